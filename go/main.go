@@ -4,6 +4,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"syscall/js"
 
 	"github.com/shiftregister-vg/logseq-odyssey/go/model"
@@ -37,6 +38,388 @@ func findMonsterJS(this js.Value, args []js.Value) interface{} {
 	return string(jsonMonster)
 }
 
+func findSpellJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	name := args[0].String()
+	return promise(func() interface{} {
+		spell, err := findSpell(name)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+		if spell == nil {
+			return nil
+		}
+
+		jsonSpell, err := json.Marshal(spell)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		return string(jsonSpell)
+	})
+}
+
+func searchSpellsJS(this js.Value, args []js.Value) interface{} {
+	var filter model.SpellFilter
+	if len(args) > 0 {
+		err := json.Unmarshal([]byte(args[0].String()), &filter)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+	}
+	return promise(func() interface{} {
+		spells, err := searchSpells(filter)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		jsonSpells, err := json.Marshal(spells)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		return string(jsonSpells)
+	})
+}
+
+func parseSpellCardJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var spell model.Spell
+	err := spell.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonSpell, err := json.Marshal(spell)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonSpell)
+}
+
+func stringifySpellToMarkdownJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var spell model.Spell
+	err := json.Unmarshal([]byte(args[0].String()), &spell)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := spell.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return md
+}
+
+func findEquipmentJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	name := args[0].String()
+	return promise(func() interface{} {
+		equipment, err := findEquipment(name)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+		if equipment == nil {
+			return nil
+		}
+
+		jsonEquipment, err := json.Marshal(equipment)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		return string(jsonEquipment)
+	})
+}
+
+func searchEquipmentJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	query, category := args[0].String(), args[1].String()
+	return promise(func() interface{} {
+		equipment, err := searchEquipment(query, category)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		jsonEquipment, err := json.Marshal(equipment)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		return string(jsonEquipment)
+	})
+}
+
+func stringifyEquipmentToMarkdownJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var equipment model.Equipment
+	err := json.Unmarshal([]byte(args[0].String()), &equipment)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := equipment.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return md
+}
+
+func addWeaponAttackJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var creature model.Creature
+	err := json.Unmarshal([]byte(args[0].String()), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	name := args[1].String()
+	return promise(func() interface{} {
+		weapon, err := findEquipment(name)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+		if weapon == nil {
+			js.Global().Get("console").Call("error", "no SRD weapon named "+name)
+			return nil
+		}
+		err = creature.AddWeaponAttack(*weapon)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		jsonCreature, err := json.Marshal(creature)
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+
+		return string(jsonCreature)
+	})
+}
+
+func searchRulesJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	jsonRules, err := json.Marshal(rulesReference.Search(args[0].String()))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonRules)
+}
+
+func listRulesJS(this js.Value, args []js.Value) interface{} {
+	kind := ""
+	if len(args) > 0 {
+		kind = args[0].String()
+	}
+	jsonRules, err := json.Marshal(rulesReference.Rules(model.RuleKind(kind)))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonRules)
+}
+
+func findRuleJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	rule, ok := rulesReference.Find(model.RuleKind(args[0].String()), args[1].String())
+	if !ok {
+		return nil
+	}
+
+	jsonRule, err := json.Marshal(rule)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonRule)
+}
+
+func refreshRulesJS(this js.Value, args []js.Value) interface{} {
+	return promise(func() interface{} {
+		err := refreshRules()
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return false
+		}
+		logError(buildSearchIndex)
+		return true
+	})
+}
+
+func setContentSourcesJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return false
+	}
+	var configs []ContentSourceConfig
+	err := json.Unmarshal([]byte(args[0].String()), &configs)
+	if err == nil {
+		err = setContentSources(configs)
+	}
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return false
+	}
+	go logError(buildSearchIndex)
+	return true
+}
+
+func setFetchCacheJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 || args[0].Type() != js.TypeObject {
+		return false
+	}
+	fetchClient.Cache = jsCache{store: args[0]}
+	return true
+}
+
+func ingestCompendiumJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var blocks []model.CompendiumBlock
+	err := json.Unmarshal([]byte(args[0].String()), &blocks)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	errs := compendium.Ingest(blocks)
+	go logError(buildSearchIndex)
+
+	jsonResult, err := json.Marshal(map[string]interface{}{
+		"errors":     errs,
+		"duplicates": compendium.Duplicates(),
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonResult)
+}
+
+func removeFromCompendiumJS(this js.Value, args []js.Value) interface{} {
+	uuids := make([]string, len(args))
+	for i, arg := range args {
+		uuids[i] = arg.String()
+	}
+	compendium.Remove(uuids...)
+	go logError(buildSearchIndex)
+	return nil
+}
+
+func searchCompendiumJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	limit := 20
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		limit = args[1].Int()
+	}
+	jsonResults, err := json.Marshal(compendium.Search(args[0].String(), limit))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonResults)
+}
+
+func findCompendiumCreatureJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	entry, ok := compendium.Find(args[0].String())
+	if !ok {
+		return nil
+	}
+
+	jsonEntry, err := json.Marshal(entry)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonEntry)
+}
+
+func searchAllJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	limit := 20
+	if len(args) > 1 && args[1].Type() == js.TypeNumber {
+		limit = args[1].Int()
+	}
+	jsonResults, err := json.Marshal(searchIndex.Load().Search(args[0].String(), limit))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonResults)
+}
+
+// promise runs f on its own goroutine and returns a Promise of its result.
+// Anything that may fetch has to go through it: blocking on the network inside
+// a callback from JavaScript deadlocks the Go runtime.
+func promise(f func() interface{}) js.Value {
+	executor := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		resolve := args[0]
+		go func() {
+			resolve.Invoke(f())
+		}()
+		return nil
+	})
+	// The Promise constructor calls the executor before it returns.
+	defer executor.Release()
+	return js.Global().Get("Promise").New(executor)
+}
+
+func logError(f func() error) {
+	if err := f(); err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+	}
+}
+
 func getModifierJS(this js.Value, args []js.Value) interface{} {
 	if len(args) == 0 {
 		return nil
@@ -66,58 +449,1187 @@ func parseInitiativeTableJS(this js.Value, args []js.Value) interface{} {
 	return string(jsonData)
 }
 
-func parseCreatureStatBlockJS(this js.Value, args []js.Value) interface{} {
+func stringifyInitiativeTableJS(this js.Value, args []js.Value) interface{} {
 	if len(args) == 0 {
 		return nil
 	}
-	content := args[0].String()
-	var creature model.Creature
-	err := creature.FromMarkdown(content)
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := it.ToMarkdown()
 	if err != nil {
 		js.Global().Get("console").Call("error", err.Error())
 		return nil
 	}
+	return md
+}
 
-	jsonCreature, err := json.Marshal(creature)
+func nextInitiativeTurnJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
 	if err != nil {
 		js.Global().Get("console").Call("error", err.Error())
 		return nil
 	}
+	recharges := it.NextTurn(nil)
 
-	return string(jsonCreature)
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"tracker":   it,
+		"recharges": recharges,
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
 }
 
-func stringifyCreatureToMarkdownJS(this js.Value, args []js.Value) interface{} {
+func nextInitiativeRoundJS(this js.Value, args []js.Value) interface{} {
 	if len(args) == 0 {
 		return nil
 	}
-	creatureJSON := args[0].String()
-	var creature model.Creature
-	err := json.Unmarshal([]byte(creatureJSON), &creature)
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
 	if err != nil {
 		js.Global().Get("console").Call("error", err.Error())
 		return nil
 	}
-	md, err := creature.ToMarkdown()
+	recharges := it.NextRound(nil)
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"tracker":   it,
+		"recharges": recharges,
+	})
 	if err != nil {
 		js.Global().Get("console").Call("error", err.Error())
 		return nil
 	}
-	return md
+
+	return string(jsonData)
 }
 
-func main() {
-	c := make(chan struct{}, 0)
-	println("Go WebAssembly Initialized")
+func sortInitiativeTrackerJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	it.Sort()
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func removeCombatantJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	err = it.RemoveCombatant(args[1].Int())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func spendCombatantResourceJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	index := args[1].Int()
+	if index < 0 || index >= len(it.Combatants) {
+		js.Global().Get("console").Call("error", "no combatant at that index")
+		return nil
+	}
+	err = it.Combatants[index].UseResource(args[2].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func addCreatureToInitiativeJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var creature model.Creature
+	err = creature.FromMarkdown(args[1].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	it.AddCreature(creature, "", args[2].Int())
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func parseCombatantSpecJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	spec := model.ParseCombatantSpec(args[0].String())
+
+	jsonData, err := json.Marshal(spec)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func addFromStatBlockJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 4 {
+		return nil
+	}
+	trackerJSON := args[0].String()
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(trackerJSON), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var spec model.CombatantSpec
+	err = json.Unmarshal([]byte(args[2].String()), &spec)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	err = it.AddFromStatBlock(args[1].String(), spec, args[3].Bool(), nil)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func parseCreatureStatBlockJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	content := args[0].String()
+	var creature model.Creature
+	err := creature.FromMarkdown(content)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonCreature, err := json.Marshal(creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonCreature)
+}
+
+func stringifyCreatureToMarkdownJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	creatureJSON := args[0].String()
+	var creature model.Creature
+	err := json.Unmarshal([]byte(creatureJSON), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := creature.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return md
+}
+
+func parseHomebreweryJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := creature.FromHomebrewery(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonCreature, err := json.Marshal(creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonCreature)
+}
+
+func stringifyCreatureToHomebreweryJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := json.Unmarshal([]byte(args[0].String()), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := creature.ToHomebrewery()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return md
+}
+
+func parseFiveEToolsJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := creature.FromFiveETools(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonCreature, err := json.Marshal(creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonCreature)
+}
+
+func stringifyCreatureToFiveEToolsJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := json.Unmarshal([]byte(args[0].String()), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	exported, err := creature.ToFiveETools()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return exported
+}
+
+func parseFoundryJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := creature.FromFoundry(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonCreature, err := json.Marshal(creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonCreature)
+}
+
+func stringifyCreatureToFoundryJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := json.Unmarshal([]byte(args[0].String()), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	exported, err := creature.ToFoundry()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return exported
+}
+
+func parseFantasyStatblockJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := creature.FromFantasyStatblock(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonCreature, err := json.Marshal(creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonCreature)
+}
+
+func stringifyCreatureToFantasyStatblockJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var creature model.Creature
+	err := json.Unmarshal([]byte(args[0].String()), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	exported, err := creature.ToFantasyStatblock()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return exported
+}
+
+func parseImprovedInitiativeJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var it model.InitiativeTracker
+	err := it.FromImprovedInitiative(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func renderCreatureHTMLJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var creature model.Creature
+	err := json.Unmarshal([]byte(args[0].String()), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	html, err := creature.ToHTML(model.HTMLLayout(args[1].String()))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return html
+}
+
+func diffCreaturesJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var from, to model.Creature
+	for i, creature := range []*model.Creature{&from, &to} {
+		if err := json.Unmarshal([]byte(args[i].String()), creature); err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+	}
+
+	jsonData, err := json.Marshal(model.DiffCreatures(from, to))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func mergeCreaturesJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	var base, ours, theirs model.Creature
+	for i, creature := range []*model.Creature{&base, &ours, &theirs} {
+		if err := json.Unmarshal([]byte(args[i].String()), creature); err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+	}
+
+	merged, conflicts := model.MergeCreatures(base, ours, theirs)
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"creature":  merged,
+		"conflicts": conflicts,
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func listCreatureTemplatesJS(this js.Value, args []js.Value) interface{} {
+	jsonData, err := json.Marshal(model.CreatureTemplates())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func applyCreatureTemplateJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var creature model.Creature
+	err := json.Unmarshal([]byte(args[0].String()), &creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	// The template is either the name of a built-in template or a template
+	// as JSON.
+	template, ok := model.FindCreatureTemplate(args[1].String())
+	if !ok {
+		if err := json.Unmarshal([]byte(args[1].String()), &template); err != nil {
+			js.Global().Get("console").Call("error", "unknown creature template "+args[1].String())
+			return nil
+		}
+	}
+
+	result, err := template.Apply(creature)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func parseCreaturePageJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	jsonCreatures, err := json.Marshal(model.ParseCreatures(args[0].String()))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonCreatures)
+}
+
+// convertCreaturePageJS converts every stat block on a page to a format:
+// markdown, 5etools, foundry, fantasyStatblock or homebrewery.
+func convertCreaturePageJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	jsonCreatures, err := json.Marshal(model.ConvertCreatures(args[0].String(), model.CreatureFormat(args[1].String())))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonCreatures)
+}
+
+// resolveCreatureStatBlockJS takes a stat block, a JSON object of the stat
+// blocks of its bases keyed by reference, and whether to flatten the result.
+func resolveCreatureStatBlockJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	var creature model.Creature
+	if err := creature.FromMarkdown(args[0].String()); err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var bases map[string]string
+	if err := json.Unmarshal([]byte(args[1].String()), &bases); err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	lookup := func(reference string) (model.Creature, error) {
+		var base model.Creature
+		content, ok := bases[reference]
+		if !ok {
+			return base, errors.New("no stat block found")
+		}
+		return base, base.FromMarkdown(content)
+	}
+
+	resolve := model.ResolveCreature
+	if args[2].Bool() {
+		resolve = model.FlattenCreature
+	}
+	resolved, err := resolve(creature, lookup)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(resolved)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func parseCharacterSheetJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var character model.Character
+	err := character.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(character)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func stringifyCharacterToMarkdownJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var character model.Character
+	err := json.Unmarshal([]byte(args[0].String()), &character)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	markdown, err := character.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return markdown
+}
+
+func addCharacterToInitiativeJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(args[0].String()), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var character model.Character
+	err = character.FromMarkdown(args[1].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	it.AddCharacter(character, args[2].Int(), nil)
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func restCharacterJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	var character model.Character
+	err := character.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var log []string
+	switch model.RestKind(args[1].String()) {
+	case model.RestShort:
+		log = character.ShortRest(args[2].Int(), nil)
+	case model.RestLong:
+		log = character.LongRest()
+	default:
+		js.Global().Get("console").Call("error", "unknown rest: "+args[1].String())
+		return nil
+	}
+
+	sheet, err := character.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"sheet": sheet,
+		"log":   log,
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func restInitiativeJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(args[0].String()), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	log, err := it.Rest(model.RestKind(args[1].String()))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"tracker": it,
+		"log":     log,
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func applyConditionJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(args[0].String()), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	index := args[1].Int()
+	if index < 0 || index >= len(it.Combatants) {
+		js.Global().Get("console").Call("error", "no combatant at that index")
+		return nil
+	}
+	condition := args[2].String()
+	rule, ok := rulesReference.Find(model.RuleCondition, condition)
+	if ok {
+		condition = rule.Name
+	}
+	it.Combatants[index].AddCondition(condition)
+
+	result := map[string]interface{}{"tracker": it}
+	if ok {
+		result["rule"] = rule
+	}
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func removeConditionJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(args[0].String()), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	index := args[1].Int()
+	if index < 0 || index >= len(it.Combatants) {
+		js.Global().Get("console").Call("error", "no combatant at that index")
+		return nil
+	}
+	it.Combatants[index].RemoveCondition(args[2].String())
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func parsePartyJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var party model.Party
+	err := party.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(party)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func stringifyPartyToMarkdownJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var party model.Party
+	err := json.Unmarshal([]byte(args[0].String()), &party)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := party.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return md
+}
+
+func addPartyToInitiativeJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var it model.InitiativeTracker
+	err := json.Unmarshal([]byte(args[0].String()), &it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var party model.Party
+	err = party.FromMarkdown(args[1].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	it.AddParty(party, nil)
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func encounterDifficultyJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var party model.Party
+	err := party.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var challenges []string
+	err = json.Unmarshal([]byte(args[1].String()), &challenges)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(party.Difficulty(challenges))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func parseEncounterJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var encounter model.Encounter
+	err := encounter.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(encounter)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func stringifyEncounterToMarkdownJS(this js.Value, args []js.Value) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	var encounter model.Encounter
+	err := json.Unmarshal([]byte(args[0].String()), &encounter)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := encounter.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	return md
+}
+
+// rateEncounterJS takes an encounter block, its party block and a JSON object
+// of its monsters' stat blocks keyed by reference. It fills in missing
+// challenge ratings and the expected difficulty.
+func rateEncounterJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 3 {
+		return nil
+	}
+	var encounter model.Encounter
+	err := encounter.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var party model.Party
+	err = party.FromMarkdown(args[1].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var statBlocks map[string]string
+	err = json.Unmarshal([]byte(args[2].String()), &statBlocks)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	encounter.FillChallenges(statBlocks)
+	rating := encounter.Rate(party)
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"encounter": encounter,
+		"rating":    rating,
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+// launchEncounterJS takes an encounter block, a JSON object of its monsters'
+// stat blocks keyed by reference, its party block (or ""), whether to roll hit
+// points and a seed for the dice.
+func launchEncounterJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 5 {
+		return nil
+	}
+	var encounter model.Encounter
+	err := encounter.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var statBlocks map[string]string
+	err = json.Unmarshal([]byte(args[1].String()), &statBlocks)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var party *model.Party
+	if args[2].String() != "" {
+		party = &model.Party{}
+		err = party.FromMarkdown(args[2].String())
+		if err != nil {
+			js.Global().Get("console").Call("error", err.Error())
+			return nil
+		}
+	}
+
+	it, err := encounter.ToInitiativeTracker(statBlocks, party, args[3].Bool(), int64(args[4].Float()))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(it)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+// rollTreasureJS takes a JSON treasure request and a seed for the dice, and
+// returns the treasure with its loot block.
+func rollTreasureJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		return nil
+	}
+	var request model.TreasureRequest
+	err := json.Unmarshal([]byte(args[0].String()), &request)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	treasure, err := model.RollTreasure(request, model.SeededRoller(int64(args[1].Float())))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := treasure.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"treasure": treasure,
+		"markdown": md,
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+// rollLootJS takes an initiative tracker block, a JSON object of its
+// combatants' stat blocks keyed by reference, whether to roll a hoard rather
+// than individual treasure and a seed for the dice. The loot is for the
+// defeated combatants.
+func rollLootJS(this js.Value, args []js.Value) interface{} {
+	if len(args) < 4 {
+		return nil
+	}
+	var it model.InitiativeTracker
+	err := it.FromMarkdown(args[0].String())
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	var statBlocks map[string]string
+	err = json.Unmarshal([]byte(args[1].String()), &statBlocks)
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	challenges := it.DefeatedChallenges(statBlocks)
+	loot, err := model.RollLoot(challenges, args[2].Bool(), model.SeededRoller(int64(args[3].Float())))
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+	md, err := loot.ToMarkdown()
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"challenges": challenges,
+		"treasure":   loot,
+		"markdown":   md,
+	})
+	if err != nil {
+		js.Global().Get("console").Call("error", err.Error())
+		return nil
+	}
+
+	return string(jsonData)
+}
+
+func main() {
+	c := make(chan struct{}, 0)
+	println("Go WebAssembly Initialized")
+	reportSkipped = func(err error) {
+		js.Global().Get("console").Call("warn", err.Error())
+	}
 
 	js.Global().Set("odysseyWasm", js.ValueOf(map[string]interface{}{
-		"greet":                       js.FuncOf(greet),
-		"findMonster":                 js.FuncOf(findMonsterJS),
-		"getModifier":                 js.FuncOf(getModifierJS),
-		"parseInitiativeTable":        js.FuncOf(parseInitiativeTableJS),
-		"parseCreatureStatBlock":      js.FuncOf(parseCreatureStatBlockJS),
-		"stringifyCreatureToMarkdown": js.FuncOf(stringifyCreatureToMarkdownJS),
+		"greet":                               js.FuncOf(greet),
+		"findMonster":                         js.FuncOf(findMonsterJS),
+		"getModifier":                         js.FuncOf(getModifierJS),
+		"parseInitiativeTable":                js.FuncOf(parseInitiativeTableJS),
+		"stringifyInitiativeTable":            js.FuncOf(stringifyInitiativeTableJS),
+		"nextInitiativeTurn":                  js.FuncOf(nextInitiativeTurnJS),
+		"nextInitiativeRound":                 js.FuncOf(nextInitiativeRoundJS),
+		"sortInitiativeTracker":               js.FuncOf(sortInitiativeTrackerJS),
+		"removeCombatant":                     js.FuncOf(removeCombatantJS),
+		"addCreatureToInitiative":             js.FuncOf(addCreatureToInitiativeJS),
+		"parseCombatantSpec":                  js.FuncOf(parseCombatantSpecJS),
+		"addFromStatBlock":                    js.FuncOf(addFromStatBlockJS),
+		"spendCombatantResource":              js.FuncOf(spendCombatantResourceJS),
+		"parseCreatureStatBlock":              js.FuncOf(parseCreatureStatBlockJS),
+		"stringifyCreatureToMarkdown":         js.FuncOf(stringifyCreatureToMarkdownJS),
+		"parseHomebrewery":                    js.FuncOf(parseHomebreweryJS),
+		"stringifyCreatureToHomebrewery":      js.FuncOf(stringifyCreatureToHomebreweryJS),
+		"parseFiveETools":                     js.FuncOf(parseFiveEToolsJS),
+		"stringifyCreatureToFiveETools":       js.FuncOf(stringifyCreatureToFiveEToolsJS),
+		"parseFoundry":                        js.FuncOf(parseFoundryJS),
+		"stringifyCreatureToFoundry":          js.FuncOf(stringifyCreatureToFoundryJS),
+		"parseFantasyStatblock":               js.FuncOf(parseFantasyStatblockJS),
+		"stringifyCreatureToFantasyStatblock": js.FuncOf(stringifyCreatureToFantasyStatblockJS),
+		"parseImprovedInitiative":             js.FuncOf(parseImprovedInitiativeJS),
+		"renderCreatureHTML":                  js.FuncOf(renderCreatureHTMLJS),
+		"diffCreatures":                       js.FuncOf(diffCreaturesJS),
+		"mergeCreatures":                      js.FuncOf(mergeCreaturesJS),
+		"listCreatureTemplates":               js.FuncOf(listCreatureTemplatesJS),
+		"applyCreatureTemplate":               js.FuncOf(applyCreatureTemplateJS),
+		"resolveCreatureStatBlock":            js.FuncOf(resolveCreatureStatBlockJS),
+		"parseCharacterSheet":                 js.FuncOf(parseCharacterSheetJS),
+		"stringifyCharacterToMarkdown":        js.FuncOf(stringifyCharacterToMarkdownJS),
+		"addCharacterToInitiative":            js.FuncOf(addCharacterToInitiativeJS),
+		"restCharacter":                       js.FuncOf(restCharacterJS),
+		"restInitiative":                      js.FuncOf(restInitiativeJS),
+		"parseParty":                          js.FuncOf(parsePartyJS),
+		"stringifyPartyToMarkdown":            js.FuncOf(stringifyPartyToMarkdownJS),
+		"addPartyToInitiative":                js.FuncOf(addPartyToInitiativeJS),
+		"encounterDifficulty":                 js.FuncOf(encounterDifficultyJS),
+		"findSpell":                           js.FuncOf(findSpellJS),
+		"searchSpells":                        js.FuncOf(searchSpellsJS),
+		"parseSpellCard":                      js.FuncOf(parseSpellCardJS),
+		"stringifySpellToMarkdown":            js.FuncOf(stringifySpellToMarkdownJS),
+		"findEquipment":                       js.FuncOf(findEquipmentJS),
+		"searchEquipment":                     js.FuncOf(searchEquipmentJS),
+		"stringifyEquipmentToMarkdown":        js.FuncOf(stringifyEquipmentToMarkdownJS),
+		"addWeaponAttack":                     js.FuncOf(addWeaponAttackJS),
+		"searchRules":                         js.FuncOf(searchRulesJS),
+		"listRules":                           js.FuncOf(listRulesJS),
+		"findRule":                            js.FuncOf(findRuleJS),
+		"refreshRules":                        js.FuncOf(refreshRulesJS),
+		"applyCondition":                      js.FuncOf(applyConditionJS),
+		"removeCondition":                     js.FuncOf(removeConditionJS),
+		"searchAll":                           js.FuncOf(searchAllJS),
+		"setContentSources":                   js.FuncOf(setContentSourcesJS),
+		"setFetchCache":                       js.FuncOf(setFetchCacheJS),
+		"ingestCompendium":                    js.FuncOf(ingestCompendiumJS),
+		"removeFromCompendium":                js.FuncOf(removeFromCompendiumJS),
+		"searchCompendium":                    js.FuncOf(searchCompendiumJS),
+		"findCompendiumCreature":              js.FuncOf(findCompendiumCreatureJS),
+		"parseCreaturePage":                   js.FuncOf(parseCreaturePageJS),
+		"convertCreaturePage":                 js.FuncOf(convertCreaturePageJS),
+		"parseEncounter":                      js.FuncOf(parseEncounterJS),
+		"stringifyEncounterToMarkdown":        js.FuncOf(stringifyEncounterToMarkdownJS),
+		"rateEncounter":                       js.FuncOf(rateEncounterJS),
+		"launchEncounter":                     js.FuncOf(launchEncounterJS),
+		"rollTreasure":                        js.FuncOf(rollTreasureJS),
+		"rollLoot":                            js.FuncOf(rollLootJS),
 	}))
+	go logError(buildSearchIndex)
 
 	<-c
 }
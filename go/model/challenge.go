@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var challengeXP = map[string]int{
+	"0": 10, "1/8": 25, "1/4": 50, "1/2": 100,
+	"1": 200, "2": 450, "3": 700, "4": 1100, "5": 1800,
+	"6": 2300, "7": 2900, "8": 3900, "9": 5000, "10": 5900,
+	"11": 7200, "12": 8400, "13": 10000, "14": 11500, "15": 13000,
+	"16": 15000, "17": 18000, "18": 20000, "19": 22000, "20": 25000,
+	"21": 33000, "22": 41000, "23": 50000, "24": 62000, "25": 75000,
+	"26": 90000, "27": 105000, "28": 120000, "29": 135000, "30": 155000,
+}
+
+// ChallengeRatingValue returns the numeric rating of a challenge such as
+// "1/4 (50 XP)", or false if it does not start with a rating.
+func ChallengeRatingValue(challenge string) (float64, bool) {
+	rating := challengeRating(challenge)
+	if numerator, denominator, ok := strings.Cut(rating, "/"); ok {
+		n, errN := strconv.Atoi(numerator)
+		d, errD := strconv.Atoi(denominator)
+		if errN != nil || errD != nil || d == 0 {
+			return 0, false
+		}
+		return float64(n) / float64(d), true
+	}
+	value, err := strconv.Atoi(rating)
+	if err != nil {
+		return 0, false
+	}
+	return float64(value), true
+}
+
+// ChallengeXP returns the experience points awarded for a challenge rating.
+func ChallengeXP(challenge string) (int, bool) {
+	xp, ok := challengeXP[challengeRating(challenge)]
+	return xp, ok
+}
+
+// ProficiencyForChallenge returns the proficiency bonus of a creature with
+// the given challenge rating.
+func ProficiencyForChallenge(challenge string) int {
+	value, ok := ChallengeRatingValue(challenge)
+	if !ok || value < 5 {
+		return 2
+	}
+	return 2 + (int(value)-1)/4
+}
+
+func challengeRating(challenge string) string {
+	rating, _, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	return rating
+}
+
+// formatChallenge writes a rating with its experience points, e.g. "1/4 (50 XP)".
+func formatChallenge(rating string) string {
+	xp, ok := challengeXP[rating]
+	if !ok {
+		return rating
+	}
+	return fmt.Sprintf("%s (%s XP)", rating, formatThousands(xp))
+}
+
+func formatChallengeValue(value float64) string {
+	switch value {
+	case 0.125:
+		return "1/8"
+	case 0.25:
+		return "1/4"
+	case 0.5:
+		return "1/2"
+	}
+	return strconv.Itoa(int(value))
+}
+
+func challengeForXP(xp int) string {
+	for rating, value := range challengeXP {
+		if value == xp {
+			return rating
+		}
+	}
+	return ""
+}
+
+func formatThousands(n int) string {
+	digits := strconv.Itoa(n)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChallenge(t *testing.T) {
+	tests := []struct {
+		challenge   string
+		value       float64
+		xp          int
+		proficiency int
+	}{
+		{challenge: "0 (10 XP)", value: 0, xp: 10, proficiency: 2},
+		{challenge: "1/4 (50 XP)", value: 0.25, xp: 50, proficiency: 2},
+		{challenge: "5", value: 5, xp: 1800, proficiency: 3},
+		{challenge: "17 (18,000 XP)", value: 17, xp: 18000, proficiency: 6},
+		{challenge: "30", value: 30, xp: 155000, proficiency: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.challenge, func(t *testing.T) {
+			value, ok := ChallengeRatingValue(tt.challenge)
+			assert.True(t, ok)
+			assert.Equal(t, tt.value, value)
+			xp, ok := ChallengeXP(tt.challenge)
+			assert.True(t, ok)
+			assert.Equal(t, tt.xp, xp)
+			assert.Equal(t, tt.proficiency, ProficiencyForChallenge(tt.challenge))
+		})
+	}
+
+	_, ok := ChallengeRatingValue("unknown")
+	assert.False(t, ok)
+	assert.Equal(t, "21 (33,000 XP)", formatChallenge("21"))
+	assert.Equal(t, "1/8", formatChallengeValue(0.125))
+}
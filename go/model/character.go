@@ -0,0 +1,500 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Character is a player character sheet.
+type Character struct {
+	Name               string        `json:"name"`
+	Player             string        `json:"player,omitempty"`
+	Species            string        `json:"species,omitempty"`
+	Background         string        `json:"background,omitempty"`
+	Alignment          string        `json:"alignment,omitempty"`
+	Classes            []ClassLevel  `json:"classes"`
+	ArmorClass         int           `json:"armorClass"`
+	Speed              int           `json:"speed"`
+	InitiativeBonus    int           `json:"initiativeBonus"`
+	HitPoints          int           `json:"hitPoints"`
+	MaxHitPoints       int           `json:"maxHitPoints"`
+	TemporaryHitPoints int           `json:"temporaryHitPoints,omitempty"`
+	HitDice            []HitDice     `json:"hitDice"`
+	Exhaustion         int           `json:"exhaustion,omitempty"`
+	AbilityScores      AbilityScores `json:"abilityScores"`
+	// SavingThrows and Skills list the proficient ones, e.g. "Wisdom" and
+	// "Arcana". Expertise doubles the proficiency bonus of a skill.
+	SavingThrows []string `json:"savingThrows,omitempty"`
+	Skills       []string `json:"skills,omitempty"`
+	Expertise    []string `json:"expertise,omitempty"`
+	Armor        string   `json:"armor,omitempty"`
+	Weapons      string   `json:"weapons,omitempty"`
+	Tools        string   `json:"tools,omitempty"`
+	Languages    string   `json:"languages,omitempty"`
+	// Resources holds spell slots and class features with limited uses.
+	Resources []Resource      `json:"resources,omitempty"`
+	Features  []Action        `json:"features,omitempty"`
+	Inventory []InventoryItem `json:"inventory,omitempty"`
+	Notes     string          `json:"notes,omitempty"`
+}
+
+type ClassLevel struct {
+	Class    string `json:"class"`
+	Subclass string `json:"subclass,omitempty"`
+	Level    int    `json:"level"`
+}
+
+// HitDice counts the hit dice of one size, e.g. the d10s of a fighter.
+type HitDice struct {
+	Die       int `json:"die"`
+	Remaining int `json:"remaining"`
+	Max       int `json:"max"`
+}
+
+type InventoryItem struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Equipped bool   `json:"equipped,omitempty"`
+}
+
+var classHitDice = map[string]int{
+	"barbarian": 12,
+	"bard":      8,
+	"cleric":    8,
+	"druid":     8,
+	"fighter":   10,
+	"monk":      8,
+	"paladin":   10,
+	"ranger":    10,
+	"rogue":     8,
+	"sorcerer":  6,
+	"warlock":   8,
+	"wizard":    6,
+}
+
+var abilityNames = []string{"Strength", "Dexterity", "Constitution", "Intelligence", "Wisdom", "Charisma"}
+
+var resourceRecharges = map[ResourceKind]string{
+	ResourceShortRest: "short rest",
+	ResourceLongRest:  "long rest",
+	ResourcePerDay:    "dawn",
+	ResourceSpellSlot: "long rest",
+}
+
+var (
+	classLevelRegex     = regexp.MustCompile(`^(.+?)(?: \((.+)\))? (\d+)$`)
+	characterHPRegex    = regexp.MustCompile(`^(\d+)(?:\s*/\s*(\d+))?(?: \(\+(\d+) temp\))?$`)
+	hitDiceRegex        = regexp.MustCompile(`^(\d+)/(\d+) d(\d+)$`)
+	resourceUsesRegex   = regexp.MustCompile(`^(\d+)/(\d+)$`)
+	spellSlotNameRegex  = regexp.MustCompile(`^(\d)(?:st|nd|rd|th)-level slots$`)
+	proficientRegex     = regexp.MustCompile(`^(.+?) [+-]\d+( \(expertise\))?$`)
+	characterSpeedRegex = regexp.MustCompile(`^(\d+)`)
+	sectionHeaderRegex  = regexp.MustCompile(`^\*\*([A-Z\s]+)\*\*$`)
+)
+
+// ClassHitDie returns the size of a class's hit die, defaulting to a d8 for
+// classes outside the SRD.
+func ClassHitDie(class string) int {
+	if die, ok := classHitDice[strings.ToLower(class)]; ok {
+		return die
+	}
+	return 8
+}
+
+// Level is the character's total level across its classes.
+func (character *Character) Level() int {
+	level := 0
+	for _, class := range character.Classes {
+		level += class.Level
+	}
+	return level
+}
+
+func (character *Character) ProficiencyBonus() int {
+	return 2 + (max(character.Level(), 1)-1)/4
+}
+
+// Score returns an ability score by its full name or three-letter
+// abbreviation.
+func (scores AbilityScores) Score(ability string) int {
+	values := []int{scores.Strength, scores.Dexterity, scores.Constitution, scores.Intelligence, scores.Wisdom, scores.Charisma}
+	for i, name := range abilityNames {
+		if len(ability) >= 3 && strings.HasPrefix(strings.ToLower(name), strings.ToLower(ability)) {
+			return values[i]
+		}
+	}
+	return 10
+}
+
+// abilityName returns the full ability name that name abbreviates, such as
+// "Wisdom" for "wis", ignoring case.
+func abilityName(name string) (string, bool) {
+	if len(name) < 3 {
+		return "", false
+	}
+	for _, ability := range abilityNames {
+		if strings.HasPrefix(strings.ToLower(ability), strings.ToLower(name)) {
+			return ability, true
+		}
+	}
+	return "", false
+}
+
+func (character *Character) SavingThrowBonus(ability string) int {
+	bonus := modifier(character.AbilityScores.Score(ability))
+	ability, _ = abilityName(ability)
+	for _, save := range character.SavingThrows {
+		if name, ok := abilityName(save); ok && name == ability {
+			bonus += character.ProficiencyBonus()
+		}
+	}
+	return bonus
+}
+
+func (character *Character) SkillBonus(skill string) int {
+	ability, ok := SkillAbility(skill)
+	if !ok {
+		ability = "Wisdom"
+	}
+	bonus := modifier(character.AbilityScores.Score(ability))
+	for _, proficient := range character.Skills {
+		if strings.EqualFold(proficient, skill) {
+			bonus += character.ProficiencyBonus()
+		}
+	}
+	for _, expert := range character.Expertise {
+		if strings.EqualFold(expert, skill) {
+			bonus += character.ProficiencyBonus()
+		}
+	}
+	return bonus
+}
+
+// PassiveScore is 10 plus the skill bonus, e.g. passive Perception.
+func (character *Character) PassiveScore(skill string) int {
+	return 10 + character.SkillBonus(skill)
+}
+
+// MaxHitDice lists the hit dice the character's classes grant, largest
+// first.
+func (character *Character) MaxHitDice() []HitDice {
+	counts := map[int]int{}
+	for _, class := range character.Classes {
+		counts[ClassHitDie(class.Class)] += class.Level
+	}
+	var dice []HitDice
+	for die, count := range counts {
+		dice = append(dice, HitDice{Die: die, Remaining: count, Max: count})
+	}
+	sort.Slice(dice, func(i, j int) bool { return dice[i].Die > dice[j].Die })
+	return dice
+}
+
+func (character *Character) FromMarkdown(content string) error {
+	*character = Character{AbilityScores: AbilityScores{10, 10, 10, 10, 10, 10}}
+	if content == "" {
+		return nil
+	}
+
+	var currentSection string
+	var sectionContent []string
+	processSection := func() {
+		text := strings.TrimSpace(strings.Join(sectionContent, "\n"))
+		switch currentSection {
+		case "FEATURES":
+			character.Features = parseActions(text)
+		case "NOTES":
+			character.Notes = text
+		}
+		sectionContent = nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if match := sectionHeaderRegex.FindStringSubmatch(trimmedLine); len(match) > 1 {
+			processSection()
+			currentSection = strings.TrimSpace(match[1])
+			continue
+		}
+		if strings.HasPrefix(trimmedLine, "### ") {
+			character.Name = strings.TrimSpace(strings.TrimPrefix(trimmedLine, "### "))
+			continue
+		}
+		if currentSection != "" && trimmedLine != "---" {
+			sectionContent = append(sectionContent, line)
+		}
+	}
+	processSection()
+
+	hasInitiative := false
+	for _, table := range ParseTables(content) {
+		switch {
+		case table.HasColumns("Property", "Value"):
+			for i := range table.Rows {
+				property := strings.Trim(table.Get(i, "Property"), "* ")
+				character.setProperty(property, table.Get(i, "Value"))
+				hasInitiative = hasInitiative || property == "Initiative"
+			}
+		case table.HasColumns("STR", "DEX") && len(table.Rows) > 0:
+			creature := Creature{AbilityScores: character.AbilityScores}
+			creature.setAbilityScores(table)
+			character.AbilityScores = creature.AbilityScores
+		case table.HasColumns("Resource", "Uses"):
+			for i := range table.Rows {
+				character.Resources = append(character.Resources, parseCharacterResource(table.Get(i, "Resource"), table.Get(i, "Uses"), table.Get(i, "Recharge")))
+			}
+		case table.HasColumns("Item", "Quantity"):
+			for i := range table.Rows {
+				quantity, err := strconv.Atoi(table.Get(i, "Quantity"))
+				if err != nil {
+					quantity = 1
+				}
+				character.Inventory = append(character.Inventory, InventoryItem{
+					Name:     table.Get(i, "Item"),
+					Quantity: quantity,
+					Equipped: strings.EqualFold(table.Get(i, "Equipped"), "yes"),
+				})
+			}
+		}
+	}
+
+	if character.HitDice == nil {
+		character.HitDice = character.MaxHitDice()
+	}
+	if !hasInitiative {
+		character.InitiativeBonus = modifier(character.AbilityScores.Dexterity)
+	}
+	return nil
+}
+
+func (character *Character) setProperty(property string, value string) {
+	switch property {
+	case "Player":
+		character.Player = value
+	case "Class":
+		for _, part := range splitList(value) {
+			if match := classLevelRegex.FindStringSubmatch(part); len(match) > 3 {
+				level, _ := strconv.Atoi(match[3])
+				character.Classes = append(character.Classes, ClassLevel{Class: match[1], Subclass: match[2], Level: level})
+			}
+		}
+	case "Species":
+		character.Species = value
+	case "Background":
+		character.Background = value
+	case "Alignment":
+		character.Alignment = value
+	case "Armor Class":
+		character.ArmorClass, _ = strconv.Atoi(value)
+	case "Speed":
+		if match := characterSpeedRegex.FindStringSubmatch(value); len(match) > 1 {
+			character.Speed, _ = strconv.Atoi(match[1])
+		}
+	case "Initiative":
+		character.InitiativeBonus, _ = strconv.Atoi(value)
+	case "Hit Points":
+		if match := characterHPRegex.FindStringSubmatch(value); len(match) > 1 {
+			character.HitPoints, _ = strconv.Atoi(match[1])
+			character.MaxHitPoints = character.HitPoints
+			if match[2] != "" {
+				character.MaxHitPoints, _ = strconv.Atoi(match[2])
+			}
+			character.TemporaryHitPoints, _ = strconv.Atoi(match[3])
+		}
+	case "Hit Dice":
+		character.HitDice = []HitDice{}
+		for _, part := range splitList(value) {
+			if match := hitDiceRegex.FindStringSubmatch(part); len(match) > 3 {
+				remaining, _ := strconv.Atoi(match[1])
+				total, _ := strconv.Atoi(match[2])
+				die, _ := strconv.Atoi(match[3])
+				character.HitDice = append(character.HitDice, HitDice{Die: die, Remaining: remaining, Max: total})
+			}
+		}
+	case "Exhaustion":
+		character.Exhaustion, _ = strconv.Atoi(value)
+	case "Saving Throws":
+		for _, save := range splitList(value) {
+			if match := proficientRegex.FindStringSubmatch(save); len(match) > 1 {
+				if name, ok := abilityName(match[1]); ok {
+					character.SavingThrows = append(character.SavingThrows, name)
+				}
+			}
+		}
+	case "Skills":
+		for _, skill := range splitList(value) {
+			if match := proficientRegex.FindStringSubmatch(skill); len(match) > 2 {
+				character.Skills = append(character.Skills, match[1])
+				if match[2] != "" {
+					character.Expertise = append(character.Expertise, match[1])
+				}
+			}
+		}
+	case "Armor":
+		character.Armor = value
+	case "Weapons":
+		character.Weapons = value
+	case "Tools":
+		character.Tools = value
+	case "Languages":
+		character.Languages = value
+	}
+}
+
+func parseCharacterResource(name string, uses string, recharge string) Resource {
+	resource := Resource{Name: name, Kind: ResourceLongRest}
+	if match := resourceUsesRegex.FindStringSubmatch(uses); len(match) > 2 {
+		resource.Remaining, _ = strconv.Atoi(match[1])
+		resource.Max, _ = strconv.Atoi(match[2])
+	}
+	if match := spellSlotNameRegex.FindStringSubmatch(name); len(match) > 1 {
+		resource.Kind = ResourceSpellSlot
+		resource.Level, _ = strconv.Atoi(match[1])
+		return resource
+	}
+	for kind, text := range resourceRecharges {
+		if kind != ResourceSpellSlot && strings.EqualFold(text, recharge) {
+			resource.Kind = kind
+		}
+	}
+	return resource
+}
+
+func (character *Character) ToMarkdown() (string, error) {
+	md := fmt.Sprintf("### %s\n", character.Name)
+	md += "---\n"
+
+	properties := Table{Header: []string{"Property", "Value"}, Align: []Alignment{AlignLeft, AlignLeft}}
+	addRow := func(name, value string) {
+		if value != "" {
+			properties.AddRow(fmt.Sprintf("**%s**", name), value)
+		}
+	}
+	addRow("Player", character.Player)
+	var classes []string
+	for _, class := range character.Classes {
+		name := class.Class
+		if class.Subclass != "" {
+			name += fmt.Sprintf(" (%s)", class.Subclass)
+		}
+		classes = append(classes, fmt.Sprintf("%s %d", name, class.Level))
+	}
+	addRow("Class", strings.Join(classes, ", "))
+	addRow("Species", character.Species)
+	addRow("Background", character.Background)
+	addRow("Alignment", character.Alignment)
+	addRow("Armor Class", optionalInt(character.ArmorClass))
+	hitPoints := fmt.Sprintf("%d/%d", character.HitPoints, character.MaxHitPoints)
+	if character.TemporaryHitPoints > 0 {
+		hitPoints += fmt.Sprintf(" (+%d temp)", character.TemporaryHitPoints)
+	}
+	addRow("Hit Points", hitPoints)
+	var hitDice []string
+	for _, dice := range character.HitDice {
+		hitDice = append(hitDice, fmt.Sprintf("%d/%d d%d", dice.Remaining, dice.Max, dice.Die))
+	}
+	addRow("Hit Dice", strings.Join(hitDice, ", "))
+	addRow("Exhaustion", optionalInt(character.Exhaustion))
+	if character.Speed != 0 {
+		addRow("Speed", fmt.Sprintf("%d ft.", character.Speed))
+	}
+	// Without a row the bonus follows the Dexterity modifier.
+	if character.InitiativeBonus != modifier(character.AbilityScores.Dexterity) {
+		addRow("Initiative", fmt.Sprintf("%+d", character.InitiativeBonus))
+	}
+	addRow("Proficiency Bonus", fmt.Sprintf("%+d", character.ProficiencyBonus()))
+	var saves []string
+	for _, save := range character.SavingThrows {
+		if name, ok := abilityName(save); ok {
+			saves = append(saves, fmt.Sprintf("%s %+d", name[:3], character.SavingThrowBonus(name)))
+		}
+	}
+	addRow("Saving Throws", strings.Join(saves, ", "))
+	var skills []string
+	for _, skill := range character.Skills {
+		entry := fmt.Sprintf("%s %+d", skill, character.SkillBonus(skill))
+		for _, expert := range character.Expertise {
+			if strings.EqualFold(expert, skill) {
+				entry += " (expertise)"
+			}
+		}
+		skills = append(skills, entry)
+	}
+	addRow("Skills", strings.Join(skills, ", "))
+	addRow("Passive Perception", strconv.Itoa(character.PassiveScore("Perception")))
+	addRow("Armor", character.Armor)
+	addRow("Weapons", character.Weapons)
+	addRow("Tools", character.Tools)
+	addRow("Languages", character.Languages)
+	md += properties.String() + "\n"
+	md += "---\n"
+
+	creature := Creature{AbilityScores: character.AbilityScores}
+	abilities := creature.abilityTable()
+	md += abilities.String() + "\n"
+	md += "---\n"
+
+	if len(character.Resources) > 0 {
+		resources := Table{Header: []string{"Resource", "Uses", "Recharge"}, Align: []Alignment{AlignLeft, AlignCenter, AlignLeft}}
+		for _, resource := range character.Resources {
+			resources.AddRow(resource.Name, fmt.Sprintf("%d/%d", resource.Remaining, resource.Max), resourceRecharges[resource.Kind])
+		}
+		md += fmt.Sprintf("\n**RESOURCES**\n---\n%s\n", resources.String())
+	}
+	if len(character.Features) > 0 {
+		var features []string
+		for _, feature := range character.Features {
+			features = append(features, fmt.Sprintf("***%s.*** %s", feature.Name, feature.Description))
+		}
+		md += fmt.Sprintf("\n**FEATURES**\n---\n%s\n", strings.Join(features, "\n\n"))
+	}
+	if len(character.Inventory) > 0 {
+		inventory := Table{Header: []string{"Item", "Quantity", "Equipped"}, Align: []Alignment{AlignLeft, AlignCenter, AlignCenter}}
+		for _, item := range character.Inventory {
+			equipped := ""
+			if item.Equipped {
+				equipped = "yes"
+			}
+			inventory.AddRow(item.Name, strconv.Itoa(item.Quantity), equipped)
+		}
+		md += fmt.Sprintf("\n**INVENTORY**\n---\n%s\n", inventory.String())
+	}
+	if character.Notes != "" {
+		md += fmt.Sprintf("\n**NOTES**\n---\n%s\n", character.Notes)
+	}
+
+	return strings.TrimSpace(md), nil
+}
+
+// ToCombatant makes the character ready for the initiative tracker, carrying
+// over its damage, initiative bonus and limited-use resources.
+func (character *Character) ToCombatant(initiative int) Combatant {
+	combatant := Combatant{
+		Name:            character.Name,
+		Initiative:      initiative,
+		Damage:          max(character.MaxHitPoints-character.HitPoints, 0),
+		ArmorClass:      character.ArmorClass,
+		HitPoints:       character.MaxHitPoints,
+		InitiativeBonus: character.InitiativeBonus,
+	}
+	if len(character.Resources) > 0 {
+		combatant.Resources = append([]Resource{}, character.Resources...)
+	}
+	return combatant
+}
+
+// AddCharacter adds the character to the tracker. An initiative of 0 is
+// rolled as a d20 plus the character's initiative bonus.
+func (it *InitiativeTracker) AddCharacter(character Character, initiative int, roll Roller) {
+	if roll == nil {
+		roll = RollDie
+	}
+	if initiative == 0 {
+		initiative = roll(20) + character.InitiativeBonus
+	}
+	it.Combatants = append(it.Combatants, character.ToCombatant(initiative))
+	it.Sort()
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacterFromMarkdown(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "characters", "mira.md"))
+	assert.NoError(t, err)
+
+	var character Character
+	assert.NoError(t, character.FromMarkdown(string(content)))
+
+	assert.Equal(t, "Mira Thorne", character.Name)
+	assert.Equal(t, []ClassLevel{{"Fighter", "Eldritch Knight", 2}, {"Wizard", "Evocation", 3}}, character.Classes)
+	assert.Equal(t, 5, character.Level())
+	assert.Equal(t, 3, character.ProficiencyBonus())
+	assert.Equal(t, 31, character.HitPoints)
+	assert.Equal(t, 38, character.MaxHitPoints)
+	assert.Equal(t, 4, character.TemporaryHitPoints)
+	assert.Equal(t, []HitDice{{Die: 10, Remaining: 1, Max: 2}, {Die: 6, Remaining: 3, Max: 3}}, character.HitDice)
+	assert.Equal(t, 4, character.InitiativeBonus)
+	assert.Equal(t, []string{"Strength", "Constitution"}, character.SavingThrows)
+	assert.Equal(t, []string{"Arcana", "History", "Perception", "Investigation"}, character.Skills)
+	assert.Equal(t, []string{"Investigation"}, character.Expertise)
+	assert.Equal(t, 16, character.AbilityScores.Intelligence)
+	assert.Equal(t, []Resource{
+		{Name: "1st-level slots", Kind: ResourceSpellSlot, Remaining: 2, Max: 4, Level: 1},
+		{Name: "2nd-level slots", Kind: ResourceSpellSlot, Remaining: 2, Max: 2, Level: 2},
+		{Name: "Second Wind", Kind: ResourceShortRest, Remaining: 0, Max: 1},
+		{Name: "Arcane Recovery", Kind: ResourceLongRest, Remaining: 1, Max: 1},
+	}, character.Resources)
+	assert.Equal(t, []string{"Second Wind", "Sculpt Spells"}, actionNames(character.Features))
+	assert.Equal(t, []InventoryItem{{"Longsword", 1, true}, {"Spellbook", 1, false}, {"Rations", 5, false}}, character.Inventory)
+	assert.Equal(t, "Owes the Cobalt Soul a favour.", character.Notes)
+
+	assert.Equal(t, 4, character.SavingThrowBonus("Strength"))
+	assert.Equal(t, 2, character.SavingThrowBonus("Dexterity"))
+	assert.Equal(t, 9, character.SkillBonus("Investigation"))
+	assert.Equal(t, 2, character.SkillBonus("Stealth"))
+	assert.Equal(t, 14, character.PassiveScore("Perception"))
+}
+
+func TestCharacterMarkdownRoundTrip(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "characters", "mira.md"))
+	assert.NoError(t, err)
+
+	var character Character
+	assert.NoError(t, character.FromMarkdown(string(content)))
+	markdown, err := character.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(content)), markdown)
+
+	var reparsed Character
+	assert.NoError(t, reparsed.FromMarkdown(markdown))
+	assert.Equal(t, character, reparsed)
+}
+
+func TestCharacterDefaults(t *testing.T) {
+	var character Character
+	assert.NoError(t, character.FromMarkdown(`### Brann
+---
+| Property | Value |
+| :--- | :--- |
+| **Class** | Barbarian 4 |
+| **Hit Points** | 45 |
+| **Saving Throws** | str +5, wis +3, Luck +1 |
+---
+| STR | DEX | CON | INT | WIS | CHA |
+| :-: | :-: | :-: | :-: | :-: | :-: |
+| 17 (+3) | 14 (+2) | 16 (+3) | 8 (-1) | 12 (+1) | 10 (+0) |`))
+
+	assert.Equal(t, 45, character.HitPoints)
+	assert.Equal(t, 45, character.MaxHitPoints)
+	assert.Equal(t, []HitDice{{Die: 12, Remaining: 4, Max: 4}}, character.HitDice)
+	assert.Equal(t, 2, character.InitiativeBonus, "initiative defaults to the Dexterity modifier")
+	assert.Equal(t, 2, character.ProficiencyBonus())
+	assert.Equal(t, []string{"Strength", "Wisdom"}, character.SavingThrows)
+
+	markdown, err := character.ToMarkdown()
+	assert.NoError(t, err)
+	assert.NotContains(t, markdown, "**Initiative**", "a bonus that matches Dexterity is not written")
+	var raised Character
+	assert.NoError(t, raised.FromMarkdown(strings.Replace(markdown, "| 14 (+2) |", "| 18 (+4) |", 1)))
+	assert.Equal(t, 4, raised.InitiativeBonus)
+}
+
+func TestCharacterSavingThrowsToMarkdown(t *testing.T) {
+	character := Character{
+		Name:          "Brann",
+		AbilityScores: AbilityScores{Strength: 17, Dexterity: 14, Constitution: 16, Intelligence: 8, Wisdom: 12, Charisma: 10},
+		SavingThrows:  []string{"strength", "wis", "X", ""},
+	}
+	markdown, err := character.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, markdown, "| **Saving Throws** | Str +5, Wis +3 |")
+}
+
+func TestCharacterToCombatant(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "characters", "mira.md"))
+	assert.NoError(t, err)
+	var character Character
+	assert.NoError(t, character.FromMarkdown(string(content)))
+
+	combatant := character.ToCombatant(17)
+	assert.Equal(t, "Mira Thorne", combatant.Name)
+	assert.Equal(t, 17, combatant.Initiative)
+	assert.Equal(t, 7, combatant.Damage)
+	assert.Equal(t, 16, combatant.ArmorClass)
+	assert.Equal(t, 38, combatant.HitPoints)
+	assert.Equal(t, 4, combatant.InitiativeBonus)
+	assert.Equal(t, character.Resources, combatant.Resources)
+
+	assert.NoError(t, combatant.UseResource("1st-level slots"))
+	assert.Equal(t, 2, character.Resources[0].Remaining, "the combatant should not share resources with the sheet")
+}
+
+func TestInitiativeTrackerAddCharacter(t *testing.T) {
+	it := InitiativeTracker{Round: 1, Combatants: []Combatant{{Name: "Goblin", Initiative: 12}}}
+	character := Character{Name: "Brann", InitiativeBonus: 2, HitPoints: 45, MaxHitPoints: 45}
+
+	it.AddCharacter(character, 0, func(sides int) int { return 15 })
+	it.AddCharacter(Character{Name: "Mira"}, 5, nil)
+
+	assert.Equal(t, []string{"Brann", "Goblin", "Mira"}, []string{it.Combatants[0].Name, it.Combatants[1].Name, it.Combatants[2].Name})
+	assert.Equal(t, 17, it.Combatants[0].Initiative)
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CombatantSpec describes combatants to pull from a stat block, as typed into
+// the tracker, e.g. "Goblin x4" or "((block-uuid)) x2".
+type CombatantSpec struct {
+	Reference string `json:"reference"`
+	Count     int    `json:"count"`
+}
+
+var (
+	combatantCountRegex = regexp.MustCompile(`^(.*?)\s*[x×]\s*(\d+)$`)
+	pageReferenceRegex  = regexp.MustCompile(`^\[\[(.+)\]\]$`)
+	blockReferenceRegex = regexp.MustCompile(`^\(\((.+)\)\)$`)
+)
+
+func ParseCombatantSpec(input string) CombatantSpec {
+	spec := CombatantSpec{Reference: strings.TrimSpace(input), Count: 1}
+	if match := combatantCountRegex.FindStringSubmatch(spec.Reference); len(match) > 2 {
+		spec.Reference = strings.TrimSpace(match[1])
+		spec.Count, _ = strconv.Atoi(match[2])
+	}
+	if !pageReferenceRegex.MatchString(spec.Reference) && !blockReferenceRegex.MatchString(spec.Reference) {
+		spec.Reference = "[[" + spec.Reference + "]]"
+	}
+	return spec
+}
+
+// ReferenceTarget returns the page name or block UUID a reference points to.
+func ReferenceTarget(reference string) (string, bool) {
+	if match := blockReferenceRegex.FindStringSubmatch(reference); len(match) > 1 {
+		return match[1], true
+	}
+	if match := pageReferenceRegex.FindStringSubmatch(reference); len(match) > 1 {
+		return match[1], false
+	}
+	return reference, false
+}
+
+// ResolveCombatants instantiates spec.Count combatants from stat block
+// markdown, numbering their names when there is more than one and using
+// either average or rolled hit points.
+func ResolveCombatants(statBlock string, spec CombatantSpec, rollHP bool, roll Roller) ([]Combatant, error) {
+	creature, err := parseReferencedCreature(statBlock, spec.Reference)
+	if err != nil {
+		return nil, err
+	}
+	return resolveCombatants(creature, spec, 1, rollHP, roll)
+}
+
+// parseReferencedCreature parses a stat block, naming the creature after the
+// reference when the stat block has no name of its own.
+func parseReferencedCreature(statBlock string, reference string) (Creature, error) {
+	var creature Creature
+	if err := creature.FromMarkdown(statBlock); err != nil {
+		return creature, err
+	}
+	if creature.Name == "" {
+		creature.Name, _ = ReferenceTarget(reference)
+	}
+	return creature, nil
+}
+
+func resolveCombatants(creature Creature, spec CombatantSpec, first int, rollHP bool, roll Roller) ([]Combatant, error) {
+	if spec.Count < 1 {
+		return nil, errors.New("combatant count must be at least 1")
+	}
+	if roll == nil {
+		roll = RollDie
+	}
+
+	average, hitDice, err := ParseHitPoints(creature.HitPoints)
+	if err != nil && creature.HitPoints != "" {
+		return nil, err
+	}
+
+	combatants := []Combatant{}
+	for i := 0; i < spec.Count; i++ {
+		name := creature.Name
+		if spec.Count > 1 || first > 1 {
+			name = fmt.Sprintf("%s %d", creature.Name, first+i)
+		}
+		combatant := NewCombatant(creature, name, 0)
+		combatant.Reference = spec.Reference
+		combatant.Initiative = roll(20) + combatant.InitiativeBonus
+		combatant.HitPoints = average
+		if rollHP && hitDice != nil {
+			combatant.HitPoints = hitDice.Roll(roll)
+			if combatant.HitPoints < 1 {
+				combatant.HitPoints = 1
+			}
+		}
+		combatants = append(combatants, combatant)
+	}
+	return combatants, nil
+}
+
+// AddFromStatBlock resolves combatants from a stat block and adds them to the
+// tracker, continuing the numbering of combatants already linked to the same
+// stat block.
+func (it *InitiativeTracker) AddFromStatBlock(statBlock string, spec CombatantSpec, rollHP bool, roll Roller) error {
+	existing := 0
+	for _, c := range it.Combatants {
+		if c.Reference == spec.Reference && !c.Lair {
+			existing++
+		}
+	}
+
+	creature, err := parseReferencedCreature(statBlock, spec.Reference)
+	if err != nil {
+		return err
+	}
+	combatants, err := resolveCombatants(creature, spec, existing+1, rollHP, roll)
+	if err != nil {
+		return err
+	}
+	it.Combatants = append(it.Combatants, combatants...)
+	it.addLair(creature)
+	it.Sort()
+	return nil
+}
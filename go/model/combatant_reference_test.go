@@ -0,0 +1,98 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const goblinStatBlock = `
+### Goblin
+Small humanoid (goblinoid), neutral evil
+---
+| Property | Value |
+| :--- | :--- |
+| **Armor Class** | 15 |
+| **Hit Points** | 7 (2d6) |
+| **Speed** | 30ft. |
+| **Challenge** | 1/4 (50 XP) |
+---
+| STR | DEX | CON | INT | WIS | CHA |
+| :-: | :-: | :-: | :-: | :-: | :-: |
+| 8 (-1) | 14 (+2) | 10 (+0) | 10 (+0) | 8 (-1) | 8 (-1) |
+---
+
+**ACTIONS**
+---
+***Scimitar.*** *Melee Weapon Attack:* +4 to hit, reach 5 ft., one target. *Hit:* 5 (1d6 + 2) slashing damage.
+
+***Shortbow.*** *Ranged Weapon Attack:* +4 to hit, range 80/320 ft., one target. *Hit:* 5 (1d6 + 2) piercing damage.
+`
+
+func TestParseCombatantSpec(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected CombatantSpec
+	}{
+		{input: "Goblin x4", expected: CombatantSpec{Reference: "[[Goblin]]", Count: 4}},
+		{input: "[[Goblin Boss]]", expected: CombatantSpec{Reference: "[[Goblin Boss]]", Count: 1}},
+		{input: "((64f1c0de-1234-4abc-9def-000000000000)) ×2", expected: CombatantSpec{Reference: "((64f1c0de-1234-4abc-9def-000000000000))", Count: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseCombatantSpec(tt.input))
+		})
+	}
+
+	target, isBlock := ReferenceTarget("((64f1c0de-1234-4abc-9def-000000000000))")
+	assert.Equal(t, "64f1c0de-1234-4abc-9def-000000000000", target)
+	assert.True(t, isBlock)
+	target, isBlock = ReferenceTarget("[[Goblin]]")
+	assert.Equal(t, "Goblin", target)
+	assert.False(t, isBlock)
+}
+
+func TestResolveCombatants(t *testing.T) {
+	roll := func(sides int) int { return sides }
+
+	combatants, err := ResolveCombatants(goblinStatBlock, ParseCombatantSpec("Goblin x2"), false, roll)
+	assert.NoError(t, err)
+	assert.Len(t, combatants, 2)
+	assert.Equal(t, "Goblin 1", combatants[0].Name)
+	assert.Equal(t, "Goblin 2", combatants[1].Name)
+	assert.Equal(t, "[[Goblin]]", combatants[0].Reference)
+	assert.Equal(t, 15, combatants[0].ArmorClass)
+	assert.Equal(t, 7, combatants[0].HitPoints)
+	assert.Equal(t, 2, combatants[0].InitiativeBonus)
+	assert.Equal(t, 22, combatants[0].Initiative)
+	assert.Equal(t, []string{"Scimitar", "Shortbow"}, combatants[0].Actions)
+
+	combatants, err = ResolveCombatants(goblinStatBlock, ParseCombatantSpec("Goblin"), true, roll)
+	assert.NoError(t, err)
+	assert.Equal(t, "Goblin", combatants[0].Name)
+	assert.Equal(t, 12, combatants[0].HitPoints)
+
+	_, err = ResolveCombatants(goblinStatBlock, CombatantSpec{Reference: "[[Goblin]]"}, false, roll)
+	assert.Error(t, err)
+}
+
+func TestAddFromStatBlockPreservesReference(t *testing.T) {
+	roll := func(sides int) int { return 10 }
+	it := InitiativeTracker{Round: 1}
+
+	assert.NoError(t, it.AddFromStatBlock(goblinStatBlock, ParseCombatantSpec("Goblin x2"), false, roll))
+	assert.NoError(t, it.AddFromStatBlock(goblinStatBlock, ParseCombatantSpec("Goblin"), false, roll))
+	assert.Equal(t, []string{"Goblin 1", "Goblin 2", "Goblin 3"}, combatantNames(it.Combatants))
+
+	markdown, err := it.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, markdown, "| Name | Initiative | Damage | Creature | AC | HP |")
+	assert.Contains(t, markdown, "| Goblin 1 | 12 | 0 | [[Goblin]] | 15 | 7 |")
+
+	var parsed InitiativeTracker
+	assert.NoError(t, parsed.FromMarkdown(markdown))
+	assert.Equal(t, "[[Goblin]]", parsed.Combatants[2].Reference)
+	assert.Equal(t, 15, parsed.Combatants[2].ArmorClass)
+	assert.Equal(t, 7, parsed.Combatants[2].HitPoints)
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// CompendiumBlock is a block from the graph holding a stat block.
+type CompendiumBlock struct {
+	UUID    string `json:"uuid"`
+	Page    string `json:"page"`
+	Content string `json:"content"`
+}
+
+// CompendiumEntry is a creature in the compendium and the block it was read
+// from.
+type CompendiumEntry struct {
+	UUID     string   `json:"uuid"`
+	Page     string   `json:"page"`
+	Creature Creature `json:"creature"`
+}
+
+// CompendiumError reports a block that could not be read as a stat block.
+type CompendiumError struct {
+	UUID  string `json:"uuid"`
+	Page  string `json:"page"`
+	Error string `json:"error"`
+}
+
+// CompendiumDuplicate is a creature name used by more than one block.
+type CompendiumDuplicate struct {
+	Name    string            `json:"name"`
+	Entries []CompendiumEntry `json:"entries"`
+}
+
+// Compendium holds the homebrew creatures kept in the graph, keyed by the
+// block each came from, and searches them the way the SRD is searched.
+type Compendium struct {
+	entries map[string]CompendiumEntry
+	index   *SearchIndex
+}
+
+func NewCompendium() *Compendium {
+	return &Compendium{entries: map[string]CompendiumEntry{}, index: NewSearchIndex(nil)}
+}
+
+// Ingest reads stat blocks from blocks, replacing whatever was read from the
+// same blocks before. A block that no longer holds a stat block is dropped
+// and reported.
+func (compendium *Compendium) Ingest(blocks []CompendiumBlock) []CompendiumError {
+	errs := []CompendiumError{}
+	for _, block := range blocks {
+		var creature Creature
+		if err := creature.FromMarkdown(block.Content); err != nil {
+			errs = append(errs, CompendiumError{UUID: block.UUID, Page: block.Page, Error: err.Error()})
+			delete(compendium.entries, block.UUID)
+			continue
+		}
+		if creature.Name == "" {
+			errs = append(errs, CompendiumError{UUID: block.UUID, Page: block.Page, Error: "no stat block name"})
+			delete(compendium.entries, block.UUID)
+			continue
+		}
+		compendium.entries[block.UUID] = CompendiumEntry{UUID: block.UUID, Page: block.Page, Creature: creature}
+	}
+	compendium.reindex()
+	return errs
+}
+
+// Remove drops the creatures read from the given blocks.
+func (compendium *Compendium) Remove(uuids ...string) {
+	for _, uuid := range uuids {
+		delete(compendium.entries, uuid)
+	}
+	compendium.reindex()
+}
+
+// Entries lists the compendium by creature name, then page.
+func (compendium *Compendium) Entries() []CompendiumEntry {
+	entries := make([]CompendiumEntry, 0, len(compendium.entries))
+	for _, entry := range compendium.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Creature.Name != entries[j].Creature.Name {
+			return entries[i].Creature.Name < entries[j].Creature.Name
+		}
+		if entries[i].Page != entries[j].Page {
+			return entries[i].Page < entries[j].Page
+		}
+		return entries[i].UUID < entries[j].UUID
+	})
+	return entries
+}
+
+// Find looks up a creature by name, ignoring case. If the name is used more
+// than once, the first by page is returned.
+func (compendium *Compendium) Find(name string) (CompendiumEntry, bool) {
+	for _, entry := range compendium.Entries() {
+		if strings.EqualFold(entry.Creature.Name, strings.TrimSpace(name)) {
+			return entry, true
+		}
+	}
+	return CompendiumEntry{}, false
+}
+
+// Search finds creatures as SearchIndex.Search does. Each result's
+// reference is the UUID of the creature's block.
+func (compendium *Compendium) Search(query string, limit int) []SearchResult {
+	return compendium.index.Search(query, limit)
+}
+
+// Documents are the compendium's creatures, for a search index that covers
+// other content too.
+func (compendium *Compendium) Documents() []Document {
+	documents := []Document{}
+	for _, entry := range compendium.Entries() {
+		document := entry.Creature.Document()
+		document.Reference = entry.UUID
+		documents = append(documents, document)
+	}
+	return documents
+}
+
+// Duplicates lists the names, ignoring case, that more than one block
+// defines, e.g. the same creature written up on two pages.
+func (compendium *Compendium) Duplicates() []CompendiumDuplicate {
+	byName := map[string][]CompendiumEntry{}
+	var names []string
+	for _, entry := range compendium.Entries() {
+		name := strings.ToLower(entry.Creature.Name)
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], entry)
+	}
+
+	duplicates := []CompendiumDuplicate{}
+	for _, name := range names {
+		if entries := byName[name]; len(entries) > 1 {
+			duplicates = append(duplicates, CompendiumDuplicate{Name: entries[0].Creature.Name, Entries: entries})
+		}
+	}
+	return duplicates
+}
+
+func (compendium *Compendium) reindex() {
+	compendium.index = NewSearchIndex(compendium.Documents())
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func compendiumBlocks(t *testing.T) []CompendiumBlock {
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join("testdata", "statblocks", name))
+		assert.NoError(t, err)
+		return string(content)
+	}
+	return []CompendiumBlock{
+		{UUID: "goblin-1", Page: "Monsters/Goblins", Content: read("goblin.md")},
+		{UUID: "captain-1", Page: "Monsters/Bandits", Content: read("bandit-captain.md")},
+		{UUID: "dragon-1", Page: "Monsters/Dragons", Content: read("adult-red-dragon.md")},
+		{UUID: "goblin-2", Page: "Session 3", Content: "### goblin\nSmall humanoid (goblinoid), chaotic evil\n"},
+	}
+}
+
+func TestCompendiumIngest(t *testing.T) {
+	compendium := NewCompendium()
+	errs := compendium.Ingest(append(compendiumBlocks(t), CompendiumBlock{UUID: "notes-1", Page: "Session 3", Content: "The party met a goblin."}))
+
+	assert.Equal(t, []CompendiumError{{UUID: "notes-1", Page: "Session 3", Error: "no stat block name"}}, errs)
+	var names []string
+	for _, entry := range compendium.Entries() {
+		names = append(names, entry.Creature.Name+" "+entry.UUID)
+	}
+	assert.Equal(t, []string{"Adult Red Dragon dragon-1", "Bandit Captain captain-1", "Goblin goblin-1", "goblin goblin-2"}, names)
+
+	entry, ok := compendium.Find("Bandit Captain")
+	assert.True(t, ok)
+	assert.Equal(t, "captain-1", entry.UUID)
+	assert.Equal(t, "Monsters/Bandits", entry.Page)
+	assert.Equal(t, 15, entry.Creature.ArmorClass)
+
+	// Editing a block replaces what was read from it.
+	compendium.Ingest([]CompendiumBlock{{UUID: "captain-1", Page: "Monsters/Bandits", Content: "### Bandit Chief\nMedium humanoid (human), any non-lawful alignment\n"}})
+	_, ok = compendium.Find("Bandit Captain")
+	assert.False(t, ok)
+	_, ok = compendium.Find("bandit chief")
+	assert.True(t, ok)
+
+	compendium.Ingest([]CompendiumBlock{{UUID: "captain-1", Content: ""}})
+	_, ok = compendium.Find("Bandit Chief")
+	assert.False(t, ok)
+
+	compendium.Remove("dragon-1")
+	_, ok = compendium.Find("Adult Red Dragon")
+	assert.False(t, ok)
+	assert.Len(t, compendium.Entries(), 2)
+}
+
+func TestCompendiumSearch(t *testing.T) {
+	compendium := NewCompendium()
+	compendium.Ingest(compendiumBlocks(t))
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"goblin", []string{"goblin-1", "goblin-2"}},
+		{"nimble escape", []string{"goblin-1"}},
+		{"fire breath", []string{"dragon-1"}},
+		{"dragon", []string{"dragon-1"}},
+		{"owlbear", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var references []string
+			for _, result := range compendium.Search(tt.query, 0) {
+				assert.Equal(t, "monster", result.Kind)
+				references = append(references, result.Reference)
+			}
+			assert.Equal(t, tt.expected, references)
+		})
+	}
+}
+
+func TestCompendiumDuplicates(t *testing.T) {
+	compendium := NewCompendium()
+	compendium.Ingest(compendiumBlocks(t))
+
+	duplicates := compendium.Duplicates()
+	assert.Len(t, duplicates, 1)
+	assert.Equal(t, "Goblin", duplicates[0].Name)
+	var pages []string
+	for _, entry := range duplicates[0].Entries {
+		pages = append(pages, entry.Page)
+	}
+	assert.Equal(t, []string{"Monsters/Goblins", "Session 3"}, pages)
+
+	compendium.Remove("goblin-2")
+	assert.Empty(t, compendium.Duplicates())
+}
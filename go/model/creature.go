@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -15,6 +16,7 @@ type Creature struct {
 	Size       string `json:"size"`
 	Alignment  string `json:"alignment"`
 	ArmorClass int    `json:"armorClass"`
+	ArmorType  string `json:"armorType,omitempty"`
 	HitPoints  string `json:"hitPoints"`
 	Speed      struct {
 		Base   int  `json:"base"`
@@ -24,40 +26,74 @@ type Creature struct {
 		Hover  bool `json:"hover,omitempty"`
 		Swim   int  `json:"swim,omitempty"`
 	} `json:"speed,omitempty"`
-	AbilityScores struct {
-		Strength     int `json:"strength"`
-		Dexterity    int `json:"dexterity"`
-		Constitution int `json:"constitution"`
-		Intelligence int `json:"intelligence"`
-		Wisdom       int `json:"wisdom"`
-		Charisma     int `json:"charisma"`
-	} `json:"abilityScores"`
-	SavingThrows          string   `json:"savingThrows,omitempty"`
-	Skills                string   `json:"skills,omitempty"`
-	DamageVulnerabilities string   `json:"damageVulnerabilities,omitempty"`
-	DamageResistances     string   `json:"damageResistances,omitempty"`
-	DamageImmunities      string   `json:"damageImmunities,omitempty"`
-	ConditionImmunities   string   `json:"conditionImmunities,omitempty"`
-	Senses                string   `json:"senses,omitempty"`
-	Languages             string   `json:"languages,omitempty"`
-	ChallengeRating       string   `json:"challengeRating"`
-	ProficiencyBonus      int      `json:"proficiencyBonus,omitempty"`
-	Notes                 string   `json:"notes,omitempty"`
-	Actions               []Action `json:"actions,omitempty"`
-	BonusActions          []Action `json:"bonusActions,omitempty"`
-	Reactions             []Action `json:"reactions,omitempty"`
-	LegendaryActions      []Action `json:"legendaryActions,omitempty"`
-	Options               []Action `json:"options,omitempty"`
-	Description           string   `json:"description,omitempty"`
+	AbilityScores         AbilityScores `json:"abilityScores"`
+	SavingThrows          string        `json:"savingThrows,omitempty"`
+	Skills                string        `json:"skills,omitempty"`
+	DamageVulnerabilities string        `json:"damageVulnerabilities,omitempty"`
+	DamageResistances     string        `json:"damageResistances,omitempty"`
+	DamageImmunities      string        `json:"damageImmunities,omitempty"`
+	ConditionImmunities   string        `json:"conditionImmunities,omitempty"`
+	Senses                string        `json:"senses,omitempty"`
+	Languages             string        `json:"languages,omitempty"`
+	ChallengeRating       string        `json:"challengeRating"`
+	ProficiencyBonus      int           `json:"proficiencyBonus,omitempty"`
+	Notes                 string        `json:"notes,omitempty"`
+	Traits                []Action      `json:"traits,omitempty"`
+	Actions               []Action      `json:"actions,omitempty"`
+	BonusActions          []Action      `json:"bonusActions,omitempty"`
+	Reactions             []Action      `json:"reactions,omitempty"`
+	LegendaryActions      []Action      `json:"legendaryActions,omitempty"`
+	LegendaryActionCount  int           `json:"legendaryActionCount,omitempty"`
+	LegendaryActionsIntro string        `json:"legendaryActionsIntro,omitempty"`
+	MythicActions         []Action      `json:"mythicActions,omitempty"`
+	LairActions           []Action      `json:"lairActions,omitempty"`
+	RegionalEffects       []Action      `json:"regionalEffects,omitempty"`
+	Options               []Action      `json:"options,omitempty"`
+	Description           string        `json:"description,omitempty"`
+	// Base references the creature this one is a variant of, e.g. "[[Bandit]]".
+	// Such a stat block lists only what it changes; see ResolveCreature.
+	Base string `json:"base,omitempty"`
+	// ExtraProperties and ExtraSections keep property rows and sections the
+	// parser does not recognise, so that saving a block does not erase them.
+	ExtraProperties map[string]Extension `json:"extraProperties,omitempty"`
+	ExtraSections   map[string]Extension `json:"extraSections,omitempty"`
 }
 
+type AbilityScores struct {
+	Strength     int `json:"strength"`
+	Dexterity    int `json:"dexterity"`
+	Constitution int `json:"constitution"`
+	Intelligence int `json:"intelligence"`
+	Wisdom       int `json:"wisdom"`
+	Charisma     int `json:"charisma"`
+}
+
+// Extension is stat block content outside the known fields. After names the
+// property or section it followed, which is where it is written back.
+type Extension struct {
+	Value string `json:"value"`
+	After string `json:"after,omitempty"`
+}
+
+var (
+	legendaryCountRegex = regexp.MustCompile(`(?i)can take (\d+) legendary actions`)
+	speedRegex          = regexp.MustCompile(`(\d+)\s*ft`)
+	typeLineRegex       = regexp.MustCompile(`^(Tiny|Small|Medium|Large|Huge|Gargantuan) ([a-zA-Z\s]+(?:\s\(.*\))?)(?:, (.*))?$`)
+)
+
 func (creature *Creature) FromMarkdown(content string) error {
-	creature.AbilityScores.Strength = 10
-	creature.AbilityScores.Dexterity = 10
-	creature.AbilityScores.Constitution = 10
-	creature.AbilityScores.Intelligence = 10
-	creature.AbilityScores.Wisdom = 10
-	creature.AbilityScores.Charisma = 10
+	for _, score := range creature.abilityScores() {
+		*score = 0
+	}
+	// A creature with a base inherits the ability scores it leaves out, so
+	// they stay zero; any other creature defaults to 10.
+	defer func() {
+		for _, score := range creature.abilityScores() {
+			if *score == 0 && creature.Base == "" {
+				*score = 10
+			}
+		}
+	}()
 	if content == "" {
 		return nil
 	}
@@ -65,76 +101,14 @@ func (creature *Creature) FromMarkdown(content string) error {
 	lines := strings.Split(content, "\n")
 
 	var currentSection string
+	var previousSection string
 	var sectionContent []string
 	justSawHeader := false
 
 	processSection := func() {
 		if currentSection != "" && len(sectionContent) > 0 {
-			text := strings.TrimSpace(strings.Join(sectionContent, "\n"))
-
-			switch currentSection {
-			case "DESCRIPTION":
-				creature.Description = text
-				sectionContent = nil
-				return
-			case "NOTES":
-				creature.Notes = text
-				sectionContent = nil
-				return
-			}
-
-			// Split text into paragraphs, then group paragraphs into action blocks.
-			paragraphs := strings.Split(text, "\n\n")
-			var actionBlocks []string
-			var currentBlock string
-
-			for _, p := range paragraphs {
-				trimmedP := strings.TrimSpace(p)
-				if strings.HasPrefix(trimmedP, "***") && currentBlock != "" {
-					actionBlocks = append(actionBlocks, strings.TrimSpace(currentBlock))
-					currentBlock = p
-				} else {
-					if currentBlock == "" {
-						currentBlock = p
-					} else {
-						currentBlock += "\n\n" + p
-					}
-				}
-			}
-			if currentBlock != "" {
-				actionBlocks = append(actionBlocks, strings.TrimSpace(currentBlock))
-			}
-
-			actions := []Action{}
-			actionRegex := regexp.MustCompile(`(?s)^\*\*\*(.*?)\*\*\*\s*(.*)`)
-
-			for _, block := range actionBlocks {
-				if strings.TrimSpace(block) == "" {
-					continue
-				}
-
-				match := actionRegex.FindStringSubmatch(block)
-				if len(match) > 2 {
-					name := strings.TrimSuffix(strings.TrimSpace(match[1]), ".")
-					description := strings.TrimSpace(match[2])
-					actions = append(actions, Action{Name: name, Description: description})
-				}
-			}
-
-			if len(actions) > 0 {
-				switch currentSection {
-				case "ACTIONS":
-					creature.Actions = actions
-				case "BONUS ACTIONS":
-					creature.BonusActions = actions
-				case "REACTIONS":
-					creature.Reactions = actions
-				case "LEGENDARY ACTIONS":
-					creature.LegendaryActions = actions
-				case "OPTIONS":
-					creature.Options = actions
-				}
-			}
+			creature.setSection(currentSection, strings.TrimSpace(strings.Join(sectionContent, "\n")), previousSection)
+			previousSection = currentSection
 		}
 		sectionContent = nil
 	}
@@ -177,149 +151,348 @@ func (creature *Creature) FromMarkdown(content string) error {
 			sectionContent = append(sectionContent, line)
 		} else {
 			if !strings.HasPrefix(trimmedLine, "|") {
-				typeSizeAlignmentMatch := regexp.MustCompile(`^(Tiny|Small|Medium|Large|Huge|Gargantuan) ([a-zA-Z\s]+(?:\s\(.*\))?)(?:, (.*))?$`).FindStringSubmatch(line)
-				if len(typeSizeAlignmentMatch) > 1 {
-					creature.Size = typeSizeAlignmentMatch[1]
-					typeAndSpecies := strings.Split(typeSizeAlignmentMatch[2], " (")
-					creature.Type = strings.TrimSpace(typeAndSpecies[0])
-					if len(typeAndSpecies) > 1 {
-						creature.Species = strings.TrimSuffix(typeAndSpecies[1], ")")
-					}
-					if len(typeSizeAlignmentMatch) > 3 {
-						creature.Alignment = strings.TrimSpace(typeSizeAlignmentMatch[3])
-					}
-				}
+				creature.setTypeLine(line)
 			}
 		}
 	}
 	processSection()
 
-	tableLines := []string{}
-	for _, line := range lines {
-		if strings.HasPrefix(line, "|") {
-			tableLines = append(tableLines, line)
+	for _, table := range ParseTables(content) {
+		if table.HasColumns("Property", "Value") {
+			previous := ""
+			for i := range table.Rows {
+				property := strings.Trim(table.Get(i, "Property"), "* ")
+				if property == "" {
+					continue
+				}
+				if !creature.setProperty(property, table.Get(i, "Value")) {
+					creature.addExtraProperty(property, table.Get(i, "Value"), previous)
+				}
+				previous = property
+			}
+		} else if table.HasColumns("STR", "DEX") && len(table.Rows) > 0 {
+			creature.setAbilityScores(table)
 		}
 	}
 
-	var propertyTable []string
-	var abilityTable []string
-	inPropertyTable := false
-	inAbilityTable := false
-
-	for _, line := range tableLines {
-		if strings.Contains(line, "Property") && strings.Contains(line, "Value") {
-			inPropertyTable = true
-			inAbilityTable = false
-			propertyTable = []string{line}
-		} else if strings.Contains(line, "STR") && strings.Contains(line, "DEX") {
-			inAbilityTable = true
-			inPropertyTable = false
-			abilityTable = []string{line}
-		} else if inPropertyTable {
-			propertyTable = append(propertyTable, line)
-		} else if inAbilityTable {
-			abilityTable = append(abilityTable, line)
+	return nil
+}
+
+func (creature *Creature) setTypeLine(line string) bool {
+	typeSizeAlignmentMatch := typeLineRegex.FindStringSubmatch(line)
+	if len(typeSizeAlignmentMatch) < 2 {
+		return false
+	}
+	creature.Size = typeSizeAlignmentMatch[1]
+	typeAndSpecies := strings.Split(typeSizeAlignmentMatch[2], " (")
+	creature.Type = strings.TrimSpace(typeAndSpecies[0])
+	if len(typeAndSpecies) > 1 {
+		creature.Species = strings.TrimSuffix(typeAndSpecies[1], ")")
+	}
+	if len(typeSizeAlignmentMatch) > 3 {
+		creature.Alignment = strings.TrimSpace(typeSizeAlignmentMatch[3])
+	}
+	return true
+}
+
+func (creature *Creature) typeLine() string {
+	if creature.Size == "" || creature.Type == "" || creature.Alignment == "" {
+		return ""
+	}
+	line := fmt.Sprintf("%s %s", creature.Size, creature.Type)
+	if creature.Species != "" {
+		line += fmt.Sprintf(" (%s)", creature.Species)
+	}
+	return line + ", " + creature.Alignment
+}
+
+// setSection stores the text of a titled section. Sections the model does
+// not know, and action sections without any parseable actions, are kept as
+// extensions positioned after the previous section.
+func (creature *Creature) setSection(title string, text string, previous string) {
+	switch title {
+	case "DESCRIPTION":
+		creature.Description = text
+		return
+	case "NOTES":
+		creature.Notes = text
+		return
+	case "TRAITS", "ACTIONS", "BONUS ACTIONS", "REACTIONS", "LEGENDARY ACTIONS", "MYTHIC ACTIONS", "LAIR ACTIONS", "REGIONAL EFFECTS", "OPTIONS":
+	default:
+		creature.addExtraSection(title, text, previous)
+		return
+	}
+
+	actions := parseActions(text)
+	if len(actions) == 0 {
+		if text != "" {
+			creature.addExtraSection(title, text, previous)
 		}
+		return
 	}
 
-	if len(propertyTable) > 0 {
-		for _, row := range propertyTable {
-			match := regexp.MustCompile(`^\| \*\*(.*?)\*\* \| (.*) \|$`).FindStringSubmatch(row)
-			if len(match) > 2 {
-				property := match[1]
-				value := match[2]
-				switch property {
-				case "Armor Class":
-					creature.ArmorClass, _ = strconv.Atoi(value)
-				case "Hit Points":
-					creature.HitPoints = value
-				case "Speed":
-					speedParts := strings.Split(value, ",")
-					for _, part := range speedParts {
-						part = strings.TrimSpace(part)
-						if strings.HasSuffix(part, "ft.") {
-							val, err := strconv.Atoi(strings.TrimSuffix(part, "ft."))
-							if err == nil {
-								if strings.Contains(part, "burrow") {
-									creature.Speed.Burrow = val
-								} else if strings.Contains(part, "climb") {
-									creature.Speed.Climb = val
-								} else if strings.Contains(part, "fly") {
-									creature.Speed.Fly = val
-								} else if strings.Contains(part, "swim") {
-									creature.Speed.Swim = val
-								} else {
-									creature.Speed.Base = val
-								}
-							}
-						} else if strings.Contains(part, "(hover)") {
-							creature.Speed.Hover = true
-						}
-					}
-				case "Saving Throws":
-					creature.SavingThrows = value
-				case "Skills":
-					creature.Skills = value
-				case "Damage Vulnerabilities":
-					creature.DamageVulnerabilities = value
-				case "Damage Resistances":
-					creature.DamageResistances = value
-				case "Damage Immunities":
-					creature.DamageImmunities = value
-				case "Condition Immunities":
-					creature.ConditionImmunities = value
-				case "Senses":
-					creature.Senses = value
-				case "Languages":
-					creature.Languages = value
-				case "Challenge":
-					creature.ChallengeRating = value
-				case "Proficiency Bonus":
-					creature.ProficiencyBonus, _ = strconv.Atoi(value)
-				}
+	if title == "LEGENDARY ACTIONS" {
+		intro, _, _ := strings.Cut(text, "\n\n")
+		intro = strings.TrimSpace(intro)
+		if !strings.HasPrefix(intro, "***") {
+			creature.LegendaryActionsIntro = intro
+			countMatch := legendaryCountRegex.FindStringSubmatch(intro)
+			if len(countMatch) > 1 {
+				creature.LegendaryActionCount, _ = strconv.Atoi(countMatch[1])
 			}
 		}
 	}
 
-	if len(abilityTable) > 2 {
-		values := strings.Split(abilityTable[2], "|")
-		var filteredValues []string
-		for _, v := range values {
-			if strings.TrimSpace(v) != "" {
-				filteredValues = append(filteredValues, strings.TrimSpace(v))
+	switch title {
+	case "TRAITS":
+		creature.Traits = actions
+	case "ACTIONS":
+		creature.Actions = actions
+	case "BONUS ACTIONS":
+		creature.BonusActions = actions
+	case "REACTIONS":
+		creature.Reactions = actions
+	case "LEGENDARY ACTIONS":
+		creature.LegendaryActions = actions
+	case "MYTHIC ACTIONS":
+		creature.MythicActions = actions
+	case "LAIR ACTIONS":
+		creature.LairActions = actions
+	case "REGIONAL EFFECTS":
+		creature.RegionalEffects = actions
+	case "OPTIONS":
+		creature.Options = actions
+	}
+}
+
+// addExtraSection keeps an unknown section. A second section with the same
+// title is appended to the first rather than replacing it.
+func (creature *Creature) addExtraSection(title string, text string, previous string) {
+	if creature.ExtraSections == nil {
+		creature.ExtraSections = map[string]Extension{}
+	}
+	if existing, ok := creature.ExtraSections[title]; ok {
+		existing.Value += "\n\n" + text
+		creature.ExtraSections[title] = existing
+		return
+	}
+	creature.ExtraSections[title] = Extension{Value: text, After: previous}
+}
+
+func (creature *Creature) addExtraProperty(property string, value string, previous string) {
+	if creature.ExtraProperties == nil {
+		creature.ExtraProperties = map[string]Extension{}
+	}
+	creature.ExtraProperties[property] = Extension{Value: value, After: previous}
+}
+
+var actionRegex = regexp.MustCompile(`(?s)^\*\*\*(.*?)\*\*\*\s*(.*)`)
+
+// parseActions reads "***Name.*** description" blocks. An action runs until
+// the next paragraph that starts a new name, so descriptions may span
+// several paragraphs.
+func parseActions(text string) []Action {
+	paragraphs := strings.Split(text, "\n\n")
+	var actionBlocks []string
+	var currentBlock string
+
+	for _, p := range paragraphs {
+		trimmedP := strings.TrimSpace(p)
+		if strings.HasPrefix(trimmedP, "***") && currentBlock != "" {
+			actionBlocks = append(actionBlocks, strings.TrimSpace(currentBlock))
+			currentBlock = p
+		} else {
+			if currentBlock == "" {
+				currentBlock = p
+			} else {
+				currentBlock += "\n\n" + p
 			}
 		}
-		if len(filteredValues) >= 6 {
-			creature.AbilityScores.Strength, _ = strconv.Atoi(strings.Fields(filteredValues[0])[0])
-			creature.AbilityScores.Dexterity, _ = strconv.Atoi(strings.Fields(filteredValues[1])[0])
-			creature.AbilityScores.Constitution, _ = strconv.Atoi(strings.Fields(filteredValues[2])[0])
-			creature.AbilityScores.Intelligence, _ = strconv.Atoi(strings.Fields(filteredValues[3])[0])
-			creature.AbilityScores.Wisdom, _ = strconv.Atoi(strings.Fields(filteredValues[4])[0])
-			creature.AbilityScores.Charisma, _ = strconv.Atoi(strings.Fields(filteredValues[5])[0])
+	}
+	if currentBlock != "" {
+		actionBlocks = append(actionBlocks, strings.TrimSpace(currentBlock))
+	}
+
+	actions := []Action{}
+	for _, block := range actionBlocks {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+
+		match := actionRegex.FindStringSubmatch(block)
+		if len(match) > 2 {
+			name := strings.TrimSuffix(strings.TrimSpace(match[1]), ".")
+			description := strings.TrimSpace(match[2])
+			actions = append(actions, Action{Name: name, Description: description})
 		}
 	}
+	return actions
+}
 
-	return nil
+func (creature *Creature) setProperty(property string, value string) bool {
+	switch property {
+	case "Armor Class":
+		armorClass, armorType, _ := strings.Cut(value, " (")
+		creature.ArmorClass, _ = strconv.Atoi(strings.TrimSpace(armorClass))
+		creature.ArmorType = strings.TrimSuffix(armorType, ")")
+	case "Hit Points":
+		creature.HitPoints = value
+	case "Speed":
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if strings.Contains(part, "(hover)") {
+				creature.Speed.Hover = true
+			}
+			match := speedRegex.FindStringSubmatch(part)
+			if len(match) < 2 {
+				continue
+			}
+			val, _ := strconv.Atoi(match[1])
+			if strings.Contains(part, "burrow") {
+				creature.Speed.Burrow = val
+			} else if strings.Contains(part, "climb") {
+				creature.Speed.Climb = val
+			} else if strings.Contains(part, "fly") {
+				creature.Speed.Fly = val
+			} else if strings.Contains(part, "swim") {
+				creature.Speed.Swim = val
+			} else {
+				creature.Speed.Base = val
+			}
+		}
+	case "Saving Throws":
+		creature.SavingThrows = value
+	case "Skills":
+		creature.Skills = value
+	case "Damage Vulnerabilities":
+		creature.DamageVulnerabilities = value
+	case "Damage Resistances":
+		creature.DamageResistances = value
+	case "Damage Immunities":
+		creature.DamageImmunities = value
+	case "Condition Immunities":
+		creature.ConditionImmunities = value
+	case "Senses":
+		creature.Senses = value
+	case "Languages":
+		creature.Languages = value
+	case "Challenge":
+		creature.ChallengeRating = value
+	case "Proficiency Bonus":
+		creature.ProficiencyBonus, _ = strconv.Atoi(value)
+	case "Base Creature":
+		creature.Base = value
+	default:
+		return false
+	}
+	return true
 }
 
 func (creature *Creature) ToMarkdown() (string, error) {
 	md := fmt.Sprintf("### %s\n", creature.Name)
-	if creature.Size != "" && creature.Type != "" && creature.Alignment != "" {
-		md += fmt.Sprintf("%s %s", creature.Size, creature.Type)
-		if creature.Species != "" {
-			md += fmt.Sprintf(" (%s)", creature.Species)
-		}
-		md += fmt.Sprintf(", %s\n", creature.Alignment)
+	if typeLine := creature.typeLine(); typeLine != "" {
+		md += typeLine + "\n"
 	}
 	md += "---\n"
 
-	md += "| Property | Value |\n"
-	md += "| :--- | :--- |\n"
+	properties := Table{Header: []string{"Property", "Value"}, Align: []Alignment{AlignLeft, AlignLeft}}
+	for _, row := range placeExtras(creature.propertyRows(), creature.ExtraProperties) {
+		properties.AddRow(fmt.Sprintf("**%s**", row.name), row.value)
+	}
+	md += properties.String() + "\n"
+	md += "---\n"
+
+	if creature.Base == "" || creature.AbilityScores != (Creature{}).AbilityScores {
+		abilities := creature.abilityTable()
+		md += abilities.String() + "\n"
+		md += "---\n"
+	}
+
+	for i, section := range placeExtras(creature.sections(), creature.ExtraSections) {
+		if i > 0 {
+			md += "\n"
+		}
+		md += fmt.Sprintf("\n**%s**\n---\n%s", section.name, section.value)
+	}
+
+	return strings.TrimSpace(md), nil
+}
+
+func (creature *Creature) setAbilityScores(table Table) {
+	scores := []struct {
+		column string
+		score  *int
+	}{
+		{"STR", &creature.AbilityScores.Strength},
+		{"DEX", &creature.AbilityScores.Dexterity},
+		{"CON", &creature.AbilityScores.Constitution},
+		{"INT", &creature.AbilityScores.Intelligence},
+		{"WIS", &creature.AbilityScores.Wisdom},
+		{"CHA", &creature.AbilityScores.Charisma},
+	}
+	for _, s := range scores {
+		fields := strings.Fields(table.Get(0, s.column))
+		if len(fields) > 0 {
+			if value, err := strconv.Atoi(fields[0]); err == nil {
+				*s.score = value
+			}
+		}
+	}
+}
+
+func (creature *Creature) abilityScores() []*int {
+	return []*int{
+		&creature.AbilityScores.Strength,
+		&creature.AbilityScores.Dexterity,
+		&creature.AbilityScores.Constitution,
+		&creature.AbilityScores.Intelligence,
+		&creature.AbilityScores.Wisdom,
+		&creature.AbilityScores.Charisma,
+	}
+}
+
+func (creature *Creature) abilityTable() Table {
+	abilities := Table{
+		Header: []string{"STR", "DEX", "CON", "INT", "WIS", "CHA"},
+		Align:  []Alignment{AlignCenter, AlignCenter, AlignCenter, AlignCenter, AlignCenter, AlignCenter},
+	}
+	abilityRow := []string{}
+	for _, score := range []int{
+		creature.AbilityScores.Strength,
+		creature.AbilityScores.Dexterity,
+		creature.AbilityScores.Constitution,
+		creature.AbilityScores.Intelligence,
+		creature.AbilityScores.Wisdom,
+		creature.AbilityScores.Charisma,
+	} {
+		if score == 0 {
+			abilityRow = append(abilityRow, "—")
+			continue
+		}
+		abilityRow = append(abilityRow, fmt.Sprintf("%d (%s)", score, GetModifier(score)))
+	}
+	abilities.AddRow(abilityRow...)
+	return abilities
+}
+
+// propertyRows lists the known properties that have a value, in stat block order.
+func (creature *Creature) propertyRows() []statBlockField {
+	var rows []statBlockField
+	addRow := func(name, value string) {
+		rows = append(rows, statBlockField{name: name, value: value})
+	}
+	if creature.Base != "" {
+		addRow("Base Creature", creature.Base)
+	}
 	if creature.ArmorClass != 0 {
-		md += fmt.Sprintf("| **Armor Class** | %d |\n", creature.ArmorClass)
+		armorClass := strconv.Itoa(creature.ArmorClass)
+		if creature.ArmorType != "" {
+			armorClass += fmt.Sprintf(" (%s)", creature.ArmorType)
+		}
+		addRow("Armor Class", armorClass)
 	}
 	if creature.HitPoints != "" {
-		md += fmt.Sprintf("| **Hit Points** | %s |\n", creature.HitPoints)
+		addRow("Hit Points", creature.HitPoints)
 	}
 
 	if creature.Speed.Base != 0 {
@@ -339,113 +512,162 @@ func (creature *Creature) ToMarkdown() (string, error) {
 		if creature.Speed.Swim != 0 {
 			speedString += fmt.Sprintf(", swim %dft.", creature.Speed.Swim)
 		}
-		md += fmt.Sprintf("| **Speed** | %s |\n", speedString)
+		addRow("Speed", speedString)
 	}
 
 	if creature.SavingThrows != "" {
-		md += fmt.Sprintf("| **Saving Throws** | %s |\n", creature.SavingThrows)
+		addRow("Saving Throws", creature.SavingThrows)
 	}
 	if creature.Skills != "" {
-		md += fmt.Sprintf("| **Skills** | %s |\n", creature.Skills)
+		addRow("Skills", creature.Skills)
 	}
 	if creature.DamageVulnerabilities != "" {
-		md += fmt.Sprintf("| **Damage Vulnerabilities** | %s |\n", creature.DamageVulnerabilities)
+		addRow("Damage Vulnerabilities", creature.DamageVulnerabilities)
 	}
 	if creature.DamageResistances != "" {
-		md += fmt.Sprintf("| **Damage Resistances** | %s |\n", creature.DamageResistances)
+		addRow("Damage Resistances", creature.DamageResistances)
 	}
 	if creature.DamageImmunities != "" {
-		md += fmt.Sprintf("| **Damage Immunities** | %s |\n", creature.DamageImmunities)
+		addRow("Damage Immunities", creature.DamageImmunities)
 	}
 	if creature.ConditionImmunities != "" {
-		md += fmt.Sprintf("| **Condition Immunities** | %s |\n", creature.ConditionImmunities)
+		addRow("Condition Immunities", creature.ConditionImmunities)
 	}
 	if creature.Senses != "" {
-		md += fmt.Sprintf("| **Senses** | %s |\n", creature.Senses)
+		addRow("Senses", creature.Senses)
 	}
 	if creature.Languages != "" {
-		md += fmt.Sprintf("| **Languages** | %s |\n", creature.Languages)
+		addRow("Languages", creature.Languages)
 	}
 	if creature.ChallengeRating != "" {
-		md += fmt.Sprintf("| **Challenge** | %s |\n", creature.ChallengeRating)
+		addRow("Challenge", creature.ChallengeRating)
 	}
 	if creature.ProficiencyBonus != 0 {
-		md += fmt.Sprintf("| **Proficiency Bonus** | +%d |\n", creature.ProficiencyBonus)
+		addRow("Proficiency Bonus", fmt.Sprintf("+%d", creature.ProficiencyBonus))
 	}
-	md += "---\n"
 
-	md += "| STR | DEX | CON | INT | WIS | CHA |\n"
-	md += "| :-: | :-: | :-: | :-: | :-: | :-: |\n"
-	md += fmt.Sprintf("| %d (%s) | %d (%s) | %d (%s) | %d (%s) | %d (%s) | %d (%s) |\n",
-		creature.AbilityScores.Strength, GetModifier(creature.AbilityScores.Strength),
-		creature.AbilityScores.Dexterity, GetModifier(creature.AbilityScores.Dexterity),
-		creature.AbilityScores.Constitution, GetModifier(creature.AbilityScores.Constitution),
-		creature.AbilityScores.Intelligence, GetModifier(creature.AbilityScores.Intelligence),
-		creature.AbilityScores.Wisdom, GetModifier(creature.AbilityScores.Wisdom),
-		creature.AbilityScores.Charisma, GetModifier(creature.AbilityScores.Charisma))
-	md += "---\n"
+	return rows
+}
 
-	if len(creature.Actions) > 0 {
-		md += "\n**ACTIONS**\n---\n"
-		for i, a := range creature.Actions {
-			md += fmt.Sprintf("***%s.*** %s", a.Name, a.Description)
-			if i < len(creature.Actions)-1 {
-				md += "\n\n"
-			}
+// sections lists the known sections that have content, in stat block order.
+func (creature *Creature) sections() []statBlockField {
+	var sections []statBlockField
+	addSection := func(title string, actions []Action, preamble string) {
+		if len(actions) == 0 {
+			return
 		}
-	}
-	if len(creature.BonusActions) > 0 {
-		md += "\n\n**BONUS ACTIONS**\n---\n"
-		for i, a := range creature.BonusActions {
-			md += fmt.Sprintf("***%s.*** %s", a.Name, a.Description)
-			if i < len(creature.BonusActions)-1 {
-				md += "\n\n"
+		body := preamble
+		for i, a := range actions {
+			if i > 0 {
+				body += "\n\n"
 			}
-		}
-	}
-	if len(creature.Reactions) > 0 {
-		md += "\n\n**REACTIONS**\n---\n"
-		for i, a := range creature.Reactions {
-			md += fmt.Sprintf("***%s.*** %s", a.Name, a.Description)
-			if i < len(creature.Reactions)-1 {
-				md += "\n\n"
+			body += fmt.Sprintf("***%s.***", a.Name)
+			if a.Description != "" {
+				body += " " + a.Description
 			}
 		}
+		sections = append(sections, statBlockField{name: title, value: body})
 	}
-	if len(creature.LegendaryActions) > 0 {
-		md += "\n\n**LEGENDARY ACTIONS**\n---\n"
-		for i, a := range creature.LegendaryActions {
-			md += fmt.Sprintf("***%s.*** %s", a.Name, a.Description)
-			if i < len(creature.LegendaryActions)-1 {
-				md += "\n\n"
-			}
-		}
-	}
-	if len(creature.Options) > 0 {
-		md += "\n\n**OPTIONS**\n---\n"
-		for i, a := range creature.Options {
-			md += fmt.Sprintf("***%s.*** %s", a.Name, a.Description)
-			if i < len(creature.Options)-1 {
-				md += "\n\n"
-			}
-		}
+	addSection("TRAITS", creature.Traits, "")
+	addSection("ACTIONS", creature.Actions, "")
+	addSection("BONUS ACTIONS", creature.BonusActions, "")
+	addSection("REACTIONS", creature.Reactions, "")
+	legendaryPreamble := ""
+	countMatch := legendaryCountRegex.FindStringSubmatch(creature.LegendaryActionsIntro)
+	if creature.LegendaryActionsIntro != "" && (len(countMatch) < 2 || countMatch[1] == strconv.Itoa(creature.LegendaryActionCount)) {
+		// Keep the intro unless it gives a count that has since changed.
+		legendaryPreamble = creature.LegendaryActionsIntro + "\n\n"
+	} else if creature.LegendaryActionCount > 0 {
+		legendaryPreamble = fmt.Sprintf("The %s can take %d legendary actions, choosing from the options below. Only one legendary action can be used at a time and only at the end of another creature's turn. The %s regains spent legendary actions at the start of its turn.\n\n",
+			creature.Name, creature.LegendaryActionCount, creature.Name)
 	}
+	addSection("LEGENDARY ACTIONS", creature.LegendaryActions, legendaryPreamble)
+	addSection("MYTHIC ACTIONS", creature.MythicActions, "")
+	addSection("LAIR ACTIONS", creature.LairActions, "")
+	addSection("REGIONAL EFFECTS", creature.RegionalEffects, "")
+	addSection("OPTIONS", creature.Options, "")
 	if len(creature.Description) > 0 {
-		md += "\n\n**DESCRIPTION**\n---\n"
-		md += creature.Description
+		sections = append(sections, statBlockField{name: "DESCRIPTION", value: creature.Description})
 	}
 	if len(creature.Notes) > 0 {
-		md += "\n\n**NOTES**\n---\n"
-		md += creature.Notes
+		sections = append(sections, statBlockField{name: "NOTES", value: creature.Notes})
 	}
 
-	return strings.TrimSpace(md), nil
+	return sections
+}
+
+type statBlockField struct {
+	name  string
+	value string
+}
+
+// placeExtras merges extension content into known fields, putting each one
+// straight after the field it followed when it was parsed. Extensions whose
+// anchor is gone are written at the end.
+func placeExtras(known []statBlockField, extras map[string]Extension) []statBlockField {
+	if len(extras) == 0 {
+		return known
+	}
+
+	names := make([]string, 0, len(extras))
+	for name := range extras {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	placed := map[string]bool{}
+	var result []statBlockField
+	var placeAfter func(anchor string)
+	placeAfter = func(anchor string) {
+		for _, name := range names {
+			if placed[name] || extras[name].After != anchor {
+				continue
+			}
+			placed[name] = true
+			result = append(result, statBlockField{name: name, value: extras[name].Value})
+			placeAfter(name)
+		}
+	}
+
+	placeAfter("")
+	for _, field := range known {
+		result = append(result, field)
+		placeAfter(field.name)
+	}
+	for _, name := range names {
+		if !placed[name] {
+			placed[name] = true
+			result = append(result, statBlockField{name: name, value: extras[name].Value})
+			placeAfter(name)
+		}
+	}
+	return result
 }
 
 func GetModifier(score int) string {
-	mod := math.Floor(float64(score-10) / 2)
+	mod := modifier(score)
 	if mod >= 0 {
-		return fmt.Sprintf("+%d", int(mod))
+		return fmt.Sprintf("+%d", mod)
+	}
+	return fmt.Sprintf("%d", mod)
+}
+
+func modifier(score int) int {
+	return int(math.Floor(float64(score-10) / 2))
+}
+
+// Document makes the creature searchable by its type line, challenge, traits
+// and actions.
+func (creature *Creature) Document() Document {
+	text := []string{creature.typeLine()}
+	if creature.ChallengeRating != "" {
+		text = append(text, fmt.Sprintf("Challenge %s", creature.ChallengeRating))
+	}
+	for _, list := range creature.actionLists() {
+		for _, action := range *list.actions {
+			text = append(text, action.Name+". "+action.Description)
+		}
 	}
-	return fmt.Sprintf("%d", int(mod))
+	text = append(text, creature.Description)
+	return Document{Kind: "monster", Name: creature.Name, Text: strings.Join(text, "\n")}
 }
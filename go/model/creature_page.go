@@ -0,0 +1,154 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParsedCreature is one stat block from a page of them. Line is where its
+// heading is on the page, counting from 1, and Errors lists what the stat
+// block is missing.
+type ParsedCreature struct {
+	Line     int      `json:"line"`
+	Creature Creature `json:"creature"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// creatureHeadingRegex matches a creature's name heading and pageHeadingRegex
+// the headings of the page around them, also as the first line of a Logseq
+// block ("- ### Goblin").
+var (
+	creatureHeadingRegex = regexp.MustCompile(`^(\s*)(- )?### `)
+	pageHeadingRegex     = regexp.MustCompile(`^\s*(?:- )?#{1,2} `)
+)
+
+// ParseCreatures splits a page, such as a pasted bestiary, into a creature
+// for each ### heading. A heading of level one or two ends the stat block
+// before it, and anything outside a stat block is skipped.
+func ParseCreatures(content string) []ParsedCreature {
+	type chunk struct {
+		line   int
+		indent int
+		lines  []string
+	}
+	var chunks []*chunk
+	var current *chunk
+	for i, line := range strings.Split(content, "\n") {
+		if match := creatureHeadingRegex.FindStringSubmatch(line); match != nil {
+			current = &chunk{line: i + 1}
+			if match[2] != "" {
+				current.indent = len(match[1]) + 2
+			}
+			chunks = append(chunks, current)
+			line = "### " + line[len(match[0]):]
+		} else if pageHeadingRegex.MatchString(line) {
+			current = nil
+		} else if current != nil && current.indent > 0 {
+			// Lines inside a Logseq block are indented under its bullet.
+			line = strings.TrimPrefix(line, strings.Repeat(" ", current.indent))
+		}
+		if current != nil {
+			current.lines = append(current.lines, line)
+		}
+	}
+
+	parsed := []ParsedCreature{}
+	lines := map[string]int{}
+	for _, c := range chunks {
+		var creature Creature
+		result := ParsedCreature{Line: c.line}
+		if err := creature.FromMarkdown(strings.Join(c.lines, "\n")); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		result.Creature = creature
+		result.Errors = append(result.Errors, creature.validate()...)
+
+		name := strings.ToLower(creature.Name)
+		if line, ok := lines[name]; ok && name != "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("same name as the creature on line %d", line))
+		} else {
+			lines[name] = c.line
+		}
+		parsed = append(parsed, result)
+	}
+	return parsed
+}
+
+// validate lists what a stat block is missing. A creature with a base
+// inherits what it leaves out, so only its name is needed.
+func (creature *Creature) validate() []string {
+	var errs []string
+	if creature.Name == "" {
+		errs = append(errs, "missing name")
+	}
+	if creature.Base != "" {
+		return errs
+	}
+	if creature.Size == "" || creature.Type == "" {
+		errs = append(errs, "missing size and type line")
+	}
+	if creature.ArmorClass == 0 {
+		errs = append(errs, "missing Armor Class")
+	}
+	if creature.HitPoints == "" {
+		errs = append(errs, "missing Hit Points")
+	}
+	if creature.ChallengeRating == "" {
+		errs = append(errs, "missing Challenge")
+	}
+	return errs
+}
+
+type CreatureFormat string
+
+const (
+	FormatMarkdown         CreatureFormat = "markdown"
+	FormatFiveETools       CreatureFormat = "5etools"
+	FormatFoundry          CreatureFormat = "foundry"
+	FormatFantasyStatblock CreatureFormat = "fantasyStatblock"
+	FormatHomebrewery      CreatureFormat = "homebrewery"
+)
+
+// Export writes the creature in one of the formats it can be exported to.
+func (creature *Creature) Export(format CreatureFormat) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		return creature.ToMarkdown()
+	case FormatFiveETools:
+		return creature.ToFiveETools()
+	case FormatFoundry:
+		return creature.ToFoundry()
+	case FormatFantasyStatblock:
+		return creature.ToFantasyStatblock()
+	case FormatHomebrewery:
+		return creature.ToHomebrewery()
+	}
+	return "", fmt.Errorf("unknown creature format %q", format)
+}
+
+// ConvertedCreature is a creature from a page written out in another format.
+type ConvertedCreature struct {
+	Line   int      `json:"line"`
+	Name   string   `json:"name"`
+	Output string   `json:"output,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ConvertCreatures converts every stat block on a page. A creature that
+// fails to convert has no output; one that is only missing fields is
+// converted as well as it can be, with its errors.
+func ConvertCreatures(content string, format CreatureFormat) []ConvertedCreature {
+	converted := []ConvertedCreature{}
+	for _, parsed := range ParseCreatures(content) {
+		result := ConvertedCreature{Line: parsed.Line, Name: parsed.Creature.Name, Errors: parsed.Errors}
+		output, err := parsed.Creature.Export(format)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			result.Output = output
+		}
+		converted = append(converted, result)
+	}
+	return converted
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCreatures(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "bestiaries", "sunless-marsh.md"))
+	assert.NoError(t, err)
+
+	parsed := ParseCreatures(string(content))
+
+	type summary struct {
+		line   int
+		name   string
+		errors []string
+	}
+	var summaries []summary
+	for _, p := range parsed {
+		summaries = append(summaries, summary{p.Line, p.Creature.Name, p.Errors})
+	}
+	assert.Equal(t, []summary{
+		{7, "Goblin", nil},
+		{36, "Goblin Boss", nil},
+		{42, "Mystery Ooze", []string{"missing Armor Class", "missing Hit Points", "missing Challenge"}},
+		{47, "Bandit Captain", nil},
+		{88, "Goblin", []string{"missing Armor Class", "missing Hit Points", "missing Challenge", "same name as the creature on line 7"}},
+	}, summaries)
+
+	goblin := parsed[0].Creature
+	assert.Len(t, goblin.Actions, 2)
+	assert.Len(t, goblin.BonusActions, 1)
+	assert.Empty(t, goblin.Description, "the page heading after the stat block is not part of it")
+
+	assert.Equal(t, "[[Goblin]]", parsed[1].Creature.Base)
+	assert.Equal(t, 17, parsed[1].Creature.ArmorClass)
+
+	// The bulleted stat block reads the same as on its own.
+	statBlock, err := os.ReadFile(filepath.Join("testdata", "statblocks", "bandit-captain.md"))
+	assert.NoError(t, err)
+	var captain Creature
+	assert.NoError(t, captain.FromMarkdown(string(statBlock)))
+	assert.Equal(t, captain, parsed[3].Creature)
+
+	assert.Equal(t, "chaotic evil", parsed[4].Creature.Alignment)
+}
+
+func TestParseCreaturesEmpty(t *testing.T) {
+	assert.Equal(t, []ParsedCreature{}, ParseCreatures("# Notes\n\nNo monsters here."))
+	assert.Equal(t, []ParsedCreature{{Line: 1, Creature: Creature{AbilityScores: AbilityScores{10, 10, 10, 10, 10, 10}}, Errors: []string{
+		"missing name", "missing size and type line", "missing Armor Class", "missing Hit Points", "missing Challenge",
+	}}}, ParseCreatures("### "))
+}
+
+func TestConvertCreatures(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "bestiaries", "sunless-marsh.md"))
+	assert.NoError(t, err)
+
+	for _, format := range []CreatureFormat{FormatMarkdown, FormatFiveETools, FormatFoundry, FormatFantasyStatblock, FormatHomebrewery} {
+		t.Run(string(format), func(t *testing.T) {
+			converted := ConvertCreatures(string(content), format)
+			assert.Len(t, converted, 5)
+			for _, c := range converted {
+				assert.NotEmpty(t, c.Output, c.Name)
+			}
+			assert.Equal(t, "Bandit Captain", converted[3].Name)
+			assert.Equal(t, 47, converted[3].Line)
+			assert.Contains(t, converted[3].Output, "Bandit Captain")
+		})
+	}
+
+	markdown := ConvertCreatures(string(content), FormatMarkdown)
+	var goblin Creature
+	assert.NoError(t, goblin.FromMarkdown(markdown[0].Output))
+	assert.Equal(t, ParseCreatures(string(content))[0].Creature, goblin)
+
+	fiveETools := ConvertCreatures(string(content), FormatFiveETools)
+	assert.True(t, json.Valid([]byte(fiveETools[0].Output)))
+
+	converted := ConvertCreatures(string(content), "pdf")
+	assert.Len(t, converted, 5)
+	assert.Empty(t, converted[0].Output)
+	assert.Equal(t, []string{`unknown creature format "pdf"`}, converted[0].Errors)
+}
@@ -1,12 +1,17 @@
 package model
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
 func TestCreatureFromMarkdown(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -546,6 +551,151 @@ Small beast, unaligned
 	}
 }
 
+func TestCreatureFromHandEditedTables(t *testing.T) {
+	markdown := `
+### Hand Edited
+Medium humanoid (human), any alignment
+---
+|Property|Value|
+|:--|:--|
+|  **Armor Class**  |  16  |
+| **Senses** | darkvision 60ft. \| truesight 10ft. |
+---
+| CHA | WIS | INT | CON | DEX | STR |
+|:---:|:---:|:---:|:---:|:---:|:---:|
+| 8 (-1) | 9 (-1) | 11 (+0) | 13 (+1) | 15 (+2) | 17 (+3) |
+`
+
+	var creature Creature
+	assert.NoError(t, creature.FromMarkdown(markdown))
+	assert.Equal(t, 16, creature.ArmorClass)
+	assert.Equal(t, "darkvision 60ft. | truesight 10ft.", creature.Senses)
+	assert.Equal(t, 17, creature.AbilityScores.Strength)
+	assert.Equal(t, 15, creature.AbilityScores.Dexterity)
+	assert.Equal(t, 8, creature.AbilityScores.Charisma)
+
+	output, err := creature.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, output, "| **Senses** | darkvision 60ft. \\| truesight 10ft. |")
+}
+
+func TestCreatureLegendaryLairAndMythicSections(t *testing.T) {
+	creature := Creature{
+		Name:                 "Adult Red Dragon",
+		Size:                 "Huge",
+		Type:                 "dragon",
+		Alignment:            "chaotic evil",
+		LegendaryActions:     []Action{{Name: "Detect", Description: "The dragon makes a Wisdom (Perception) check."}, {Name: "Tail Attack", Description: "The dragon makes a tail attack."}},
+		LegendaryActionCount: 3,
+		MythicActions:        []Action{{Name: "Fiery Rebirth", Description: "The dragon regains 100 hit points."}},
+		LairActions:          []Action{{Name: "Magma Eruption", Description: "Magma erupts from a point on the ground the dragon can see within 120 feet of it."}},
+		RegionalEffects:      []Action{{Name: "Tremors", Description: "Small earthquakes are common within 6 miles of the dragon's lair."}},
+	}
+	creature.AbilityScores.Strength = 27
+	creature.AbilityScores.Dexterity = 10
+	creature.AbilityScores.Constitution = 25
+	creature.AbilityScores.Intelligence = 16
+	creature.AbilityScores.Wisdom = 13
+	creature.AbilityScores.Charisma = 21
+
+	markdown, err := creature.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, markdown, "**LEGENDARY ACTIONS**\n---\nThe Adult Red Dragon can take 3 legendary actions")
+	assert.Contains(t, markdown, "**MYTHIC ACTIONS**\n---\n***Fiery Rebirth.***")
+	assert.Contains(t, markdown, "**LAIR ACTIONS**\n---\n***Magma Eruption.***")
+	assert.Contains(t, markdown, "**REGIONAL EFFECTS**\n---\n***Tremors.***")
+
+	var parsed Creature
+	assert.NoError(t, parsed.FromMarkdown(markdown))
+	assert.Equal(t, creature.LegendaryActions, parsed.LegendaryActions)
+	assert.Equal(t, 3, parsed.LegendaryActionCount)
+	assert.Equal(t, creature.MythicActions, parsed.MythicActions)
+	assert.Equal(t, creature.LairActions, parsed.LairActions)
+	assert.Equal(t, creature.RegionalEffects, parsed.RegionalEffects)
+}
+
+func TestCreatureGoldenRoundTrip(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "statblocks", "*.md"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			assert.NoError(t, err)
+
+			var creature Creature
+			assert.NoError(t, creature.FromMarkdown(string(content)))
+			markdown, err := creature.ToMarkdown()
+			assert.NoError(t, err)
+
+			golden := strings.TrimSuffix(input, ".md") + ".golden"
+			if *updateGolden {
+				assert.NoError(t, os.WriteFile(golden, []byte(markdown+"\n"), 0644))
+			}
+			expected, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(string(expected)), markdown)
+
+			var reparsed Creature
+			assert.NoError(t, reparsed.FromMarkdown(markdown))
+			assert.Equal(t, creature, reparsed)
+			again, err := reparsed.ToMarkdown()
+			assert.NoError(t, err)
+			assert.Equal(t, markdown, again, "serializing a parsed stat block should be idempotent")
+		})
+	}
+}
+
+func TestCreaturePreservesUnknownContent(t *testing.T) {
+	markdown := `
+### Bandit Captain
+Medium humanoid (any race), any non-lawful alignment
+---
+| Property | Value |
+| :--- | :--- |
+| **Armor Class** | 15 |
+| **Habitat** | Coastal roads |
+| **Hit Points** | 65 (10d8 + 20) |
+---
+| STR | DEX | CON | INT | WIS | CHA |
+| :-: | :-: | :-: | :-: | :-: | :-: |
+| 15 (+2) | 16 (+3) | 14 (+2) | 14 (+2) | 11 (+0) | 14 (+2) |
+---
+
+**VILLAIN ACTIONS**
+---
+***Action 1: Rally.*** Each bandit can move up to half its speed.
+
+**ACTIONS**
+---
+***Scimitar.*** *Melee Weapon Attack:* +5 to hit.
+
+**VILLAIN ACTIONS**
+---
+***Action 2: Regroup.*** The captain takes the Dash action.
+
+**LEGENDARY ACTIONS**
+---
+The captain barks orders between other creatures' turns.
+
+***Command.*** One bandit makes a weapon attack.
+`
+
+	var creature Creature
+	assert.NoError(t, creature.FromMarkdown(markdown))
+	assert.Equal(t, map[string]Extension{"Habitat": {Value: "Coastal roads", After: "Armor Class"}}, creature.ExtraProperties)
+	assert.Equal(t, map[string]Extension{"VILLAIN ACTIONS": {Value: "***Action 1: Rally.*** Each bandit can move up to half its speed.\n\n***Action 2: Regroup.*** The captain takes the Dash action."}}, creature.ExtraSections)
+	assert.Equal(t, "The captain barks orders between other creatures' turns.", creature.LegendaryActionsIntro)
+	assert.Zero(t, creature.LegendaryActionCount)
+
+	output, err := creature.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, output, "| **Armor Class** | 15 |\n| **Habitat** | Coastal roads |\n| **Hit Points** | 65 (10d8 + 20) |")
+	assert.Contains(t, output, "---\n\n**VILLAIN ACTIONS**\n---\n***Action 1: Rally.*** Each bandit can move up to half its speed.\n\n***Action 2: Regroup.*** The captain takes the Dash action.\n\n**ACTIONS**")
+	assert.Contains(t, output, "**LEGENDARY ACTIONS**\n---\nThe captain barks orders between other creatures' turns.\n\n***Command.*** One bandit makes a weapon attack.")
+}
+
 func TestGetModifier(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Dice struct {
+	Count    int `json:"count"`
+	Sides    int `json:"sides"`
+	Modifier int `json:"modifier,omitempty"`
+}
+
+var (
+	diceRegex      = regexp.MustCompile(`(\d*)\s*[dD]\s*(\d+)\s*(?:([+\-−])\s*(\d+))?`)
+	hitPointsRegex = regexp.MustCompile(`^\s*(\d+)`)
+)
+
+func ParseDice(expr string) (Dice, error) {
+	match := diceRegex.FindStringSubmatch(expr)
+	if len(match) < 5 {
+		return Dice{}, fmt.Errorf("no dice expression in %q", expr)
+	}
+
+	dice := Dice{Count: 1}
+	if match[1] != "" {
+		dice.Count, _ = strconv.Atoi(match[1])
+	}
+	dice.Sides, _ = strconv.Atoi(match[2])
+	if dice.Sides == 0 {
+		return Dice{}, fmt.Errorf("invalid die size in %q", expr)
+	}
+	if match[4] != "" {
+		dice.Modifier, _ = strconv.Atoi(match[4])
+		if match[3] != "+" {
+			dice.Modifier = -dice.Modifier
+		}
+	}
+	return dice, nil
+}
+
+func (d Dice) Average() int {
+	return d.Count*(d.Sides+1)/2 + d.Modifier
+}
+
+func (d Dice) Roll(roll Roller) int {
+	if roll == nil {
+		roll = RollDie
+	}
+	total := d.Modifier
+	for i := 0; i < d.Count; i++ {
+		total += roll(d.Sides)
+	}
+	return total
+}
+
+func (d Dice) String() string {
+	s := fmt.Sprintf("%dd%d", d.Count, d.Sides)
+	if d.Modifier > 0 {
+		s += fmt.Sprintf(" + %d", d.Modifier)
+	} else if d.Modifier < 0 {
+		s += fmt.Sprintf(" - %d", -d.Modifier)
+	}
+	return s
+}
+
+// ParseHitPoints reads a stat block hit point value such as "45 (6d10 + 12)",
+// returning the listed average and, when present, the hit dice.
+func ParseHitPoints(value string) (int, *Dice, error) {
+	match := hitPointsRegex.FindStringSubmatch(value)
+	if len(match) < 2 {
+		return 0, nil, errors.New("hit points must start with a number: " + value)
+	}
+	average, _ := strconv.Atoi(match[1])
+
+	rest := strings.TrimSpace(value[len(match[0]):])
+	if rest == "" {
+		return average, nil, nil
+	}
+	dice, err := ParseDice(rest)
+	if err != nil {
+		return average, nil, nil
+	}
+	return average, &dice, nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDice(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        string
+		expected    Dice
+		average     int
+		expectError bool
+	}{
+		{name: "Plain dice", expr: "2d6", expected: Dice{Count: 2, Sides: 6}, average: 7},
+		{name: "Positive modifier", expr: "6d10 + 12", expected: Dice{Count: 6, Sides: 10, Modifier: 12}, average: 45},
+		{name: "Negative modifier", expr: "1d4-1", expected: Dice{Count: 1, Sides: 4, Modifier: -1}, average: 1},
+		{name: "Implicit count", expr: "d20", expected: Dice{Count: 1, Sides: 20}, average: 10},
+		{name: "Not dice", expr: "seven", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dice, err := ParseDice(tt.expr)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, dice)
+			assert.Equal(t, tt.average, dice.Average())
+		})
+	}
+}
+
+func TestDiceRoll(t *testing.T) {
+	dice := Dice{Count: 3, Sides: 8, Modifier: 2}
+	assert.Equal(t, 26, dice.Roll(func(sides int) int { return sides }))
+	assert.Equal(t, 5, dice.Roll(func(sides int) int { return 1 }))
+	assert.Equal(t, "3d8 + 2", dice.String())
+}
+
+func TestParseHitPoints(t *testing.T) {
+	average, dice, err := ParseHitPoints("7 (2d6)")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, average)
+	assert.Equal(t, &Dice{Count: 2, Sides: 6}, dice)
+
+	average, dice, err = ParseHitPoints("100")
+	assert.NoError(t, err)
+	assert.Equal(t, 100, average)
+	assert.Nil(t, dice)
+
+	_, _, err = ParseHitPoints("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,347 @@
+package model
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeEdited  ChangeKind = "edited"
+)
+
+// FieldChange is a changed scalar field or extra property/section. Field is
+// the JSON path of the field, e.g. "abilityScores.strength" or
+// "extraProperties.Habitat". From or To is nil when the extra was added or
+// removed.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// ActionChange is an action added to, removed from, or edited in one of the
+// action lists. Section is the JSON name of the list, e.g. "bonusActions".
+type ActionChange struct {
+	Section string     `json:"section"`
+	Kind    ChangeKind `json:"kind"`
+	Name    string     `json:"name"`
+	From    *Action    `json:"from,omitempty"`
+	To      *Action    `json:"to,omitempty"`
+}
+
+type CreatureDiff struct {
+	Fields  []FieldChange  `json:"fields"`
+	Actions []ActionChange `json:"actions"`
+}
+
+// MergeConflict is a field or action both sides changed differently. Field is
+// a path as in FieldChange; actions are named "<section>.<action name>". The
+// values are nil where a side does not have the action or extra.
+type MergeConflict struct {
+	Field  string `json:"field"`
+	Base   any    `json:"base"`
+	Ours   any    `json:"ours"`
+	Theirs any    `json:"theirs"`
+}
+
+func (diff CreatureDiff) Empty() bool {
+	return len(diff.Fields) == 0 && len(diff.Actions) == 0
+}
+
+// DiffCreatures lists the field-level changes that turn from into to. Actions
+// are matched by name, so a renamed action shows as a removal and an addition,
+// and reordering alone is not a change.
+func DiffCreatures(from, to Creature) CreatureDiff {
+	diff := CreatureDiff{Fields: []FieldChange{}, Actions: []ActionChange{}}
+
+	toFields := creatureFields(&to)
+	for i, field := range creatureFields(&from) {
+		if a, b := field.value.Interface(), toFields[i].value.Interface(); a != b {
+			diff.Fields = append(diff.Fields, FieldChange{Field: field.name, From: a, To: b})
+		}
+	}
+
+	for _, name := range []string{"extraProperties", "extraSections"} {
+		fromExtras, toExtras := from.extras(name), to.extras(name)
+		for _, key := range sortedKeys(unionKeys(fromExtras, toExtras)) {
+			a, b := optionalExtra(fromExtras, key), optionalExtra(toExtras, key)
+			if a != b {
+				diff.Fields = append(diff.Fields, FieldChange{Field: name + "." + key, From: a.orNil(), To: b.orNil()})
+			}
+		}
+	}
+
+	toLists := to.actionLists()
+	for i, list := range from.actionLists() {
+		before := keyedActions(*list.actions)
+		after := keyedActions(*toLists[i].actions)
+		for _, entry := range after {
+			previous, ok := findAction(before, entry.key)
+			switch {
+			case !ok:
+				diff.Actions = append(diff.Actions, ActionChange{Section: list.name, Kind: ChangeAdded, Name: entry.action.Name, To: &entry.action})
+			case previous.action != entry.action:
+				diff.Actions = append(diff.Actions, ActionChange{Section: list.name, Kind: ChangeEdited, Name: entry.action.Name, From: &previous.action, To: &entry.action})
+			}
+		}
+		for _, entry := range before {
+			if _, ok := findAction(after, entry.key); !ok {
+				diff.Actions = append(diff.Actions, ActionChange{Section: list.name, Kind: ChangeRemoved, Name: entry.action.Name, From: &entry.action})
+			}
+		}
+	}
+
+	return diff
+}
+
+// MergeCreatures merges two edits, ours and theirs, of the same base
+// creature. A field or action changed on only one side takes that change;
+// where both sides changed it differently ours is kept and a conflict is
+// reported.
+func MergeCreatures(base, ours, theirs Creature) (Creature, []MergeConflict) {
+	merged := ours
+	conflicts := []MergeConflict{}
+
+	mergedFields := creatureFields(&merged)
+	baseFields := creatureFields(&base)
+	theirFields := creatureFields(&theirs)
+	for i, field := range creatureFields(&ours) {
+		b, o, t := baseFields[i].value.Interface(), field.value.Interface(), theirFields[i].value.Interface()
+		value, conflict := merge3(b, o, t)
+		if conflict {
+			conflicts = append(conflicts, MergeConflict{Field: field.name, Base: b, Ours: o, Theirs: t})
+		}
+		mergedFields[i].value.Set(reflect.ValueOf(value))
+	}
+
+	for _, name := range []string{"extraProperties", "extraSections"} {
+		baseExtras, ourExtras, theirExtras := base.extras(name), ours.extras(name), theirs.extras(name)
+		extras := map[string]Extension{}
+		for _, key := range sortedKeys(unionKeys(baseExtras, unionKeys(ourExtras, theirExtras))) {
+			b, o, t := optionalExtra(baseExtras, key), optionalExtra(ourExtras, key), optionalExtra(theirExtras, key)
+			value, conflict := merge3(b, o, t)
+			if conflict {
+				conflicts = append(conflicts, MergeConflict{Field: name + "." + key, Base: b.orNil(), Ours: o.orNil(), Theirs: t.orNil()})
+			}
+			if value.ok {
+				extras[key] = value.value
+			}
+		}
+		if len(extras) == 0 {
+			extras = nil
+		}
+		merged.setExtras(name, extras)
+	}
+
+	baseLists, theirLists := base.actionLists(), theirs.actionLists()
+	for i, list := range merged.actionLists() {
+		actions, listConflicts := mergeActions(list.name, *baseLists[i].actions, *list.actions, *theirLists[i].actions)
+		*list.actions = actions
+		conflicts = append(conflicts, listConflicts...)
+	}
+
+	return merged, conflicts
+}
+
+// merge3 picks the merged value of one field. A side that left the base
+// alone yields to the other; when both sides changed it differently ours is
+// kept and the field is reported as a conflict.
+func merge3[T comparable](base, ours, theirs T) (T, bool) {
+	switch {
+	case ours == theirs, theirs == base:
+		return ours, false
+	case ours == base:
+		return theirs, false
+	}
+	return ours, true
+}
+
+// mergeActions merges one action list. The result keeps our order; actions
+// only they added are placed after the action that precedes them in theirs.
+func mergeActions(section string, base, ours, theirs []Action) ([]Action, []MergeConflict) {
+	baseEntries, ourEntries, theirEntries := keyedActions(base), keyedActions(ours), keyedActions(theirs)
+	merged := append([]keyedAction{}, ourEntries...)
+	var conflicts []MergeConflict
+
+	keys := []string{}
+	for _, entries := range [][]keyedAction{ourEntries, theirEntries, baseEntries} {
+		for _, entry := range entries {
+			if !slices.Contains(keys, entry.key) {
+				keys = append(keys, entry.key)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		b, o, t := optionalAction(baseEntries, key), optionalAction(ourEntries, key), optionalAction(theirEntries, key)
+		value, conflict := merge3(b, o, t)
+		if conflict {
+			conflicts = append(conflicts, MergeConflict{Field: section + "." + key, Base: b.orNil(), Ours: o.orNil(), Theirs: t.orNil()})
+		}
+		if value == o {
+			continue
+		}
+
+		index := -1
+		for i, entry := range merged {
+			if entry.key == key {
+				index = i
+			}
+		}
+		switch {
+		case !value.ok:
+			merged = append(merged[:index], merged[index+1:]...)
+		case index >= 0:
+			merged[index].action = value.value
+		default:
+			at := 0
+			for _, entry := range theirEntries {
+				if entry.key == key {
+					break
+				}
+				for i, m := range merged {
+					if m.key == entry.key {
+						at = i + 1
+					}
+				}
+			}
+			merged = append(merged[:at], append([]keyedAction{{key, value.value}}, merged[at:]...)...)
+		}
+	}
+
+	var actions []Action
+	for _, entry := range merged {
+		actions = append(actions, entry.action)
+	}
+	return actions, conflicts
+}
+
+type creatureField struct {
+	name  string
+	value reflect.Value
+}
+
+// creatureFields lists the scalar fields of the creature, including those
+// nested in Speed and AbilityScores, named by their JSON paths.
+func creatureFields(creature *Creature) []creatureField {
+	return scalarFields(reflect.ValueOf(creature).Elem(), "")
+}
+
+func scalarFields(v reflect.Value, prefix string) []creatureField {
+	var fields []creatureField
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			fields = append(fields, scalarFields(field, prefix+name+".")...)
+		case reflect.String, reflect.Int, reflect.Bool:
+			fields = append(fields, creatureField{prefix + name, field})
+		}
+	}
+	return fields
+}
+
+type actionList struct {
+	name    string
+	actions *[]Action
+}
+
+func (creature *Creature) actionLists() []actionList {
+	return []actionList{
+		{"traits", &creature.Traits},
+		{"actions", &creature.Actions},
+		{"bonusActions", &creature.BonusActions},
+		{"reactions", &creature.Reactions},
+		{"legendaryActions", &creature.LegendaryActions},
+		{"mythicActions", &creature.MythicActions},
+		{"lairActions", &creature.LairActions},
+		{"regionalEffects", &creature.RegionalEffects},
+		{"options", &creature.Options},
+	}
+}
+
+func (creature *Creature) extras(name string) map[string]Extension {
+	if name == "extraProperties" {
+		return creature.ExtraProperties
+	}
+	return creature.ExtraSections
+}
+
+func (creature *Creature) setExtras(name string, extras map[string]Extension) {
+	if name == "extraProperties" {
+		creature.ExtraProperties = extras
+	} else {
+		creature.ExtraSections = extras
+	}
+}
+
+// keyedAction identifies an action by its name. A repeated name is numbered,
+// e.g. "Claw (2)", so that both copies can be told apart.
+type keyedAction struct {
+	key    string
+	action Action
+}
+
+func keyedActions(actions []Action) []keyedAction {
+	seen := map[string]int{}
+	var entries []keyedAction
+	for _, action := range actions {
+		seen[action.Name]++
+		key := action.Name
+		if seen[action.Name] > 1 {
+			key += " (" + strconv.Itoa(seen[action.Name]) + ")"
+		}
+		entries = append(entries, keyedAction{key, action})
+	}
+	return entries
+}
+
+func findAction(entries []keyedAction, key string) (keyedAction, bool) {
+	for _, entry := range entries {
+		if entry.key == key {
+			return entry, true
+		}
+	}
+	return keyedAction{}, false
+}
+
+// optional is a value that one side of a diff or merge may not have.
+type optional[T comparable] struct {
+	value T
+	ok    bool
+}
+
+func (o optional[T]) orNil() any {
+	if !o.ok {
+		return nil
+	}
+	return o.value
+}
+
+func optionalAction(entries []keyedAction, key string) optional[Action] {
+	entry, ok := findAction(entries, key)
+	return optional[Action]{entry.action, ok}
+}
+
+func optionalExtra(extras map[string]Extension, key string) optional[Extension] {
+	value, ok := extras[key]
+	return optional[Extension]{value, ok}
+}
+
+func unionKeys(a, b map[string]Extension) map[string]Extension {
+	union := map[string]Extension{}
+	for key, value := range a {
+		union[key] = value
+	}
+	for key, value := range b {
+		union[key] = value
+	}
+	return union
+}
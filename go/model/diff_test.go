@@ -0,0 +1,172 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func diffBaseCreature() Creature {
+	creature := Creature{
+		Name:            "Bandit Captain",
+		Type:            "humanoid",
+		Size:            "Medium",
+		ArmorClass:      15,
+		HitPoints:       "65 (10d8 + 20)",
+		ChallengeRating: "2 (450 XP)",
+		Actions: []Action{
+			{Name: "Multiattack", Description: "The captain makes three melee attacks."},
+			{Name: "Scimitar", Description: "*Melee Weapon Attack:* +5 to hit, reach 5 ft., one target."},
+			{Name: "Dagger", Description: "*Melee or Ranged Weapon Attack:* +5 to hit, reach 5 ft. or range 20/60 ft., one target."},
+		},
+		Reactions:       []Action{{Name: "Parry", Description: "The captain adds 2 to its AC against one melee attack that would hit it."}},
+		ExtraProperties: map[string]Extension{"Habitat": {Value: "Coastal roads", After: "Armor Class"}},
+	}
+	creature.AbilityScores.Strength = 15
+	creature.AbilityScores.Dexterity = 16
+	return creature
+}
+
+func TestDiffCreatures(t *testing.T) {
+	from := diffBaseCreature()
+	to := diffBaseCreature()
+	to.ArmorClass = 16
+	to.AbilityScores.Strength = 17
+	to.Speed.Swim = 30
+	to.Actions = []Action{
+		{Name: "Multiattack", Description: "The captain makes two melee attacks."},
+		{Name: "Dagger", Description: from.Actions[2].Description},
+		{Name: "Light Crossbow", Description: "*Ranged Weapon Attack:* +5 to hit, range 80/320 ft., one target."},
+	}
+	to.ExtraProperties = map[string]Extension{"Ship": {Value: "The Gull", After: "Hit Points"}}
+
+	diff := DiffCreatures(from, to)
+
+	assert.Equal(t, []FieldChange{
+		{Field: "armorClass", From: 15, To: 16},
+		{Field: "speed.swim", From: 0, To: 30},
+		{Field: "abilityScores.strength", From: 15, To: 17},
+		{Field: "extraProperties.Habitat", From: Extension{Value: "Coastal roads", After: "Armor Class"}, To: nil},
+		{Field: "extraProperties.Ship", From: nil, To: Extension{Value: "The Gull", After: "Hit Points"}},
+	}, diff.Fields)
+	assert.Equal(t, []ActionChange{
+		{Section: "actions", Kind: ChangeEdited, Name: "Multiattack", From: &from.Actions[0], To: &to.Actions[0]},
+		{Section: "actions", Kind: ChangeAdded, Name: "Light Crossbow", To: &to.Actions[2]},
+		{Section: "actions", Kind: ChangeRemoved, Name: "Scimitar", From: &from.Actions[1]},
+	}, diff.Actions)
+	assert.False(t, diff.Empty())
+}
+
+func TestDiffCreaturesUnchanged(t *testing.T) {
+	creature := diffBaseCreature()
+	reordered := diffBaseCreature()
+	reordered.Actions[1], reordered.Actions[2] = reordered.Actions[2], reordered.Actions[1]
+
+	assert.True(t, DiffCreatures(creature, creature).Empty())
+	assert.True(t, DiffCreatures(creature, reordered).Empty())
+
+	data, err := json.Marshal(DiffCreatures(creature, creature))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"fields": [], "actions": []}`, string(data))
+}
+
+func TestDiffCreaturesRepeatedActionNames(t *testing.T) {
+	from := Creature{Actions: []Action{{Name: "Claw", Description: "1d6"}, {Name: "Claw", Description: "1d6"}}}
+	to := Creature{Actions: []Action{{Name: "Claw", Description: "1d6"}, {Name: "Claw", Description: "1d8"}}}
+
+	diff := DiffCreatures(from, to)
+	assert.Len(t, diff.Actions, 1)
+	assert.Equal(t, ChangeEdited, diff.Actions[0].Kind)
+	assert.Equal(t, "1d8", diff.Actions[0].To.Description)
+}
+
+func TestMergeCreatures(t *testing.T) {
+	tests := []struct {
+		name      string
+		ours      func(*Creature)
+		theirs    func(*Creature)
+		expected  func(*Creature)
+		conflicts []string
+	}{
+		{
+			name:     "independent field edits",
+			ours:     func(c *Creature) { c.ArmorClass = 16 },
+			theirs:   func(c *Creature) { c.HitPoints = "78 (12d8 + 24)" },
+			expected: func(c *Creature) { c.ArmorClass = 16; c.HitPoints = "78 (12d8 + 24)" },
+		},
+		{
+			name:     "same edit on both sides",
+			ours:     func(c *Creature) { c.AbilityScores.Strength = 16 },
+			theirs:   func(c *Creature) { c.AbilityScores.Strength = 16 },
+			expected: func(c *Creature) { c.AbilityScores.Strength = 16 },
+		},
+		{
+			name:      "conflicting field edits keep ours",
+			ours:      func(c *Creature) { c.ArmorClass = 16 },
+			theirs:    func(c *Creature) { c.ArmorClass = 17 },
+			expected:  func(c *Creature) { c.ArmorClass = 16 },
+			conflicts: []string{"armorClass"},
+		},
+		{
+			name: "actions added on both sides",
+			ours: func(c *Creature) {
+				c.Actions = append(c.Actions, Action{Name: "Light Crossbow", Description: "range 80/320 ft."})
+			},
+			theirs: func(c *Creature) {
+				c.Actions = []Action{c.Actions[0], {Name: "Leadership", Description: "For 1 minute."}, c.Actions[1], c.Actions[2]}
+			},
+			expected: func(c *Creature) {
+				c.Actions = []Action{c.Actions[0], {Name: "Leadership", Description: "For 1 minute."}, c.Actions[1], c.Actions[2], {Name: "Light Crossbow", Description: "range 80/320 ft."}}
+			},
+		},
+		{
+			name:     "action edited by us and removed by them",
+			ours:     func(c *Creature) { c.Actions[0].Description = "The captain makes two melee attacks." },
+			theirs:   func(c *Creature) { c.Actions = c.Actions[1:] },
+			expected: func(c *Creature) { c.Actions[0].Description = "The captain makes two melee attacks." },
+			conflicts: []string{
+				"actions.Multiattack",
+			},
+		},
+		{
+			name:     "list removed by them",
+			ours:     func(c *Creature) { c.Actions[2].Description = "Thrown." },
+			theirs:   func(c *Creature) { c.Reactions = nil; c.Actions = c.Actions[:2] },
+			expected: func(c *Creature) { c.Reactions = nil; c.Actions[2].Description = "Thrown." },
+			conflicts: []string{
+				"actions.Dagger",
+			},
+		},
+		{
+			name:   "extras",
+			ours:   func(c *Creature) { c.ExtraSections = map[string]Extension{"LOOT": {Value: "A map"}} },
+			theirs: func(c *Creature) { c.ExtraProperties = nil },
+			expected: func(c *Creature) {
+				c.ExtraSections = map[string]Extension{"LOOT": {Value: "A map"}}
+				c.ExtraProperties = nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ours, theirs, expected := diffBaseCreature(), diffBaseCreature(), diffBaseCreature()
+			tt.ours(&ours)
+			tt.theirs(&theirs)
+			tt.expected(&expected)
+
+			merged, conflicts := MergeCreatures(diffBaseCreature(), ours, theirs)
+
+			fields := []string{}
+			for _, conflict := range conflicts {
+				fields = append(fields, conflict.Field)
+			}
+			if tt.conflicts == nil {
+				tt.conflicts = []string{}
+			}
+			assert.Equal(t, tt.conflicts, fields)
+			assert.Equal(t, expected, merged)
+		})
+	}
+}
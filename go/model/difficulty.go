@@ -0,0 +1,107 @@
+package model
+
+type Difficulty string
+
+const (
+	DifficultyTrivial Difficulty = "trivial"
+	DifficultyEasy    Difficulty = "easy"
+	DifficultyMedium  Difficulty = "medium"
+	DifficultyHard    Difficulty = "hard"
+	DifficultyDeadly  Difficulty = "deadly"
+)
+
+// XPThresholds are the adjusted XP at which an encounter becomes easy,
+// medium, hard or deadly for a party.
+type XPThresholds struct {
+	Easy   int `json:"easy"`
+	Medium int `json:"medium"`
+	Hard   int `json:"hard"`
+	Deadly int `json:"deadly"`
+}
+
+// DifficultyRating is how an encounter measures up against a party.
+type DifficultyRating struct {
+	Thresholds XPThresholds `json:"thresholds"`
+	XP         int          `json:"xp"`
+	Multiplier float64      `json:"multiplier"`
+	AdjustedXP int          `json:"adjustedXp"`
+	Difficulty Difficulty   `json:"difficulty"`
+}
+
+var levelThresholds = [20]XPThresholds{
+	{25, 50, 75, 100}, {50, 100, 150, 200}, {75, 150, 225, 400}, {125, 250, 375, 500},
+	{250, 500, 750, 1100}, {300, 600, 900, 1400}, {350, 750, 1100, 1700}, {450, 900, 1400, 2100},
+	{550, 1100, 1600, 2400}, {600, 1200, 1900, 2800}, {800, 1600, 2400, 3600}, {1000, 2000, 3000, 4500},
+	{1100, 2200, 3400, 5100}, {1250, 2500, 3800, 5700}, {1400, 2800, 4300, 6400}, {1600, 3200, 4800, 7200},
+	{2000, 3900, 5900, 8800}, {2100, 4200, 6300, 9500}, {2400, 4900, 7300, 10900}, {2800, 5700, 8500, 12700},
+}
+
+var encounterMultipliers = []float64{0.5, 1, 1.5, 2, 2.5, 3, 4, 5}
+
+// PartyThresholds adds up the XP thresholds of characters of the given
+// levels.
+func PartyThresholds(levels []int) XPThresholds {
+	var thresholds XPThresholds
+	for _, level := range levels {
+		level := levelThresholds[min(max(level, 1), 20)-1]
+		thresholds.Easy += level.Easy
+		thresholds.Medium += level.Medium
+		thresholds.Hard += level.Hard
+		thresholds.Deadly += level.Deadly
+	}
+	return thresholds
+}
+
+// EncounterDifficulty rates an encounter with monsters of the given
+// challenge ratings against a party of the given levels, following the
+// Dungeon Master's Guide: the monsters' XP is multiplied for their number,
+// one step more for a party of fewer than three and one step less for a
+// party of six or more.
+func EncounterDifficulty(levels []int, challenges []string) DifficultyRating {
+	rating := DifficultyRating{Thresholds: PartyThresholds(levels)}
+	monsters := 0
+	for _, challenge := range challenges {
+		if xp, ok := ChallengeXP(challenge); ok {
+			rating.XP += xp
+			monsters++
+		}
+	}
+
+	step := 1
+	switch {
+	case monsters >= 15:
+		step = 6
+	case monsters >= 11:
+		step = 5
+	case monsters >= 7:
+		step = 4
+	case monsters >= 3:
+		step = 3
+	case monsters == 2:
+		step = 2
+	}
+	switch {
+	case len(levels) < 3:
+		step++
+	case len(levels) >= 6:
+		step--
+	}
+	rating.Multiplier = encounterMultipliers[step]
+	rating.AdjustedXP = int(float64(rating.XP) * rating.Multiplier)
+
+	switch {
+	case rating.XP == 0:
+		rating.Difficulty = DifficultyTrivial
+	case rating.AdjustedXP >= rating.Thresholds.Deadly:
+		rating.Difficulty = DifficultyDeadly
+	case rating.AdjustedXP >= rating.Thresholds.Hard:
+		rating.Difficulty = DifficultyHard
+	case rating.AdjustedXP >= rating.Thresholds.Medium:
+		rating.Difficulty = DifficultyMedium
+	case rating.AdjustedXP >= rating.Thresholds.Easy:
+		rating.Difficulty = DifficultyEasy
+	default:
+		rating.Difficulty = DifficultyTrivial
+	}
+	return rating
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartyThresholds(t *testing.T) {
+	assert.Equal(t, XPThresholds{Easy: 1000, Medium: 2000, Hard: 3000, Deadly: 4400}, PartyThresholds([]int{5, 5, 5, 5}))
+	assert.Equal(t, XPThresholds{Easy: 25, Medium: 50, Hard: 75, Deadly: 100}, PartyThresholds([]int{0}))
+	assert.Equal(t, XPThresholds{Easy: 2800, Medium: 5700, Hard: 8500, Deadly: 12700}, PartyThresholds([]int{25}))
+}
+
+func TestEncounterDifficulty(t *testing.T) {
+	tests := []struct {
+		name       string
+		levels     []int
+		challenges []string
+		xp         int
+		multiplier float64
+		difficulty Difficulty
+	}{
+		{"no monsters", []int{3, 3, 3, 3}, nil, 0, 1, DifficultyTrivial},
+		{"one ogre", []int{3, 3, 3, 3}, []string{"2"}, 450, 1, DifficultyEasy},
+		{"goblin pack", []int{3, 3, 3, 3}, []string{"1/4", "1/4", "1/4", "1/4", "1 (200 XP)"}, 400, 2, DifficultyMedium},
+		{"small party", []int{1, 1}, []string{"1/4"}, 50, 1.5, DifficultyEasy},
+		{"large party", []int{1, 1, 1, 1, 1, 1}, []string{"1/4", "1/4"}, 100, 1, DifficultyTrivial},
+		{"dragon", []int{5, 5, 5, 5}, []string{"10"}, 5900, 1, DifficultyDeadly},
+		{"unknown challenges are skipped", []int{3, 3, 3, 3}, []string{"2", "—"}, 450, 1, DifficultyEasy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rating := EncounterDifficulty(tt.levels, tt.challenges)
+			assert.Equal(t, tt.xp, rating.XP)
+			assert.Equal(t, tt.multiplier, rating.Multiplier)
+			assert.Equal(t, int(float64(tt.xp)*tt.multiplier), rating.AdjustedXP)
+			assert.Equal(t, tt.difficulty, rating.Difficulty)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Encounter is a fight prepared ahead of the session, kept as a block with
+// Logseq properties for where it happens and who it is for.
+type Encounter struct {
+	Name     string `json:"name"`
+	Location string `json:"location,omitempty"`
+	// Party references the party page the encounter is built for.
+	Party string `json:"party,omitempty"`
+	// Difficulty is how hard the encounter is expected to be; see Rate.
+	Difficulty Difficulty         `json:"difficulty,omitempty"`
+	Monsters   []EncounterMonster `json:"monsters"`
+	Treasure   string             `json:"treasure,omitempty"`
+	Notes      string             `json:"notes,omitempty"`
+	// Properties keeps any other block properties, such as tags.
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// EncounterMonster is a group of the same monster. Reference is its stat
+// block's page or block, e.g. "[[Goblin]]".
+type EncounterMonster struct {
+	Name      string `json:"name"`
+	Reference string `json:"reference"`
+	Count     int    `json:"count"`
+	Challenge string `json:"challenge,omitempty"`
+}
+
+var (
+	blockPropertyRegex = regexp.MustCompile(`^([A-Za-z][\w-]*)::\s*(.*)$`)
+	encounterColumns   = []string{"Monster", "Count", "CR", "XP"}
+)
+
+// Challenges lists the challenge rating of every monster, one per creature,
+// as the difficulty calculator expects.
+func (encounter *Encounter) Challenges() []string {
+	challenges := []string{}
+	for _, monster := range encounter.Monsters {
+		for i := 0; i < monster.Count; i++ {
+			challenges = append(challenges, monster.Challenge)
+		}
+	}
+	return challenges
+}
+
+// TotalXP is the experience the monsters are worth, before any multiplier
+// for their number.
+func (encounter *Encounter) TotalXP() int {
+	total := 0
+	for _, challenge := range encounter.Challenges() {
+		xp, _ := ChallengeXP(challenge)
+		total += xp
+	}
+	return total
+}
+
+// FillChallenges takes the challenge rating of each monster that has none
+// from its stat block, keyed by reference.
+func (encounter *Encounter) FillChallenges(statBlocks map[string]string) {
+	for i := range encounter.Monsters {
+		monster := &encounter.Monsters[i]
+		statBlock, ok := statBlocks[monster.Reference]
+		if monster.Challenge != "" || !ok {
+			continue
+		}
+		var creature Creature
+		if creature.FromMarkdown(statBlock) == nil {
+			monster.Challenge = challengeRating(creature.ChallengeRating)
+		}
+	}
+}
+
+// Rate works out the encounter's difficulty for the party and records it as
+// the expected difficulty.
+func (encounter *Encounter) Rate(party Party) DifficultyRating {
+	rating := party.Difficulty(encounter.Challenges())
+	encounter.Difficulty = rating.Difficulty
+	return rating
+}
+
+// ToInitiativeTracker sets up the encounter for play: the party, if given,
+// and every monster, from its stat block keyed by reference. Dice are rolled
+// from the seed, so the same seed always gives the same initiative order and
+// hit points.
+func (encounter *Encounter) ToInitiativeTracker(statBlocks map[string]string, party *Party, rollHP bool, seed int64) (InitiativeTracker, error) {
+	it := InitiativeTracker{Combatants: []Combatant{}, Round: 1}
+	roll := SeededRoller(seed)
+	if party != nil {
+		it.AddParty(*party, roll)
+	}
+	for _, monster := range encounter.Monsters {
+		spec := CombatantSpec{Reference: monster.Reference, Count: monster.Count}
+		if err := it.AddFromStatBlock(statBlocks[monster.Reference], spec, rollHP, roll); err != nil {
+			return it, fmt.Errorf("%s: %w", monster.Name, err)
+		}
+	}
+	return it, nil
+}
+
+func (encounter *Encounter) FromMarkdown(content string) error {
+	*encounter = Encounter{Monsters: []EncounterMonster{}}
+	if content == "" {
+		return nil
+	}
+
+	var section string
+	sections := map[string][]string{}
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if match := sectionHeaderRegex.FindStringSubmatch(trimmedLine); len(match) > 1 {
+			section = strings.TrimSpace(match[1])
+			continue
+		}
+		if strings.HasPrefix(trimmedLine, "### ") {
+			encounter.Name = strings.TrimSpace(strings.TrimPrefix(trimmedLine, "### "))
+			continue
+		}
+		if section != "" {
+			if trimmedLine != "---" {
+				sections[section] = append(sections[section], line)
+			}
+			continue
+		}
+		if match := blockPropertyRegex.FindStringSubmatch(trimmedLine); len(match) > 2 {
+			encounter.setProperty(match[1], strings.TrimSpace(match[2]))
+		}
+	}
+	encounter.Treasure = strings.TrimSpace(strings.Join(sections["TREASURE"], "\n"))
+	encounter.Notes = strings.TrimSpace(strings.Join(sections["NOTES"], "\n"))
+
+	for _, table := range ParseTables(content) {
+		if !table.HasColumns("Monster") {
+			continue
+		}
+		for i := range table.Rows {
+			spec := ParseCombatantSpec(table.Get(i, "Monster"))
+			if count, err := strconv.Atoi(table.Get(i, "Count")); err == nil {
+				spec.Count = count
+			}
+			name, _ := ReferenceTarget(spec.Reference)
+			encounter.Monsters = append(encounter.Monsters, EncounterMonster{
+				Name:      name,
+				Reference: spec.Reference,
+				Count:     spec.Count,
+				Challenge: challengeRating(table.Get(i, "CR")),
+			})
+		}
+	}
+	return nil
+}
+
+func (encounter *Encounter) setProperty(key string, value string) {
+	switch strings.ToLower(key) {
+	case "location":
+		encounter.Location = value
+	case "party":
+		encounter.Party = value
+	case "difficulty":
+		encounter.Difficulty = Difficulty(strings.ToLower(value))
+	default:
+		if encounter.Properties == nil {
+			encounter.Properties = map[string]string{}
+		}
+		encounter.Properties[key] = value
+	}
+}
+
+func (encounter *Encounter) ToMarkdown() (string, error) {
+	md := fmt.Sprintf("### %s\n", encounter.Name)
+	for _, property := range []struct{ key, value string }{
+		{"location", encounter.Location},
+		{"party", encounter.Party},
+		{"difficulty", string(encounter.Difficulty)},
+	} {
+		if property.value != "" {
+			md += fmt.Sprintf("%s:: %s\n", property.key, property.value)
+		}
+	}
+	for _, key := range sortedKeys(encounter.Properties) {
+		md += fmt.Sprintf("%s:: %s\n", key, encounter.Properties[key])
+	}
+	md += "---\n"
+
+	monsters := Table{Header: encounterColumns, Align: []Alignment{AlignLeft, AlignCenter, AlignCenter, AlignCenter}}
+	for _, monster := range encounter.Monsters {
+		xp := ""
+		if each, ok := ChallengeXP(monster.Challenge); ok {
+			xp = formatThousands(each * monster.Count)
+		}
+		monsters.AddRow(monster.Reference, strconv.Itoa(monster.Count), monster.Challenge, xp)
+	}
+	md += monsters.String() + "\n"
+	md += "---\n"
+
+	count := len(encounter.Challenges())
+	md += fmt.Sprintf("**Monsters:** %d, **Total XP:** %s\n", count, formatThousands(encounter.TotalXP()))
+
+	if encounter.Treasure != "" {
+		md += fmt.Sprintf("\n**TREASURE**\n---\n%s\n", encounter.Treasure)
+	}
+	if encounter.Notes != "" {
+		md += fmt.Sprintf("\n**NOTES**\n---\n%s\n", encounter.Notes)
+	}
+	return strings.TrimSpace(md), nil
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readEncounter(t *testing.T) (Encounter, string) {
+	content, err := os.ReadFile(filepath.Join("testdata", "encounters", "ambush-at-the-ford.md"))
+	assert.NoError(t, err)
+	var encounter Encounter
+	assert.NoError(t, encounter.FromMarkdown(string(content)))
+	return encounter, string(content)
+}
+
+func readStatBlocks(t *testing.T) map[string]string {
+	statBlocks := map[string]string{}
+	for reference, file := range map[string]string{"[[Goblin]]": "goblin.md", "[[Bandit Captain]]": "bandit-captain.md"} {
+		content, err := os.ReadFile(filepath.Join("testdata", "statblocks", file))
+		assert.NoError(t, err)
+		statBlocks[reference] = string(content)
+	}
+	return statBlocks
+}
+
+func TestEncounterFromMarkdown(t *testing.T) {
+	encounter, _ := readEncounter(t)
+
+	assert.Equal(t, Encounter{
+		Name:       "Ambush at the Ford",
+		Location:   "[[Sunless Marsh]]",
+		Party:      "[[The Lantern Bearers]]",
+		Difficulty: DifficultyMedium,
+		Monsters: []EncounterMonster{
+			{Name: "Goblin", Reference: "[[Goblin]]", Count: 4, Challenge: "1/4"},
+			{Name: "Bandit Captain", Reference: "[[Bandit Captain]]", Count: 1, Challenge: "2"},
+		},
+		Treasure:   "The captain carries 35 gp and a silver locket worth 25 gp.",
+		Notes:      "The goblins hide in the reeds on the far bank; the captain waits on the bridge.",
+		Properties: map[string]string{"tags": "[[Session 4]]"},
+	}, encounter)
+	assert.Equal(t, 650, encounter.TotalXP())
+	assert.Equal(t, []string{"1/4", "1/4", "1/4", "1/4", "2"}, encounter.Challenges())
+}
+
+func TestEncounterMarkdownRoundTrip(t *testing.T) {
+	encounter, content := readEncounter(t)
+
+	md, err := encounter.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, string(content[:len(content)-1]), md)
+
+	var empty Encounter
+	assert.NoError(t, empty.FromMarkdown(""))
+	assert.Equal(t, Encounter{Monsters: []EncounterMonster{}}, empty)
+}
+
+func TestEncounterMonsterCells(t *testing.T) {
+	var encounter Encounter
+	assert.NoError(t, encounter.FromMarkdown("### Crypt\n| Monster | CR |\n| --- | --- |\n| Zombie x6 | 1/4 (50 XP) |\n| ((64f1c2a0-1b2c)) | 3 |"))
+	assert.Equal(t, []EncounterMonster{
+		{Name: "Zombie", Reference: "[[Zombie]]", Count: 6, Challenge: "1/4"},
+		{Name: "64f1c2a0-1b2c", Reference: "((64f1c2a0-1b2c))", Count: 1, Challenge: "3"},
+	}, encounter.Monsters)
+}
+
+func TestEncounterRate(t *testing.T) {
+	encounter, _ := readEncounter(t)
+	content, err := os.ReadFile(filepath.Join("testdata", "parties", "lantern-bearers.md"))
+	assert.NoError(t, err)
+	var party Party
+	assert.NoError(t, party.FromMarkdown(string(content)))
+
+	encounter.Difficulty = ""
+	rating := encounter.Rate(party)
+	assert.Equal(t, 650, rating.XP)
+	assert.Equal(t, 1300, rating.AdjustedXP)
+	assert.Equal(t, DifficultyMedium, rating.Difficulty)
+	assert.Equal(t, DifficultyMedium, encounter.Difficulty)
+
+	encounter.Monsters = append(encounter.Monsters, EncounterMonster{Name: "Ogre", Reference: "[[Ogre]]", Count: 2, Challenge: "2"})
+	assert.Equal(t, DifficultyDeadly, encounter.Rate(party).Difficulty)
+}
+
+func TestEncounterFillChallenges(t *testing.T) {
+	encounter := Encounter{Monsters: []EncounterMonster{
+		{Name: "Goblin", Reference: "[[Goblin]]", Count: 2},
+		{Name: "Bandit Captain", Reference: "[[Bandit Captain]]", Count: 1, Challenge: "3"},
+		{Name: "Ogre", Reference: "[[Ogre]]", Count: 1},
+	}}
+	encounter.FillChallenges(readStatBlocks(t))
+
+	assert.Equal(t, "1/4", encounter.Monsters[0].Challenge)
+	assert.Equal(t, "3", encounter.Monsters[1].Challenge, "a rating already set is kept")
+	assert.Equal(t, "", encounter.Monsters[2].Challenge)
+}
+
+func TestEncounterToInitiativeTracker(t *testing.T) {
+	encounter, _ := readEncounter(t)
+	statBlocks := readStatBlocks(t)
+
+	it, err := encounter.ToInitiativeTracker(statBlocks, nil, false, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, it.Round)
+	assert.ElementsMatch(t, []string{"Goblin 1", "Goblin 2", "Goblin 3", "Goblin 4", "Bandit Captain"}, combatantNames(it.Combatants))
+	for _, c := range it.Combatants {
+		if c.Name == "Bandit Captain" {
+			assert.Equal(t, 65, c.HitPoints)
+			assert.Equal(t, "[[Bandit Captain]]", c.Reference)
+		}
+	}
+
+	again, err := encounter.ToInitiativeTracker(statBlocks, nil, false, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, it, again, "the same seed sets up the same fight")
+
+	rolled, err := encounter.ToInitiativeTracker(statBlocks, nil, true, 42)
+	assert.NoError(t, err)
+	assert.NotEqual(t, it, rolled)
+
+	content, err := os.ReadFile(filepath.Join("testdata", "parties", "lantern-bearers.md"))
+	assert.NoError(t, err)
+	var party Party
+	assert.NoError(t, party.FromMarkdown(string(content)))
+	withParty, err := encounter.ToInitiativeTracker(statBlocks, &party, false, 7)
+	assert.NoError(t, err)
+	assert.Len(t, withParty.Combatants, 8)
+	assert.Contains(t, combatantNames(withParty.Combatants), "Mira Thorne")
+
+	encounter.Monsters[0].Count = 0
+	_, err = encounter.ToInitiativeTracker(statBlocks, nil, false, 42)
+	assert.ErrorContains(t, err, "Goblin")
+}
@@ -0,0 +1,263 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Equipment is a piece of mundane or magic equipment. Weapons and armor
+// carry their combat details.
+type Equipment struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	// ItemType is how a magic item's description names its type, e.g.
+	// "Wondrous item" or "Armor (plate)". TypeLine prefers it to Category.
+	ItemType    string  `json:"itemType,omitempty"`
+	Cost        string  `json:"cost,omitempty"`
+	Weight      float64 `json:"weight,omitempty"`
+	Weapon      *Weapon `json:"weapon,omitempty"`
+	Armor       *Armor  `json:"armor,omitempty"`
+	Rarity      string  `json:"rarity,omitempty"`
+	Attunement  string  `json:"attunement,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+type Weapon struct {
+	// Category is "Simple" or "Martial", and Range "Melee" or "Ranged".
+	Category   string   `json:"category"`
+	Range      string   `json:"range"`
+	Damage     string   `json:"damage"`
+	DamageType string   `json:"damageType"`
+	Versatile  string   `json:"versatile,omitempty"`
+	Properties []string `json:"properties,omitempty"`
+	// NormalRange and LongRange are in feet, for ranged and thrown weapons.
+	NormalRange int `json:"normalRange,omitempty"`
+	LongRange   int `json:"longRange,omitempty"`
+}
+
+type Armor struct {
+	// Category is "Light", "Medium", "Heavy" or "Shield".
+	Category string `json:"category"`
+	BaseAC   int    `json:"baseAc"`
+	DexBonus bool   `json:"dexBonus,omitempty"`
+	// MaxDexBonus caps the Dexterity bonus; 0 with DexBonus means no cap.
+	MaxDexBonus         int  `json:"maxDexBonus,omitempty"`
+	StrengthMinimum     int  `json:"strengthMinimum,omitempty"`
+	StealthDisadvantage bool `json:"stealthDisadvantage,omitempty"`
+}
+
+// EquipmentIndex holds equipment ordered by name for searching.
+type EquipmentIndex struct {
+	equipment []Equipment
+}
+
+func NewEquipmentIndex(equipment []Equipment) *EquipmentIndex {
+	index := &EquipmentIndex{equipment: append([]Equipment{}, equipment...)}
+	sort.SliceStable(index.equipment, func(i, j int) bool { return index.equipment[i].Name < index.equipment[j].Name })
+	return index
+}
+
+// Find returns the equipment with the given name, ignoring case.
+func (index *EquipmentIndex) Find(name string) (Equipment, bool) {
+	for _, equipment := range index.equipment {
+		if strings.EqualFold(equipment.Name, name) {
+			return equipment, true
+		}
+	}
+	return Equipment{}, false
+}
+
+// Search returns the equipment whose name contains the query and, when a
+// category is given, that belongs to it, e.g. "Weapon" or "Wondrous Items".
+func (index *EquipmentIndex) Search(query string, category string) []Equipment {
+	query = strings.ToLower(strings.TrimSpace(query))
+	results := []Equipment{}
+	for _, equipment := range index.equipment {
+		if category != "" && !strings.EqualFold(equipment.Category, category) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(equipment.Name), query) {
+			results = append(results, equipment)
+		}
+	}
+	return results
+}
+
+// HasProperty reports whether the weapon has a property such as "Finesse".
+func (weapon *Weapon) HasProperty(property string) bool {
+	return slices.ContainsFunc(weapon.Properties, func(p string) bool { return strings.EqualFold(p, property) })
+}
+
+// ArmorClass is the armor class the armor gives a wearer with the given
+// Dexterity score. A shield's is the bonus it adds.
+func (armor *Armor) ArmorClass(dexterity int) int {
+	if !armor.DexBonus {
+		return armor.BaseAC
+	}
+	bonus := modifier(dexterity)
+	if armor.MaxDexBonus > 0 {
+		bonus = min(bonus, armor.MaxDexBonus)
+	}
+	return armor.BaseAC + bonus
+}
+
+// TypeLine describes the item, e.g. "Martial melee weapon", "Light armor" or
+// "Wondrous item, rare (requires attunement)".
+func (equipment *Equipment) TypeLine() string {
+	line := equipment.Category
+	switch {
+	case equipment.ItemType != "":
+		line = equipment.ItemType
+	case equipment.Weapon != nil:
+		line = fmt.Sprintf("%s %s weapon", equipment.Weapon.Category, strings.ToLower(equipment.Weapon.Range))
+	case equipment.Armor != nil && equipment.Armor.Category == "Shield":
+		line = "Shield"
+	case equipment.Armor != nil:
+		line = fmt.Sprintf("%s armor", equipment.Armor.Category)
+	}
+	if equipment.Rarity != "" {
+		line += ", " + strings.ToLower(equipment.Rarity)
+	}
+	if equipment.Attunement != "" {
+		line += fmt.Sprintf(" (%s)", equipment.Attunement)
+	}
+	return line
+}
+
+func (equipment *Equipment) ToMarkdown() (string, error) {
+	md := fmt.Sprintf("### %s\n", equipment.Name)
+	md += fmt.Sprintf("*%s*\n", equipment.TypeLine())
+
+	properties := Table{Header: []string{"Property", "Value"}, Align: []Alignment{AlignLeft, AlignLeft}}
+	addRow := func(name, value string) {
+		if value != "" {
+			properties.AddRow(fmt.Sprintf("**%s**", name), value)
+		}
+	}
+	addRow("Cost", equipment.Cost)
+	if equipment.Weight != 0 {
+		addRow("Weight", strconv.FormatFloat(equipment.Weight, 'f', -1, 64)+" lb.")
+	}
+	if weapon := equipment.Weapon; weapon != nil {
+		addRow("Damage", strings.TrimSpace(weapon.Damage+" "+strings.ToLower(weapon.DamageType)))
+		var properties []string
+		for _, property := range weapon.Properties {
+			switch {
+			case strings.EqualFold(property, "Versatile") && weapon.Versatile != "":
+				property += fmt.Sprintf(" (%s)", weapon.Versatile)
+			case (strings.EqualFold(property, "Thrown") || strings.EqualFold(property, "Ammunition")) && weapon.NormalRange != 0:
+				property += fmt.Sprintf(" (range %d/%d)", weapon.NormalRange, weapon.LongRange)
+			}
+			properties = append(properties, property)
+		}
+		addRow("Properties", strings.Join(properties, ", "))
+	}
+	if armor := equipment.Armor; armor != nil {
+		armorClass := strconv.Itoa(armor.BaseAC)
+		switch {
+		case armor.Category == "Shield":
+			armorClass = fmt.Sprintf("+%d", armor.BaseAC)
+		case armor.DexBonus && armor.MaxDexBonus > 0:
+			armorClass += fmt.Sprintf(" + Dex modifier (max %d)", armor.MaxDexBonus)
+		case armor.DexBonus:
+			armorClass += " + Dex modifier"
+		}
+		addRow("Armor Class", armorClass)
+		if armor.StrengthMinimum != 0 {
+			addRow("Strength", fmt.Sprintf("Str %d", armor.StrengthMinimum))
+		}
+		if armor.StealthDisadvantage {
+			addRow("Stealth", "Disadvantage")
+		}
+	}
+	if len(properties.Rows) > 0 {
+		md += "---\n"
+		md += properties.String() + "\n"
+	}
+
+	if equipment.Description != "" {
+		md += "---\n"
+		md += equipment.Description + "\n"
+	}
+	return strings.TrimSpace(md), nil
+}
+
+// WeaponAttack writes the attack a creature makes with the weapon. Melee
+// weapons use Strength and ranged weapons Dexterity; finesse weapons use the
+// better of the two. The creature adds its proficiency bonus to hit.
+func (creature *Creature) WeaponAttack(equipment Equipment) (Action, error) {
+	weapon := equipment.Weapon
+	if weapon == nil {
+		return Action{}, fmt.Errorf("%s is not a weapon", equipment.Name)
+	}
+	damage, err := ParseDice(weapon.Damage)
+	if err != nil {
+		return Action{}, err
+	}
+
+	ranged := strings.EqualFold(weapon.Range, "Ranged")
+	bonus := modifier(creature.AbilityScores.Strength)
+	if ranged {
+		bonus = modifier(creature.AbilityScores.Dexterity)
+	}
+	if weapon.HasProperty("Finesse") {
+		bonus = max(modifier(creature.AbilityScores.Strength), modifier(creature.AbilityScores.Dexterity))
+	}
+	proficiency := creature.ProficiencyBonus
+	if proficiency == 0 {
+		proficiency = ProficiencyForChallenge(creature.ChallengeRating)
+	}
+
+	reach := 5
+	if weapon.HasProperty("Reach") {
+		reach = 10
+	}
+	var kind, distance string
+	switch {
+	case ranged:
+		kind, distance = "Ranged", fmt.Sprintf("range %d/%d ft.", weapon.NormalRange, weapon.LongRange)
+	case weapon.HasProperty("Thrown"):
+		kind, distance = "Melee or Ranged", fmt.Sprintf("reach %d ft. or range %d/%d ft.", reach, weapon.NormalRange, weapon.LongRange)
+	default:
+		kind, distance = "Melee", fmt.Sprintf("reach %d ft.", reach)
+	}
+
+	damage.Modifier = bonus
+	hit := fmt.Sprintf("%d (%s) %s damage", damage.Average(), damage, strings.ToLower(weapon.DamageType))
+	if weapon.Versatile != "" {
+		if versatile, err := ParseDice(weapon.Versatile); err == nil {
+			versatile.Modifier = bonus
+			hit += fmt.Sprintf(", or %d (%s) %s damage if used with two hands", versatile.Average(), versatile, strings.ToLower(weapon.DamageType))
+		}
+	}
+
+	return Action{
+		Name:        equipment.Name,
+		Description: fmt.Sprintf("*%s Weapon Attack:* %+d to hit, %s, one target. *Hit:* %s.", kind, bonus+proficiency, distance, hit),
+	}, nil
+}
+
+// AddWeaponAttack adds the weapon's attack to the creature's actions, or
+// replaces an action of the same name.
+func (creature *Creature) AddWeaponAttack(equipment Equipment) error {
+	action, err := creature.WeaponAttack(equipment)
+	if err != nil {
+		return err
+	}
+	for i, existing := range creature.Actions {
+		if strings.EqualFold(existing.Name, action.Name) {
+			creature.Actions[i] = action
+			return nil
+		}
+	}
+	creature.Actions = append(creature.Actions, action)
+	return nil
+}
+
+// Document makes the equipment searchable.
+func (equipment *Equipment) Document() Document {
+	return Document{Kind: "item", Name: equipment.Name, Text: equipment.TypeLine() + "\n" + equipment.Description}
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	longsword = Equipment{Name: "Longsword", Category: "Weapon", Cost: "15 gp", Weight: 3, Weapon: &Weapon{
+		Category: "Martial", Range: "Melee", Damage: "1d8", DamageType: "Slashing", Versatile: "1d10", Properties: []string{"Versatile"},
+	}}
+	rapier = Equipment{Name: "Rapier", Category: "Weapon", Weapon: &Weapon{
+		Category: "Martial", Range: "Melee", Damage: "1d8", DamageType: "Piercing", Properties: []string{"Finesse"},
+	}}
+	javelin = Equipment{Name: "Javelin", Category: "Weapon", Weapon: &Weapon{
+		Category: "Simple", Range: "Melee", Damage: "1d6", DamageType: "Piercing", Properties: []string{"Thrown"}, NormalRange: 30, LongRange: 120,
+	}}
+	longbow = Equipment{Name: "Longbow", Category: "Weapon", Weapon: &Weapon{
+		Category: "Martial", Range: "Ranged", Damage: "1d8", DamageType: "Piercing", Properties: []string{"Ammunition", "Heavy", "Two-Handed"}, NormalRange: 150, LongRange: 600,
+	}}
+	glaive = Equipment{Name: "Glaive", Category: "Weapon", Weapon: &Weapon{
+		Category: "Martial", Range: "Melee", Damage: "1d10", DamageType: "Slashing", Properties: []string{"Heavy", "Reach", "Two-Handed"},
+	}}
+	halfPlate = Equipment{Name: "Half Plate", Category: "Armor", Cost: "750 gp", Weight: 40, Armor: &Armor{
+		Category: "Medium", BaseAC: 15, DexBonus: true, MaxDexBonus: 2, StealthDisadvantage: true,
+	}}
+)
+
+func TestCreatureWeaponAttack(t *testing.T) {
+	creature := Creature{Name: "Veteran", ChallengeRating: "3 (700 XP)", AbilityScores: AbilityScores{Strength: 16, Dexterity: 13}}
+
+	tests := []struct {
+		name     string
+		weapon   Equipment
+		expected string
+	}{
+		{"versatile", longsword, "*Melee Weapon Attack:* +5 to hit, reach 5 ft., one target. *Hit:* 7 (1d8 + 3) slashing damage, or 8 (1d10 + 3) slashing damage if used with two hands."},
+		{"finesse", rapier, "*Melee Weapon Attack:* +5 to hit, reach 5 ft., one target. *Hit:* 7 (1d8 + 3) piercing damage."},
+		{"thrown", javelin, "*Melee or Ranged Weapon Attack:* +5 to hit, reach 5 ft. or range 30/120 ft., one target. *Hit:* 6 (1d6 + 3) piercing damage."},
+		{"ranged", longbow, "*Ranged Weapon Attack:* +3 to hit, range 150/600 ft., one target. *Hit:* 5 (1d8 + 1) piercing damage."},
+		{"reach", glaive, "*Melee Weapon Attack:* +5 to hit, reach 10 ft., one target. *Hit:* 8 (1d10 + 3) slashing damage."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, err := creature.WeaponAttack(tt.weapon)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.weapon.Name, action.Name)
+			assert.Equal(t, tt.expected, action.Description)
+		})
+	}
+
+	sneaky := Creature{ProficiencyBonus: 4, AbilityScores: AbilityScores{Strength: 8, Dexterity: 18}}
+	action, err := sneaky.WeaponAttack(rapier)
+	assert.NoError(t, err)
+	assert.Contains(t, action.Description, "+8 to hit")
+
+	_, err = creature.WeaponAttack(halfPlate)
+	assert.EqualError(t, err, "Half Plate is not a weapon")
+}
+
+func TestCreatureAddWeaponAttack(t *testing.T) {
+	creature := Creature{ChallengeRating: "1", AbilityScores: AbilityScores{Strength: 14, Dexterity: 12},
+		Actions: []Action{{Name: "Multiattack", Description: "Two attacks."}, {Name: "Longsword", Description: "Old."}}}
+
+	assert.NoError(t, creature.AddWeaponAttack(longsword))
+	assert.NoError(t, creature.AddWeaponAttack(longbow))
+	assert.Equal(t, []string{"Multiattack", "Longsword", "Longbow"}, actionNames(creature.Actions))
+	assert.NotEqual(t, "Old.", creature.Actions[1].Description)
+}
+
+func TestArmorClass(t *testing.T) {
+	tests := []struct {
+		name      string
+		armor     Armor
+		dexterity int
+		expected  int
+	}{
+		{"light", Armor{Category: "Light", BaseAC: 11, DexBonus: true}, 18, 15},
+		{"medium capped", Armor{Category: "Medium", BaseAC: 15, DexBonus: true, MaxDexBonus: 2}, 18, 17},
+		{"medium below cap", Armor{Category: "Medium", BaseAC: 15, DexBonus: true, MaxDexBonus: 2}, 8, 14},
+		{"heavy", Armor{Category: "Heavy", BaseAC: 18}, 18, 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.armor.ArmorClass(tt.dexterity))
+		})
+	}
+}
+
+func TestEquipmentToMarkdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		equipment Equipment
+		expected  string
+	}{
+		{"weapon", longsword, `### Longsword
+*Martial melee weapon*
+---
+| Property | Value |
+| :--- | :--- |
+| **Cost** | 15 gp |
+| **Weight** | 3 lb. |
+| **Damage** | 1d8 slashing |
+| **Properties** | Versatile (1d10) |`},
+		{"armor", halfPlate, `### Half Plate
+*Medium armor*
+---
+| Property | Value |
+| :--- | :--- |
+| **Cost** | 750 gp |
+| **Weight** | 40 lb. |
+| **Armor Class** | 15 + Dex modifier (max 2) |
+| **Stealth** | Disadvantage |`},
+		{"magic item", Equipment{Name: "Cloak of Protection", Category: "Wondrous Items", ItemType: "Wondrous item", Rarity: "Uncommon", Attunement: "requires attunement", Description: "You gain a +1 bonus to AC and saving throws while you wear this cloak."}, `### Cloak of Protection
+*Wondrous item, uncommon (requires attunement)*
+---
+You gain a +1 bonus to AC and saving throws while you wear this cloak.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markdown, err := tt.equipment.ToMarkdown()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, markdown)
+		})
+	}
+}
+
+func TestEquipmentIndex(t *testing.T) {
+	index := NewEquipmentIndex([]Equipment{longsword, halfPlate, longbow, rapier, {Name: "Rope, hempen (50 feet)", Category: "Adventuring Gear"}})
+
+	names := func(equipment []Equipment) []string {
+		result := []string{}
+		for _, e := range equipment {
+			result = append(result, e.Name)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"Longbow", "Longsword"}, names(index.Search("long", "")))
+	assert.Equal(t, []string{"Half Plate"}, names(index.Search("", "armor")))
+	assert.Equal(t, []string{}, names(index.Search("plate", "weapon")))
+
+	equipment, ok := index.Find("rapier")
+	assert.True(t, ok)
+	assert.True(t, equipment.Weapon.HasProperty("finesse"))
+}
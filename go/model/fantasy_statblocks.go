@@ -0,0 +1,260 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fantasyStatblock is the 5e layout of the Obsidian Fantasy Statblocks
+// plugin, as written in a "statblock" code block or a note's frontmatter.
+type fantasyStatblock struct {
+	Name                  string                   `yaml:"name"`
+	Size                  string                   `yaml:"size,omitempty"`
+	Type                  string                   `yaml:"type,omitempty"`
+	Subtype               string                   `yaml:"subtype,omitempty"`
+	Alignment             string                   `yaml:"alignment,omitempty"`
+	AC                    any                      `yaml:"ac,omitempty"`
+	HP                    any                      `yaml:"hp,omitempty"`
+	HitDice               string                   `yaml:"hit_dice,omitempty"`
+	Speed                 string                   `yaml:"speed,omitempty"`
+	Stats                 []int                    `yaml:"stats,flow,omitempty"`
+	Saves                 []map[string]int         `yaml:"saves,omitempty"`
+	SkillSaves            []map[string]int         `yaml:"skillsaves,omitempty"`
+	DamageVulnerabilities string                   `yaml:"damage_vulnerabilities,omitempty"`
+	DamageResistances     string                   `yaml:"damage_resistances,omitempty"`
+	DamageImmunities      string                   `yaml:"damage_immunities,omitempty"`
+	ConditionImmunities   string                   `yaml:"condition_immunities,omitempty"`
+	Senses                string                   `yaml:"senses,omitempty"`
+	Languages             string                   `yaml:"languages,omitempty"`
+	CR                    any                      `yaml:"cr,omitempty"`
+	Traits                []fantasyStatblockAction `yaml:"traits,omitempty"`
+	Actions               []fantasyStatblockAction `yaml:"actions,omitempty"`
+	BonusActions          []fantasyStatblockAction `yaml:"bonus_actions,omitempty"`
+	Reactions             []fantasyStatblockAction `yaml:"reactions,omitempty"`
+	LegendaryDescription  string                   `yaml:"legendary_description,omitempty"`
+	LegendaryActions      []fantasyStatblockAction `yaml:"legendary_actions,omitempty"`
+	MythicActions         []fantasyStatblockAction `yaml:"mythic_actions,omitempty"`
+	LairActions           []fantasyStatblockAction `yaml:"lair_actions,omitempty"`
+	RegionalEffects       []fantasyStatblockAction `yaml:"regional_effects,omitempty"`
+}
+
+type fantasyStatblockAction struct {
+	Name string `yaml:"name"`
+	Desc string `yaml:"desc"`
+}
+
+var fantasyStatblockAbilities = []string{"strength", "dexterity", "constitution", "intelligence", "wisdom", "charisma"}
+
+// FromFantasyStatblock reads Fantasy Statblocks YAML. The YAML may be wrapped
+// in a ```statblock fence or be the frontmatter of a note.
+func (creature *Creature) FromFantasyStatblock(content string) error {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimSuffix(content, "```")
+		_, content, _ = strings.Cut(content, "\n")
+	} else if strings.HasPrefix(content, "---\n") {
+		content, _, _ = strings.Cut(strings.TrimPrefix(content, "---\n"), "\n---")
+	}
+
+	var statblock fantasyStatblock
+	if err := yaml.Unmarshal([]byte(content), &statblock); err != nil {
+		return err
+	}
+	if statblock.Name == "" {
+		return errors.New("statblock has no name")
+	}
+
+	creature.Name = statblock.Name
+	creature.Size = statblock.Size
+	creature.Type = statblock.Type
+	creature.Species = statblock.Subtype
+	creature.Alignment = statblock.Alignment
+	if statblock.AC != nil {
+		creature.setProperty("Armor Class", fmt.Sprint(statblock.AC))
+	}
+	if statblock.HP != nil {
+		creature.HitPoints = fmt.Sprint(statblock.HP)
+		if statblock.HitDice != "" {
+			creature.HitPoints += fmt.Sprintf(" (%s)", statblock.HitDice)
+		}
+	}
+	creature.setProperty("Speed", statblock.Speed)
+
+	scores := []*int{
+		&creature.AbilityScores.Strength,
+		&creature.AbilityScores.Dexterity,
+		&creature.AbilityScores.Constitution,
+		&creature.AbilityScores.Intelligence,
+		&creature.AbilityScores.Wisdom,
+		&creature.AbilityScores.Charisma,
+	}
+	for i, score := range scores {
+		*score = 10
+		if i < len(statblock.Stats) {
+			*score = statblock.Stats[i]
+		}
+	}
+
+	var saves []string
+	for _, save := range statblock.Saves {
+		for ability, bonus := range save {
+			if ability == "" {
+				continue
+			}
+			name := strings.ToUpper(ability[:1]) + ability[1:min(3, len(ability))]
+			saves = append(saves, fmt.Sprintf("%s %+d", name, bonus))
+		}
+	}
+	creature.SavingThrows = strings.Join(saves, ", ")
+
+	var skills []string
+	for _, skill := range statblock.SkillSaves {
+		for name, bonus := range skill {
+			skills = append(skills, fmt.Sprintf("%s %+d", skillTitle(name), bonus))
+		}
+	}
+	creature.Skills = strings.Join(skills, ", ")
+
+	creature.DamageVulnerabilities = statblock.DamageVulnerabilities
+	creature.DamageResistances = statblock.DamageResistances
+	creature.DamageImmunities = statblock.DamageImmunities
+	creature.ConditionImmunities = statblock.ConditionImmunities
+	creature.Senses = statblock.Senses
+	creature.Languages = statblock.Languages
+	switch cr := statblock.CR.(type) {
+	case float64:
+		creature.ChallengeRating = formatChallenge(formatChallengeValue(cr))
+	case nil:
+	default:
+		creature.ChallengeRating = formatChallenge(fmt.Sprint(cr))
+	}
+
+	creature.Traits = fantasyStatblockActions(statblock.Traits)
+	creature.Actions = fantasyStatblockActions(statblock.Actions)
+	creature.BonusActions = fantasyStatblockActions(statblock.BonusActions)
+	creature.Reactions = fantasyStatblockActions(statblock.Reactions)
+	creature.LegendaryActions = fantasyStatblockActions(statblock.LegendaryActions)
+	creature.MythicActions = fantasyStatblockActions(statblock.MythicActions)
+	creature.LairActions = fantasyStatblockActions(statblock.LairActions)
+	creature.RegionalEffects = fantasyStatblockActions(statblock.RegionalEffects)
+	if len(creature.LegendaryActions) > 0 {
+		creature.LegendaryActionCount = 3
+		creature.LegendaryActionsIntro = statblock.LegendaryDescription
+		if match := legendaryCountRegex.FindStringSubmatch(statblock.LegendaryDescription); len(match) > 1 {
+			creature.LegendaryActionCount, _ = strconv.Atoi(match[1])
+		}
+	}
+
+	return nil
+}
+
+// ToFantasyStatblock writes the creature as Fantasy Statblocks YAML, ready to
+// paste into a ```statblock code block.
+func (creature *Creature) ToFantasyStatblock() (string, error) {
+	statblock := fantasyStatblock{
+		Name:                  creature.Name,
+		Size:                  creature.Size,
+		Type:                  creature.Type,
+		Subtype:               creature.Species,
+		Alignment:             creature.Alignment,
+		DamageVulnerabilities: creature.DamageVulnerabilities,
+		DamageResistances:     creature.DamageResistances,
+		DamageImmunities:      creature.DamageImmunities,
+		ConditionImmunities:   creature.ConditionImmunities,
+		Senses:                creature.Senses,
+		Languages:             creature.Languages,
+		Stats: []int{
+			creature.AbilityScores.Strength,
+			creature.AbilityScores.Dexterity,
+			creature.AbilityScores.Constitution,
+			creature.AbilityScores.Intelligence,
+			creature.AbilityScores.Wisdom,
+			creature.AbilityScores.Charisma,
+		},
+		Traits:           fantasyStatblockEntries(creature.Traits),
+		Actions:          fantasyStatblockEntries(creature.Actions),
+		BonusActions:     fantasyStatblockEntries(creature.BonusActions),
+		Reactions:        fantasyStatblockEntries(creature.Reactions),
+		LegendaryActions: fantasyStatblockEntries(creature.LegendaryActions),
+		MythicActions:    fantasyStatblockEntries(creature.MythicActions),
+		LairActions:      fantasyStatblockEntries(creature.LairActions),
+		RegionalEffects:  fantasyStatblockEntries(creature.RegionalEffects),
+	}
+
+	for _, row := range creature.propertyRows() {
+		switch row.name {
+		case "Armor Class":
+			statblock.AC = row.value
+			if creature.ArmorType == "" {
+				statblock.AC = creature.ArmorClass
+			}
+		case "Speed":
+			statblock.Speed = row.value
+		}
+	}
+	if creature.HitPoints != "" {
+		average, dice, _ := strings.Cut(creature.HitPoints, " (")
+		statblock.HP = creature.HitPoints
+		if value, err := strconv.Atoi(strings.TrimSpace(average)); err == nil {
+			statblock.HP = value
+			statblock.HitDice = strings.TrimSuffix(dice, ")")
+		}
+	}
+
+	for _, save := range splitList(creature.SavingThrows) {
+		if match := fiveEToolsSaveRegex.FindStringSubmatch(save); len(match) > 2 {
+			for _, ability := range fantasyStatblockAbilities {
+				if strings.HasPrefix(ability, strings.ToLower(match[1])) {
+					bonus, _ := strconv.Atoi(match[2])
+					statblock.Saves = append(statblock.Saves, map[string]int{ability: bonus})
+				}
+			}
+		}
+	}
+	for _, skill := range splitList(creature.Skills) {
+		if match := fiveEToolsSkillRegex.FindStringSubmatch(skill); len(match) > 2 {
+			bonus, _ := strconv.Atoi(match[2])
+			statblock.SkillSaves = append(statblock.SkillSaves, map[string]int{strings.ToLower(match[1]): bonus})
+		}
+	}
+
+	if rating := challengeRating(creature.ChallengeRating); rating != "" {
+		statblock.CR = rating
+		if value, err := strconv.Atoi(rating); err == nil {
+			statblock.CR = value
+		}
+	}
+	if len(creature.LegendaryActions) > 0 && creature.LegendaryActionCount > 0 {
+		for _, section := range creature.sections() {
+			if section.name == "LEGENDARY ACTIONS" {
+				statblock.LegendaryDescription, _, _ = strings.Cut(section.value, "\n\n***")
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(statblock)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func fantasyStatblockActions(entries []fantasyStatblockAction) []Action {
+	var actions []Action
+	for _, entry := range entries {
+		actions = append(actions, Action{Name: entry.Name, Description: strings.TrimSpace(entry.Desc)})
+	}
+	return actions
+}
+
+func fantasyStatblockEntries(actions []Action) []fantasyStatblockAction {
+	var entries []fantasyStatblockAction
+	for _, action := range actions {
+		entries = append(entries, fantasyStatblockAction{Name: action.Name, Desc: action.Description})
+	}
+	return entries
+}
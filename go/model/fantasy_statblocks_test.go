@@ -0,0 +1,85 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatureFromFantasyStatblock(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "fantasy-statblocks", "goblin.md"))
+	assert.NoError(t, err)
+
+	var expected Creature
+	expected.Name = "Goblin"
+	expected.Size = "Small"
+	expected.Type = "humanoid"
+	expected.Species = "goblinoid"
+	expected.Alignment = "neutral evil"
+	expected.ArmorClass = 15
+	expected.ArmorType = "leather armor, shield"
+	expected.HitPoints = "7 (2d6)"
+	expected.Speed.Base = 30
+	expected.AbilityScores.Strength = 8
+	expected.AbilityScores.Dexterity = 14
+	expected.AbilityScores.Constitution = 10
+	expected.AbilityScores.Intelligence = 10
+	expected.AbilityScores.Wisdom = 8
+	expected.AbilityScores.Charisma = 8
+	expected.Skills = "Stealth +6"
+	expected.Senses = "darkvision 60 ft., passive Perception 9"
+	expected.Languages = "Common, Goblin"
+	expected.ChallengeRating = "1/4 (50 XP)"
+	expected.Traits = []Action{{Name: "Nimble Escape", Description: "The goblin can take the Disengage or Hide action as a bonus action on each of its turns."}}
+	expected.Actions = []Action{
+		{Name: "Scimitar", Description: "*Melee Weapon Attack:* +4 to hit, reach 5 ft., one target. *Hit:* 5 (1d6 + 2) slashing damage."},
+		{Name: "Shortbow", Description: "*Ranged Weapon Attack:* +4 to hit, range 80/320 ft., one target. *Hit:* 5 (1d6 + 2) piercing damage."},
+	}
+
+	var creature Creature
+	assert.NoError(t, creature.FromFantasyStatblock(string(content)))
+	assert.Equal(t, expected, creature)
+
+	var frontmatter Creature
+	assert.NoError(t, frontmatter.FromFantasyStatblock("---\nname: Goblin\ncr: 0.25\n---\n# Goblin notes"))
+	assert.Equal(t, "Goblin", frontmatter.Name)
+	assert.Equal(t, "1/4 (50 XP)", frontmatter.ChallengeRating)
+
+	var saves Creature
+	assert.NoError(t, saves.FromFantasyStatblock("```statblock\nname: Goblin\nsaves: [{\"\": 3}, {dexterity: 4}]\n```"))
+	assert.Equal(t, "Dex +4", saves.SavingThrows)
+
+	assert.Error(t, frontmatter.FromFantasyStatblock("size: Small"))
+}
+
+func TestCreatureFantasyStatblockRoundTrip(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "statblocks", "*.md"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			assert.NoError(t, err)
+
+			var creature Creature
+			assert.NoError(t, creature.FromMarkdown(string(content)))
+			yaml, err := creature.ToFantasyStatblock()
+			assert.NoError(t, err)
+
+			var reparsed Creature
+			assert.NoError(t, reparsed.FromFantasyStatblock(yaml))
+
+			// Fantasy Statblocks has no fields for these.
+			creature.ProficiencyBonus = 0
+			creature.Options = nil
+			creature.Description = ""
+			creature.Notes = ""
+			creature.ExtraProperties = nil
+			creature.ExtraSections = nil
+			assert.Equal(t, creature, reparsed)
+		})
+	}
+}
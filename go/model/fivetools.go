@@ -0,0 +1,852 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fiveEToolsMonster is the part of the 5etools bestiary schema that maps onto
+// Creature. Lair actions and regional effects live in separate legendary
+// group files in 5etools and are not written.
+type fiveEToolsMonster struct {
+	Name             string             `json:"name"`
+	Source           string             `json:"source,omitempty"`
+	Size             []string           `json:"size,omitempty"`
+	Type             *fiveEToolsType    `json:"type,omitempty"`
+	Alignment        []fiveEToolsText   `json:"alignment,omitempty"`
+	AC               []fiveEToolsArmor  `json:"ac,omitempty"`
+	HP               *fiveEToolsHP      `json:"hp,omitempty"`
+	Speed            *fiveEToolsSpeed   `json:"speed,omitempty"`
+	Str              int                `json:"str"`
+	Dex              int                `json:"dex"`
+	Con              int                `json:"con"`
+	Int              int                `json:"int"`
+	Wis              int                `json:"wis"`
+	Cha              int                `json:"cha"`
+	Save             map[string]string  `json:"save,omitempty"`
+	Skill            map[string]string  `json:"skill,omitempty"`
+	Vulnerable       []fiveEToolsText   `json:"vulnerable,omitempty"`
+	Resist           []fiveEToolsText   `json:"resist,omitempty"`
+	Immune           []fiveEToolsText   `json:"immune,omitempty"`
+	ConditionImmune  []fiveEToolsText   `json:"conditionImmune,omitempty"`
+	Senses           []string           `json:"senses,omitempty"`
+	Passive          json.RawMessage    `json:"passive,omitempty"`
+	Languages        []string           `json:"languages,omitempty"`
+	CR               *fiveEToolsCR      `json:"cr,omitempty"`
+	Spellcasting     []fiveEToolsSpells `json:"spellcasting,omitempty"`
+	Trait            []fiveEToolsEntry  `json:"trait,omitempty"`
+	Action           []fiveEToolsEntry  `json:"action,omitempty"`
+	Bonus            []fiveEToolsEntry  `json:"bonus,omitempty"`
+	Reaction         []fiveEToolsEntry  `json:"reaction,omitempty"`
+	LegendaryHeader  []json.RawMessage  `json:"legendaryHeader,omitempty"`
+	LegendaryActions int                `json:"legendaryActions,omitempty"`
+	Legendary        []fiveEToolsEntry  `json:"legendary,omitempty"`
+	Mythic           []fiveEToolsEntry  `json:"mythic,omitempty"`
+}
+
+type fiveEToolsHP struct {
+	Average int    `json:"average,omitempty"`
+	Formula string `json:"formula,omitempty"`
+	Special string `json:"special,omitempty"`
+}
+
+type fiveEToolsSpeed struct {
+	Walk     fiveEToolsSpeedValue `json:"walk,omitempty"`
+	Burrow   fiveEToolsSpeedValue `json:"burrow,omitempty"`
+	Climb    fiveEToolsSpeedValue `json:"climb,omitempty"`
+	Fly      fiveEToolsSpeedValue `json:"fly,omitempty"`
+	Swim     fiveEToolsSpeedValue `json:"swim,omitempty"`
+	CanHover bool                 `json:"canHover,omitempty"`
+}
+
+type fiveEToolsEntry struct {
+	Name    string            `json:"name"`
+	Entries []json.RawMessage `json:"entries"`
+}
+
+type fiveEToolsSpells struct {
+	Name          string                     `json:"name"`
+	HeaderEntries []json.RawMessage          `json:"headerEntries"`
+	Will          []string                   `json:"will"`
+	Daily         map[string][]string        `json:"daily"`
+	Spells        map[string]fiveEToolsLevel `json:"spells"`
+	FooterEntries []json.RawMessage          `json:"footerEntries"`
+	DisplayAs     string                     `json:"displayAs"`
+}
+
+type fiveEToolsLevel struct {
+	Slots  int      `json:"slots"`
+	Spells []string `json:"spells"`
+}
+
+// fiveEToolsSpeedValue is a speed in feet, written either as a number or as
+// {"number": 30, "condition": "..."}.
+type fiveEToolsSpeedValue int
+
+func (v *fiveEToolsSpeedValue) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*v = fiveEToolsSpeedValue(number)
+		return nil
+	}
+	var object struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*v = fiveEToolsSpeedValue(object.Number)
+	return nil
+}
+
+// fiveEToolsType is a creature type, written either as a plain string or as
+// {"type": "humanoid", "tags": ["goblinoid"]}.
+type fiveEToolsType struct {
+	Type string
+	Tags []string
+}
+
+func (t *fiveEToolsType) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Type); err == nil {
+		return nil
+	}
+	var object struct {
+		Type json.RawMessage   `json:"type"`
+		Tags []json.RawMessage `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(object.Type, &t.Type); err != nil {
+		var choice struct {
+			Choose []string `json:"choose"`
+		}
+		json.Unmarshal(object.Type, &choice)
+		t.Type = strings.Join(choice.Choose, " or ")
+	}
+	for _, raw := range object.Tags {
+		var tag string
+		if err := json.Unmarshal(raw, &tag); err != nil {
+			var prefixed struct {
+				Tag    string `json:"tag"`
+				Prefix string `json:"prefix"`
+			}
+			json.Unmarshal(raw, &prefixed)
+			tag = strings.TrimSpace(prefixed.Prefix + " " + prefixed.Tag)
+		}
+		t.Tags = append(t.Tags, tag)
+	}
+	return nil
+}
+
+func (t fiveEToolsType) MarshalJSON() ([]byte, error) {
+	if len(t.Tags) == 0 {
+		return json.Marshal(t.Type)
+	}
+	return json.Marshal(struct {
+		Type string   `json:"type"`
+		Tags []string `json:"tags"`
+	}{t.Type, t.Tags})
+}
+
+// fiveEToolsArmor is an armor class entry, written either as a number or as
+// {"ac": 15, "from": ["leather armor"]}.
+type fiveEToolsArmor struct {
+	AC   int
+	From []string
+}
+
+func (a *fiveEToolsArmor) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.AC); err == nil {
+		return nil
+	}
+	var object struct {
+		AC   int      `json:"ac"`
+		From []string `json:"from"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	a.AC = object.AC
+	a.From = object.From
+	return nil
+}
+
+func (a fiveEToolsArmor) MarshalJSON() ([]byte, error) {
+	if len(a.From) == 0 {
+		return json.Marshal(a.AC)
+	}
+	return json.Marshal(struct {
+		AC   int      `json:"ac"`
+		From []string `json:"from"`
+	}{a.AC, a.From})
+}
+
+// fiveEToolsCR is a challenge rating, written either as a string or as
+// {"cr": "21", "lair": "22"}.
+type fiveEToolsCR struct {
+	CR   string
+	Lair string
+}
+
+func (c *fiveEToolsCR) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.CR); err == nil {
+		return nil
+	}
+	var object struct {
+		CR   string `json:"cr"`
+		Lair string `json:"lair"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	c.CR = object.CR
+	c.Lair = object.Lair
+	return nil
+}
+
+func (c fiveEToolsCR) MarshalJSON() ([]byte, error) {
+	if c.Lair == "" {
+		return json.Marshal(c.CR)
+	}
+	return json.Marshal(struct {
+		CR   string `json:"cr"`
+		Lair string `json:"lair"`
+	}{c.CR, c.Lair})
+}
+
+// fiveEToolsText is a list element that is either a plain string or an
+// object carrying a nested list with a note, or free text under "special".
+type fiveEToolsText struct {
+	Text    string
+	Special bool
+}
+
+func (t *fiveEToolsText) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Text); err == nil {
+		return nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	t.Special = true
+	if special, ok := object["special"]; ok {
+		return json.Unmarshal(special, &t.Text)
+	}
+	var parts []string
+	var preNote, note string
+	json.Unmarshal(object["preNote"], &preNote)
+	json.Unmarshal(object["note"], &note)
+	for _, key := range []string{"vulnerable", "resist", "immune", "conditionImmune", "alignment"} {
+		var nested []fiveEToolsText
+		if raw, ok := object[key]; ok && json.Unmarshal(raw, &nested) == nil {
+			for _, n := range nested {
+				parts = append(parts, n.Text)
+			}
+		}
+	}
+	t.Text = strings.TrimSpace(strings.Join([]string{preNote, strings.Join(parts, ", "), note}, " "))
+	return nil
+}
+
+func (t fiveEToolsText) MarshalJSON() ([]byte, error) {
+	if !t.Special {
+		return json.Marshal(t.Text)
+	}
+	return json.Marshal(struct {
+		Special string `json:"special"`
+	}{t.Text})
+}
+
+var (
+	fiveEToolsTagRegex       = regexp.MustCompile(`\{@(\w+)(?: ([^{}]*))?\}`)
+	fiveEToolsSaveRegex      = regexp.MustCompile(`^(\w+) ([+-]\d+)$`)
+	fiveEToolsSkillRegex     = regexp.MustCompile(`^(.+?) ([+-]\d+)$`)
+	fiveEToolsPassiveRegex   = regexp.MustCompile(`^passive Perception (\d+)$`)
+	fiveEToolsLairXPRegex    = regexp.MustCompile(`or ([\d,]+) XP`)
+	fiveEToolsDamageRegex    = regexp.MustCompile(`\((\d+d\d+(?:\s*[+−-]\s*\d+)?)\)`)
+	fiveEToolsHitRegex       = regexp.MustCompile(`([+-]\d+) to hit`)
+	fiveEToolsDCRegex        = regexp.MustCompile(`\bDC (\d+)`)
+	fiveEToolsPlainListRegex = regexp.MustCompile(`^[a-z]+$`)
+	fiveEToolsAttackRegex    = regexp.MustCompile(`\*(Melee or Ranged|Melee|Ranged) (Weapon|Spell) Attack:\*`)
+	fiveEToolsAttackRoll     = regexp.MustCompile(`\*(Melee or Ranged|Melee|Ranged) Attack Roll:\*`)
+)
+
+var fiveEToolsSizes = map[string]string{
+	"T": "Tiny", "S": "Small", "M": "Medium", "L": "Large", "H": "Huge", "G": "Gargantuan",
+}
+
+var fiveEToolsAlignments = map[string]string{
+	"L": "lawful", "N": "neutral", "NX": "neutral", "NY": "neutral", "C": "chaotic",
+	"G": "good", "E": "evil", "U": "unaligned", "A": "any alignment",
+}
+
+var fiveEToolsAttacks = map[string]string{
+	"mw": "*Melee Weapon Attack:*", "rw": "*Ranged Weapon Attack:*", "mw,rw": "*Melee or Ranged Weapon Attack:*",
+	"ms": "*Melee Spell Attack:*", "rs": "*Ranged Spell Attack:*", "ms,rs": "*Melee or Ranged Spell Attack:*",
+	"m": "*Melee Attack Roll:*", "r": "*Ranged Attack Roll:*", "m,r": "*Melee or Ranged Attack Roll:*",
+}
+
+var abilityAbbreviations = []string{"str", "dex", "con", "int", "wis", "cha"}
+
+// FromFiveETools reads a 5etools monster, either on its own or as the first
+// entry of a {"monster": [...]} homebrew file. Tags such as {@hit 4} and
+// {@damage 1d6 + 2} are rendered as plain stat block text.
+func (creature *Creature) FromFiveETools(content string) error {
+	var file struct {
+		Monster []json.RawMessage `json:"monster"`
+	}
+	data := []byte(content)
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	if file.Monster != nil {
+		if len(file.Monster) == 0 {
+			return errors.New("5etools file contains no monsters")
+		}
+		data = file.Monster[0]
+	}
+
+	var monster fiveEToolsMonster
+	if err := json.Unmarshal(data, &monster); err != nil {
+		return err
+	}
+	if monster.Name == "" {
+		return errors.New("5etools monster has no name")
+	}
+
+	creature.Name = monster.Name
+	if len(monster.Size) > 0 {
+		creature.Size = fiveEToolsSizes[monster.Size[0]]
+	}
+	if monster.Type != nil {
+		creature.Type = monster.Type.Type
+		creature.Species = strings.Join(monster.Type.Tags, ", ")
+	}
+	creature.Alignment = fiveEToolsAlignmentText(monster.Alignment)
+	if len(monster.AC) > 0 {
+		creature.ArmorClass = monster.AC[0].AC
+		from := make([]string, len(monster.AC[0].From))
+		for i, f := range monster.AC[0].From {
+			from[i] = fiveEToolsToMarkdown(f)
+		}
+		creature.ArmorType = strings.Join(from, ", ")
+	}
+	if monster.HP != nil {
+		switch {
+		case monster.HP.Special != "":
+			creature.HitPoints = monster.HP.Special
+		case monster.HP.Formula != "":
+			creature.HitPoints = fmt.Sprintf("%d (%s)", monster.HP.Average, monster.HP.Formula)
+		default:
+			creature.HitPoints = strconv.Itoa(monster.HP.Average)
+		}
+	}
+	if monster.Speed != nil {
+		creature.Speed.Base = int(monster.Speed.Walk)
+		creature.Speed.Burrow = int(monster.Speed.Burrow)
+		creature.Speed.Climb = int(monster.Speed.Climb)
+		creature.Speed.Fly = int(monster.Speed.Fly)
+		creature.Speed.Swim = int(monster.Speed.Swim)
+		creature.Speed.Hover = monster.Speed.CanHover
+	}
+
+	creature.AbilityScores.Strength = monster.Str
+	creature.AbilityScores.Dexterity = monster.Dex
+	creature.AbilityScores.Constitution = monster.Con
+	creature.AbilityScores.Intelligence = monster.Int
+	creature.AbilityScores.Wisdom = monster.Wis
+	creature.AbilityScores.Charisma = monster.Cha
+
+	var saves []string
+	for _, ability := range abilityAbbreviations {
+		if bonus, ok := monster.Save[ability]; ok {
+			saves = append(saves, strings.ToUpper(ability[:1])+ability[1:]+" "+bonus)
+		}
+	}
+	creature.SavingThrows = strings.Join(saves, ", ")
+
+	var skills []string
+	for name, bonus := range monster.Skill {
+		skills = append(skills, skillTitle(name)+" "+bonus)
+	}
+	sort.Strings(skills)
+	creature.Skills = strings.Join(skills, ", ")
+
+	creature.DamageVulnerabilities = fiveEToolsListText(monster.Vulnerable)
+	creature.DamageResistances = fiveEToolsListText(monster.Resist)
+	creature.DamageImmunities = fiveEToolsListText(monster.Immune)
+	creature.ConditionImmunities = fiveEToolsListText(monster.ConditionImmune)
+
+	senses := make([]string, 0, len(monster.Senses)+1)
+	for _, sense := range monster.Senses {
+		senses = append(senses, fiveEToolsToMarkdown(sense))
+	}
+	if len(monster.Passive) > 0 {
+		var passive any
+		json.Unmarshal(monster.Passive, &passive)
+		senses = append(senses, fmt.Sprintf("passive Perception %v", passive))
+	}
+	creature.Senses = strings.Join(senses, ", ")
+	creature.Languages = fiveEToolsToMarkdown(strings.Join(monster.Languages, ", "))
+
+	if monster.CR != nil {
+		creature.ChallengeRating = formatChallenge(monster.CR.CR)
+		if xp, ok := challengeXP[monster.CR.Lair]; ok {
+			creature.ChallengeRating = strings.TrimSuffix(creature.ChallengeRating, ")") +
+				fmt.Sprintf(", or %s XP in lair)", formatThousands(xp))
+		}
+	}
+
+	creature.Traits = fiveEToolsActions(monster.Trait)
+	creature.Actions = fiveEToolsActions(monster.Action)
+	for _, spellcasting := range monster.Spellcasting {
+		action := spellcasting.action()
+		if spellcasting.DisplayAs == "action" {
+			creature.Actions = append(creature.Actions, action)
+		} else {
+			creature.Traits = append(creature.Traits, action)
+		}
+	}
+	creature.BonusActions = fiveEToolsActions(monster.Bonus)
+	creature.Reactions = fiveEToolsActions(monster.Reaction)
+	creature.LegendaryActions = fiveEToolsActions(monster.Legendary)
+	creature.MythicActions = fiveEToolsActions(monster.Mythic)
+	if len(creature.LegendaryActions) > 0 {
+		creature.LegendaryActionCount = monster.LegendaryActions
+		if creature.LegendaryActionCount == 0 {
+			creature.LegendaryActionCount = 3
+		}
+		creature.LegendaryActionsIntro = fiveEToolsEntriesText(monster.LegendaryHeader)
+	}
+
+	return nil
+}
+
+// ToFiveETools writes the creature as a 5etools monster with attack, hit,
+// damage, DC and recharge tags.
+func (creature *Creature) ToFiveETools() (string, error) {
+	monster := fiveEToolsMonster{
+		Name: creature.Name,
+		Str:  creature.AbilityScores.Strength,
+		Dex:  creature.AbilityScores.Dexterity,
+		Con:  creature.AbilityScores.Constitution,
+		Int:  creature.AbilityScores.Intelligence,
+		Wis:  creature.AbilityScores.Wisdom,
+		Cha:  creature.AbilityScores.Charisma,
+	}
+	for code, size := range fiveEToolsSizes {
+		if strings.EqualFold(size, creature.Size) {
+			monster.Size = []string{code}
+		}
+	}
+	if creature.Type != "" {
+		monster.Type = &fiveEToolsType{Type: creature.Type}
+		if creature.Species != "" {
+			monster.Type.Tags = strings.Split(creature.Species, ", ")
+		}
+	}
+	monster.Alignment = fiveEToolsAlignmentCodes(creature.Alignment)
+	if creature.ArmorClass != 0 {
+		armor := fiveEToolsArmor{AC: creature.ArmorClass}
+		if creature.ArmorType != "" {
+			armor.From = strings.Split(creature.ArmorType, ", ")
+		}
+		monster.AC = []fiveEToolsArmor{armor}
+	}
+	if creature.HitPoints != "" {
+		hp := fiveEToolsHP{}
+		average, formula, _ := strings.Cut(creature.HitPoints, " (")
+		value, err := strconv.Atoi(strings.TrimSpace(average))
+		if err != nil {
+			hp.Special = creature.HitPoints
+		} else {
+			hp.Average = value
+			hp.Formula = strings.TrimSuffix(formula, ")")
+		}
+		monster.HP = &hp
+	}
+	monster.Speed = &fiveEToolsSpeed{
+		Walk:     fiveEToolsSpeedValue(creature.Speed.Base),
+		Burrow:   fiveEToolsSpeedValue(creature.Speed.Burrow),
+		Climb:    fiveEToolsSpeedValue(creature.Speed.Climb),
+		Fly:      fiveEToolsSpeedValue(creature.Speed.Fly),
+		Swim:     fiveEToolsSpeedValue(creature.Speed.Swim),
+		CanHover: creature.Speed.Hover,
+	}
+
+	for _, save := range splitList(creature.SavingThrows) {
+		if match := fiveEToolsSaveRegex.FindStringSubmatch(save); len(match) > 2 {
+			if monster.Save == nil {
+				monster.Save = map[string]string{}
+			}
+			monster.Save[strings.ToLower(match[1])] = match[2]
+		}
+	}
+	for _, skill := range splitList(creature.Skills) {
+		if match := fiveEToolsSkillRegex.FindStringSubmatch(skill); len(match) > 2 {
+			if monster.Skill == nil {
+				monster.Skill = map[string]string{}
+			}
+			monster.Skill[strings.ToLower(match[1])] = match[2]
+		}
+	}
+
+	monster.Vulnerable = fiveEToolsList(creature.DamageVulnerabilities)
+	monster.Resist = fiveEToolsList(creature.DamageResistances)
+	monster.Immune = fiveEToolsList(creature.DamageImmunities)
+	monster.ConditionImmune = fiveEToolsList(creature.ConditionImmunities)
+
+	for _, sense := range splitList(creature.Senses) {
+		if match := fiveEToolsPassiveRegex.FindStringSubmatch(sense); len(match) > 1 {
+			passive, _ := strconv.Atoi(match[1])
+			monster.Passive, _ = json.Marshal(passive)
+			continue
+		}
+		monster.Senses = append(monster.Senses, sense)
+	}
+	if creature.Languages != "" && creature.Languages != "—" && creature.Languages != "-" {
+		monster.Languages = splitList(creature.Languages)
+	}
+
+	if rating := challengeRating(creature.ChallengeRating); rating != "" {
+		monster.CR = &fiveEToolsCR{CR: rating}
+		if match := fiveEToolsLairXPRegex.FindStringSubmatch(creature.ChallengeRating); len(match) > 1 {
+			xp, _ := strconv.Atoi(strings.ReplaceAll(match[1], ",", ""))
+			monster.CR.Lair = challengeForXP(xp)
+		}
+	}
+
+	monster.Trait = fiveEToolsEntries(creature.Traits)
+	monster.Action = fiveEToolsEntries(creature.Actions)
+	monster.Bonus = fiveEToolsEntries(creature.BonusActions)
+	monster.Reaction = fiveEToolsEntries(creature.Reactions)
+	monster.Legendary = fiveEToolsEntries(creature.LegendaryActions)
+	monster.Mythic = fiveEToolsEntries(creature.MythicActions)
+	if len(monster.Legendary) > 0 {
+		monster.LegendaryActions = creature.LegendaryActionCount
+		countMatch := legendaryCountRegex.FindStringSubmatch(creature.LegendaryActionsIntro)
+		if len(countMatch) > 1 && countMatch[1] == strconv.Itoa(creature.LegendaryActionCount) {
+			header, _ := json.Marshal(fiveEToolsFromMarkdown(creature.LegendaryActionsIntro))
+			monster.LegendaryHeader = []json.RawMessage{header}
+		}
+	}
+
+	data, err := json.MarshalIndent(monster, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s fiveEToolsSpells) action() Action {
+	paragraphs := []string{}
+	if header := fiveEToolsEntriesText(s.HeaderEntries); header != "" {
+		paragraphs = append(paragraphs, header)
+	}
+	var lines []string
+	if len(s.Will) > 0 {
+		lines = append(lines, "At will: "+fiveEToolsToMarkdown(strings.Join(s.Will, ", ")))
+	}
+	for _, key := range sortedKeys(s.Daily) {
+		uses := strings.TrimSuffix(key, "e")
+		label := uses + "/day"
+		if strings.HasSuffix(key, "e") {
+			label += " each"
+		}
+		lines = append(lines, label+": "+fiveEToolsToMarkdown(strings.Join(s.Daily[key], ", ")))
+	}
+	for _, key := range sortedKeys(s.Spells) {
+		level := s.Spells[key]
+		spells := fiveEToolsToMarkdown(strings.Join(level.Spells, ", "))
+		if key == "0" {
+			lines = append(lines, "Cantrips (at will): "+spells)
+			continue
+		}
+		n, _ := strconv.Atoi(key)
+		slots := "slots"
+		if level.Slots == 1 {
+			slots = "slot"
+		}
+		lines = append(lines, fmt.Sprintf("%s level (%d %s): %s", strings.TrimSuffix(spellSlotName(n), "-level slots"), level.Slots, slots, spells))
+	}
+	if len(lines) > 0 {
+		paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+	}
+	if footer := fiveEToolsEntriesText(s.FooterEntries); footer != "" {
+		paragraphs = append(paragraphs, footer)
+	}
+	return Action{Name: fiveEToolsToMarkdown(s.Name), Description: strings.Join(paragraphs, "\n\n")}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fiveEToolsActions(entries []fiveEToolsEntry) []Action {
+	var actions []Action
+	for _, entry := range entries {
+		actions = append(actions, Action{
+			Name:        fiveEToolsToMarkdown(entry.Name),
+			Description: fiveEToolsEntriesText(entry.Entries),
+		})
+	}
+	return actions
+}
+
+func fiveEToolsEntries(actions []Action) []fiveEToolsEntry {
+	var entries []fiveEToolsEntry
+	for _, action := range actions {
+		entry := fiveEToolsEntry{Name: fiveEToolsFromMarkdown(action.Name)}
+		for _, paragraph := range strings.Split(action.Description, "\n\n") {
+			var value any = fiveEToolsFromMarkdown(paragraph)
+			lines := strings.Split(paragraph, "\n")
+			isList := true
+			for _, line := range lines {
+				if !strings.HasPrefix(line, "- ") {
+					isList = false
+				}
+			}
+			if isList {
+				items := make([]string, len(lines))
+				for i, line := range lines {
+					items[i] = fiveEToolsFromMarkdown(strings.TrimPrefix(line, "- "))
+				}
+				value = map[string]any{"type": "list", "items": items}
+			}
+			raw, _ := json.Marshal(value)
+			entry.Entries = append(entry.Entries, raw)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// fiveEToolsEntriesText flattens 5etools entries into paragraphs. Lists
+// become "- item" lines and named sub-entries become "***Name.*** text".
+func fiveEToolsEntriesText(entries []json.RawMessage) string {
+	var paragraphs []string
+	for _, raw := range entries {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			paragraphs = append(paragraphs, fiveEToolsToMarkdown(text))
+			continue
+		}
+		var object struct {
+			Type    string            `json:"type"`
+			Name    string            `json:"name"`
+			Items   []json.RawMessage `json:"items"`
+			Entries []json.RawMessage `json:"entries"`
+			Entry   json.RawMessage   `json:"entry"`
+		}
+		if err := json.Unmarshal(raw, &object); err != nil {
+			continue
+		}
+		if object.Type == "list" {
+			var items []string
+			for _, item := range object.Items {
+				items = append(items, "- "+strings.ReplaceAll(fiveEToolsEntriesText([]json.RawMessage{item}), "\n\n", " "))
+			}
+			paragraphs = append(paragraphs, strings.Join(items, "\n"))
+			continue
+		}
+		body := object.Entries
+		if object.Entry != nil {
+			body = append(body, object.Entry)
+		}
+		content := fiveEToolsEntriesText(body)
+		if object.Name != "" {
+			content = fmt.Sprintf("***%s.*** %s", fiveEToolsToMarkdown(object.Name), content)
+		}
+		if content != "" {
+			paragraphs = append(paragraphs, content)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// fiveEToolsToMarkdown replaces 5etools tags with the text they render as.
+func fiveEToolsToMarkdown(text string) string {
+	for fiveEToolsTagRegex.MatchString(text) {
+		text = fiveEToolsTagRegex.ReplaceAllStringFunc(text, func(tag string) string {
+			match := fiveEToolsTagRegex.FindStringSubmatch(tag)
+			name, args := match[1], match[2]
+			parts := strings.Split(args, "|")
+			switch name {
+			case "atk", "atkr":
+				return fiveEToolsAttacks[strings.ReplaceAll(args, " ", "")]
+			case "hit":
+				if strings.HasPrefix(args, "-") {
+					return args
+				}
+				return "+" + args
+			case "h":
+				return "*Hit:* "
+			case "dc":
+				return "DC " + args
+			case "recharge":
+				if args == "" || args == "6" {
+					return "(Recharge 6)"
+				}
+				return fmt.Sprintf("(Recharge %s–6)", args)
+			}
+			if len(parts) > 2 && parts[2] != "" {
+				return parts[2]
+			}
+			return parts[0]
+		})
+	}
+	return text
+}
+
+// fiveEToolsFromMarkdown adds 5etools tags to plain stat block text.
+func fiveEToolsFromMarkdown(text string) string {
+	text = fiveEToolsAttackRegex.ReplaceAllStringFunc(text, func(attack string) string {
+		for code, rendered := range fiveEToolsAttacks {
+			if rendered == attack {
+				return "{@atk " + code + "}"
+			}
+		}
+		return attack
+	})
+	text = fiveEToolsAttackRoll.ReplaceAllStringFunc(text, func(attack string) string {
+		for code, rendered := range fiveEToolsAttacks {
+			if rendered == attack {
+				return "{@atkr " + code + "}"
+			}
+		}
+		return attack
+	})
+	text = fiveEToolsHitRegex.ReplaceAllStringFunc(text, func(hit string) string {
+		bonus := strings.TrimPrefix(fiveEToolsHitRegex.FindStringSubmatch(hit)[1], "+")
+		return "{@hit " + bonus + "} to hit"
+	})
+	text = strings.ReplaceAll(text, "*Hit:* ", "{@h}")
+	text = fiveEToolsDamageRegex.ReplaceAllString(text, "({@damage $1})")
+	text = fiveEToolsDCRegex.ReplaceAllString(text, "{@dc $1}")
+	text = rechargeRegex.ReplaceAllStringFunc(text, func(recharge string) string {
+		on := rechargeRegex.FindStringSubmatch(recharge)[1]
+		if on == "6" {
+			return "{@recharge}"
+		}
+		return "{@recharge " + on + "}"
+	})
+	return text
+}
+
+func fiveEToolsAlignmentText(alignment []fiveEToolsText) string {
+	var words []string
+	for _, a := range alignment {
+		if a.Special {
+			return a.Text
+		}
+		word := fiveEToolsAlignments[a.Text]
+		if len(words) == 0 || words[len(words)-1] != word {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func fiveEToolsAlignmentCodes(alignment string) []fiveEToolsText {
+	if alignment == "" {
+		return nil
+	}
+	for _, code := range []string{"N", "U", "A"} {
+		if strings.EqualFold(fiveEToolsAlignments[code], alignment) {
+			return []fiveEToolsText{{Text: code}}
+		}
+	}
+	var codes []fiveEToolsText
+	words := strings.Fields(strings.ToLower(alignment))
+	if len(words) == 2 {
+		for _, word := range words {
+			for _, code := range []string{"L", "N", "C", "G", "E"} {
+				if fiveEToolsAlignments[code] == word {
+					codes = append(codes, fiveEToolsText{Text: code})
+					break
+				}
+			}
+		}
+		if len(codes) == 2 {
+			return codes
+		}
+	}
+	return []fiveEToolsText{{Text: alignment, Special: true}}
+}
+
+// fiveEToolsListText joins damage or condition lists: plain entries with
+// commas, and entries carrying notes with semicolons.
+func fiveEToolsListText(list []fiveEToolsText) string {
+	var plain, special []string
+	for _, item := range list {
+		if item.Special {
+			special = append(special, item.Text)
+		} else {
+			plain = append(plain, item.Text)
+		}
+	}
+	groups := special
+	if len(plain) > 0 {
+		groups = append([]string{strings.Join(plain, ", ")}, special...)
+	}
+	return strings.Join(groups, "; ")
+}
+
+func fiveEToolsList(text string) []fiveEToolsText {
+	if text == "" {
+		return nil
+	}
+	var list []fiveEToolsText
+	for i, group := range strings.Split(text, "; ") {
+		parts := splitList(group)
+		plain := i == 0
+		for _, part := range parts {
+			if !fiveEToolsPlainListRegex.MatchString(part) {
+				plain = false
+			}
+		}
+		if !plain {
+			list = append(list, fiveEToolsText{Text: group, Special: true})
+			continue
+		}
+		for _, part := range parts {
+			list = append(list, fiveEToolsText{Text: part})
+		}
+	}
+	return list
+}
+
+// splitList splits a comma separated stat block value, dropping the "and"
+// before the last item.
+func splitList(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimPrefix(strings.TrimSpace(part), "and ")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func skillTitle(skill string) string {
+	words := strings.Fields(skill)
+	for i, word := range words {
+		if word != "of" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatureFromFiveETools(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "fivetools", "goblin.json"))
+	assert.NoError(t, err)
+
+	var expected Creature
+	expected.Name = "Goblin"
+	expected.Size = "Small"
+	expected.Type = "humanoid"
+	expected.Species = "goblinoid"
+	expected.Alignment = "neutral evil"
+	expected.ArmorClass = 15
+	expected.ArmorType = "leather armor, shield"
+	expected.HitPoints = "7 (2d6)"
+	expected.Speed.Base = 30
+	expected.AbilityScores.Strength = 8
+	expected.AbilityScores.Dexterity = 14
+	expected.AbilityScores.Constitution = 10
+	expected.AbilityScores.Intelligence = 10
+	expected.AbilityScores.Wisdom = 8
+	expected.AbilityScores.Charisma = 8
+	expected.Skills = "Stealth +6"
+	expected.Senses = "darkvision 60 ft., passive Perception 9"
+	expected.Languages = "Common, Goblin"
+	expected.ChallengeRating = "1/4 (50 XP)"
+	expected.Traits = []Action{{Name: "Nimble Escape", Description: "The goblin can take the Disengage or Hide action as a bonus action on each of its turns."}}
+	expected.Actions = []Action{
+		{Name: "Scimitar", Description: "*Melee Weapon Attack:* +4 to hit, reach 5 ft., one target. *Hit:* 5 (1d6 + 2) slashing damage."},
+		{Name: "Shortbow", Description: "*Ranged Weapon Attack:* +4 to hit, range 80/320 ft., one target. *Hit:* 5 (1d6 + 2) piercing damage."},
+	}
+
+	var creature Creature
+	assert.NoError(t, creature.FromFiveETools(string(content)))
+	assert.Equal(t, expected, creature)
+}
+
+func TestCreatureFromFiveEToolsSpellcasterAndLegendary(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "fivetools", "lich.json"))
+	assert.NoError(t, err)
+
+	var creature Creature
+	assert.NoError(t, creature.FromFiveETools(string(content)))
+
+	assert.Equal(t, "any alignment", creature.Alignment)
+	assert.Equal(t, "Con +10, Int +12, Wis +9", creature.SavingThrows)
+	assert.Equal(t, "Arcana +19, History +12, Insight +9, Perception +9", creature.Skills)
+	assert.Equal(t, "poison; bludgeoning, piercing, slashing from nonmagical attacks", creature.DamageImmunities)
+	assert.Equal(t, "21 (33,000 XP, or 41,000 XP in lair)", creature.ChallengeRating)
+	assert.Equal(t, 3, creature.LegendaryActionCount)
+
+	assert.Len(t, creature.Traits, 3)
+	spellcasting := creature.Traits[2]
+	assert.Equal(t, "Spellcasting", spellcasting.Name)
+	assert.Contains(t, spellcasting.Description, "(spell save DC 20, +12 to hit with spell attacks)")
+	assert.Contains(t, spellcasting.Description, "Cantrips (at will): mage hand, prestidigitation, ray of frost\n1st level (4 slots): detect magic")
+	assert.Contains(t, spellcasting.Description, "9th level (1 slot): power word kill")
+
+	assert.Equal(t, "*Melee Spell Attack:* +12 to hit, reach 5 ft., one creature. *Hit:* 10 (3d6) cold damage. The target must succeed on a DC 18 Constitution saving throw or be paralyzed for 1 minute.", creature.Actions[0].Description)
+	assert.Equal(t, "Each non-undead creature within 20 feet of the lich must make a DC 18 Constitution saving throw against this magic:\n\n- On a failed save, it takes 21 (6d6) necrotic damage.\n- On a successful save, it takes half as much damage.", creature.LegendaryActions[2].Description)
+
+	resources := ResourcesFromCreature(creature)
+	assert.Contains(t, resources, Resource{Name: "1st-level slots", Kind: ResourceSpellSlot, Remaining: 4, Max: 4, Level: 1})
+}
+
+func TestCreatureFiveEToolsRoundTrip(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "fivetools", "*.json"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			assert.NoError(t, err)
+
+			var creature Creature
+			assert.NoError(t, creature.FromFiveETools(string(content)))
+			exported, err := creature.ToFiveETools()
+			assert.NoError(t, err)
+
+			var reparsed Creature
+			assert.NoError(t, reparsed.FromFiveETools(exported))
+			assert.Equal(t, creature, reparsed)
+		})
+	}
+}
+
+func TestFiveEToolsTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tagged   string
+		markdown string
+	}{
+		{
+			name:     "Weapon attack",
+			tagged:   "{@atk mw,rw} {@hit 4} to hit, reach 5 ft. or range 20/60 ft., one target. {@h}5 ({@damage 1d6 + 2}) piercing damage.",
+			markdown: "*Melee or Ranged Weapon Attack:* +4 to hit, reach 5 ft. or range 20/60 ft., one target. *Hit:* 5 (1d6 + 2) piercing damage.",
+		},
+		{
+			name:     "Saving throw",
+			tagged:   "Each creature must make a {@dc 21} Dexterity saving throw, taking 63 ({@damage 18d6}) fire damage.",
+			markdown: "Each creature must make a DC 21 Dexterity saving throw, taking 63 (18d6) fire damage.",
+		},
+		{
+			name:     "Recharge",
+			tagged:   "Fire Breath {@recharge 5}",
+			markdown: "Fire Breath (Recharge 5–6)",
+		},
+		{
+			name:     "Negative attack bonus",
+			tagged:   "{@atk mw} {@hit -1} to hit",
+			markdown: "*Melee Weapon Attack:* -1 to hit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.markdown, fiveEToolsToMarkdown(tt.tagged))
+			assert.Equal(t, tt.tagged, fiveEToolsFromMarkdown(tt.markdown))
+		})
+	}
+
+	assert.Equal(t, "long sword", fiveEToolsToMarkdown("{@item longsword|phb|long sword}"))
+}
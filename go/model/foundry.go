@@ -0,0 +1,647 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// foundryFlagScope namespaces the values Foundry has no field for, such as
+// the armor description and the section an item came from.
+const foundryFlagScope = "logseq-odyssey"
+
+type foundryActor struct {
+	Name   string         `json:"name"`
+	Type   string         `json:"type"`
+	System foundryNPC     `json:"system"`
+	Items  []foundryItem  `json:"items"`
+	Flags  map[string]any `json:"flags,omitempty"`
+}
+
+type foundryNPC struct {
+	Abilities  map[string]foundryAbility `json:"abilities"`
+	Attributes struct {
+		AC struct {
+			Flat int    `json:"flat"`
+			Calc string `json:"calc"`
+		} `json:"ac"`
+		HP struct {
+			Value   int    `json:"value"`
+			Max     int    `json:"max"`
+			Formula string `json:"formula"`
+		} `json:"hp"`
+		Movement struct {
+			Walk   int    `json:"walk"`
+			Burrow int    `json:"burrow"`
+			Climb  int    `json:"climb"`
+			Fly    int    `json:"fly"`
+			Swim   int    `json:"swim"`
+			Hover  bool   `json:"hover"`
+			Units  string `json:"units"`
+		} `json:"movement"`
+		Senses struct {
+			Darkvision  int    `json:"darkvision"`
+			Blindsight  int    `json:"blindsight"`
+			Tremorsense int    `json:"tremorsense"`
+			Truesight   int    `json:"truesight"`
+			Units       string `json:"units"`
+			Special     string `json:"special"`
+		} `json:"senses"`
+	} `json:"attributes"`
+	Details struct {
+		Alignment string `json:"alignment"`
+		Type      struct {
+			Value   string `json:"value"`
+			Subtype string `json:"subtype"`
+		} `json:"type"`
+		CR        float64 `json:"cr"`
+		Biography struct {
+			Value string `json:"value"`
+		} `json:"biography"`
+	} `json:"details"`
+	Traits struct {
+		Size      string       `json:"size"`
+		DI        foundryTrait `json:"di"`
+		DR        foundryTrait `json:"dr"`
+		DV        foundryTrait `json:"dv"`
+		CI        foundryTrait `json:"ci"`
+		Languages foundryTrait `json:"languages"`
+	} `json:"traits"`
+	Skills    map[string]foundrySkill `json:"skills"`
+	Resources struct {
+		Legact foundryResource `json:"legact"`
+		Legres foundryResource `json:"legres"`
+		Lair   struct {
+			Value      bool `json:"value"`
+			Initiative int  `json:"initiative"`
+		} `json:"lair"`
+	} `json:"resources"`
+}
+
+type foundryAbility struct {
+	Value      int `json:"value"`
+	Proficient int `json:"proficient"`
+}
+
+type foundryTrait struct {
+	Value  []string `json:"value"`
+	Custom string   `json:"custom"`
+}
+
+type foundrySkill struct {
+	Value   float64 `json:"value"`
+	Ability string  `json:"ability"`
+}
+
+type foundryResource struct {
+	Value int `json:"value"`
+	Max   int `json:"max"`
+}
+
+type foundryItem struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	System struct {
+		Description struct {
+			Value string `json:"value"`
+		} `json:"description"`
+		Activation struct {
+			Type string `json:"type"`
+			Cost int    `json:"cost,omitempty"`
+		} `json:"activation"`
+		Uses struct {
+			Value int    `json:"value,omitempty"`
+			Max   string `json:"max,omitempty"`
+			Per   string `json:"per,omitempty"`
+		} `json:"uses"`
+		Recharge struct {
+			Value   int  `json:"value,omitempty"`
+			Charged bool `json:"charged,omitempty"`
+		} `json:"recharge"`
+		ActionType string `json:"actionType,omitempty"`
+		Damage     struct {
+			Parts [][2]string `json:"parts,omitempty"`
+		} `json:"damage"`
+	} `json:"system"`
+	Flags map[string]any `json:"flags,omitempty"`
+}
+
+var foundrySkills = map[string]string{
+	"acr": "Acrobatics", "ani": "Animal Handling", "arc": "Arcana",
+	"ath": "Athletics", "dec": "Deception", "his": "History",
+	"ins": "Insight", "itm": "Intimidation", "inv": "Investigation",
+	"med": "Medicine", "nat": "Nature", "prc": "Perception",
+	"prf": "Performance", "per": "Persuasion", "rel": "Religion",
+	"slt": "Sleight of Hand", "ste": "Stealth", "sur": "Survival",
+}
+
+// foundrySkillAbility returns Foundry's code, such as "dex", for the ability
+// a skill uses.
+func foundrySkillAbility(code string) string {
+	ability, _ := SkillAbility(foundrySkills[code])
+	return strings.ToLower(ability[:min(3, len(ability))])
+}
+
+var foundrySizes = map[string]string{
+	"tiny": "Tiny", "sm": "Small", "med": "Medium", "lg": "Large", "huge": "Huge", "grg": "Gargantuan",
+}
+
+var foundryDamageTypes = []string{
+	"acid", "bludgeoning", "cold", "fire", "force", "lightning", "necrotic",
+	"piercing", "poison", "psychic", "radiant", "slashing", "thunder",
+}
+
+var foundryConditions = []string{
+	"blinded", "charmed", "deafened", "diseased", "exhaustion", "frightened", "grappled", "incapacitated",
+	"invisible", "paralyzed", "petrified", "poisoned", "prone", "restrained", "stunned", "unconscious",
+}
+
+var foundryLanguages = map[string]string{
+	"common": "Common", "dwarvish": "Dwarvish", "elvish": "Elvish", "giant": "Giant", "gnomish": "Gnomish",
+	"goblin": "Goblin", "halfling": "Halfling", "orc": "Orc", "abyssal": "Abyssal", "celestial": "Celestial",
+	"draconic": "Draconic", "deep": "Deep Speech", "infernal": "Infernal", "primordial": "Primordial",
+	"sylvan": "Sylvan", "undercommon": "Undercommon", "druidic": "Druidic", "cant": "Thieves' Cant",
+}
+
+// foundryActivations maps stat block sections to item activation types.
+// Sections without an activation are told apart by the item flag.
+var foundryActivations = map[string]string{
+	"TRAITS": "", "ACTIONS": "action", "BONUS ACTIONS": "bonus", "REACTIONS": "reaction",
+	"LEGENDARY ACTIONS": "legendary", "MYTHIC ACTIONS": "mythic", "LAIR ACTIONS": "lair",
+	"REGIONAL EFFECTS": "", "OPTIONS": "",
+}
+
+var (
+	foundrySenseRegex   = regexp.MustCompile(`^(darkvision|blindsight|tremorsense|truesight) (\d+) ft\.?$`)
+	foundryAttackRegex  = regexp.MustCompile(`\*(Melee|Ranged)(?: or Ranged)? (Weapon|Spell) Attack:\*`)
+	foundryDamageRegex  = regexp.MustCompile(`\((\d+d\d+(?:\s*[+−-]\s*\d+)?)\)\s+(\w+) damage`)
+	foundryBoldItalic   = regexp.MustCompile(`\*\*\*(.+?)\*\*\*`)
+	foundryBold         = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	foundryItalic       = regexp.MustCompile(`\*(.+?)\*`)
+	foundryHTMLTagRegex = regexp.MustCompile(`<[^>]+>`)
+)
+
+// FromFoundry reads a Foundry VTT dnd5e NPC actor export. Saving throws,
+// skills and passive Perception are worked out from the proficiencies and
+// challenge rating, the same way the dnd5e system displays them.
+func (creature *Creature) FromFoundry(content string) error {
+	var actor foundryActor
+	if err := json.Unmarshal([]byte(content), &actor); err != nil {
+		return err
+	}
+	if actor.Name == "" {
+		return errors.New("Foundry actor has no name")
+	}
+	if actor.Type != "" && actor.Type != "npc" {
+		return fmt.Errorf("Foundry actor %s is a %s, not an npc", actor.Name, actor.Type)
+	}
+	system := actor.System
+
+	creature.Name = actor.Name
+	creature.Size = foundrySizes[system.Traits.Size]
+	creature.Type = system.Details.Type.Value
+	creature.Species = system.Details.Type.Subtype
+	creature.Alignment = system.Details.Alignment
+
+	creature.ArmorClass = system.Attributes.AC.Flat
+	creature.ArmorType = foundryFlag(actor.Flags, "armorType")
+	if creature.ArmorType == "" && system.Attributes.AC.Calc == "natural" {
+		creature.ArmorType = "natural armor"
+	}
+	creature.HitPoints = strconv.Itoa(system.Attributes.HP.Max)
+	if system.Attributes.HP.Formula != "" {
+		creature.HitPoints += fmt.Sprintf(" (%s)", system.Attributes.HP.Formula)
+	}
+	movement := system.Attributes.Movement
+	creature.Speed.Base = movement.Walk
+	creature.Speed.Burrow = movement.Burrow
+	creature.Speed.Climb = movement.Climb
+	creature.Speed.Fly = movement.Fly
+	creature.Speed.Swim = movement.Swim
+	creature.Speed.Hover = movement.Hover
+
+	scores := map[string]*int{
+		"str": &creature.AbilityScores.Strength,
+		"dex": &creature.AbilityScores.Dexterity,
+		"con": &creature.AbilityScores.Constitution,
+		"int": &creature.AbilityScores.Intelligence,
+		"wis": &creature.AbilityScores.Wisdom,
+		"cha": &creature.AbilityScores.Charisma,
+	}
+	for ability, score := range scores {
+		*score = 10
+		if value, ok := system.Abilities[ability]; ok {
+			*score = value.Value
+		}
+	}
+
+	rating := formatChallengeValue(system.Details.CR)
+	proficiency := ProficiencyForChallenge(rating)
+	creature.ChallengeRating = formatChallenge(rating)
+
+	var saves []string
+	for _, ability := range abilityAbbreviations {
+		if system.Abilities[ability].Proficient > 0 {
+			bonus := modifier(*scores[ability]) + proficiency
+			saves = append(saves, fmt.Sprintf("%s%s %+d", strings.ToUpper(ability[:1]), ability[1:], bonus))
+		}
+	}
+	creature.SavingThrows = strings.Join(saves, ", ")
+
+	skillBonus := func(code string) int {
+		skill := system.Skills[code]
+		ability := skill.Ability
+		if ability == "" {
+			ability = foundrySkillAbility(code)
+		}
+		return modifier(*scores[ability]) + int(math.Floor(skill.Value*float64(proficiency)))
+	}
+	var skills []string
+	for code, skill := range system.Skills {
+		if _, ok := foundrySkills[code]; ok && skill.Value > 0 {
+			skills = append(skills, fmt.Sprintf("%s %+d", foundrySkills[code], skillBonus(code)))
+		}
+	}
+	sort.Strings(skills)
+	creature.Skills = strings.Join(skills, ", ")
+
+	creature.DamageVulnerabilities = foundryTraitText(system.Traits.DV, nil)
+	creature.DamageResistances = foundryTraitText(system.Traits.DR, nil)
+	creature.DamageImmunities = foundryTraitText(system.Traits.DI, nil)
+	creature.ConditionImmunities = foundryTraitText(system.Traits.CI, nil)
+	creature.Languages = foundryTraitText(system.Traits.Languages, foundryLanguages)
+
+	var senses []string
+	attributeSenses := system.Attributes.Senses
+	for _, sense := range []struct {
+		name     string
+		distance int
+	}{
+		{"blindsight", attributeSenses.Blindsight},
+		{"darkvision", attributeSenses.Darkvision},
+		{"tremorsense", attributeSenses.Tremorsense},
+		{"truesight", attributeSenses.Truesight},
+	} {
+		if sense.distance > 0 {
+			senses = append(senses, fmt.Sprintf("%s %d ft.", sense.name, sense.distance))
+		}
+	}
+	if attributeSenses.Special != "" {
+		senses = append(senses, attributeSenses.Special)
+	}
+	senses = append(senses, fmt.Sprintf("passive Perception %d", 10+skillBonus("prc")))
+	creature.Senses = strings.Join(senses, ", ")
+
+	creature.Description = htmlToMarkdown(system.Details.Biography.Value)
+
+	sections := map[string]*[]Action{
+		"TRAITS":            &creature.Traits,
+		"ACTIONS":           &creature.Actions,
+		"BONUS ACTIONS":     &creature.BonusActions,
+		"REACTIONS":         &creature.Reactions,
+		"LEGENDARY ACTIONS": &creature.LegendaryActions,
+		"MYTHIC ACTIONS":    &creature.MythicActions,
+		"LAIR ACTIONS":      &creature.LairActions,
+		"REGIONAL EFFECTS":  &creature.RegionalEffects,
+		"OPTIONS":           &creature.Options,
+	}
+	for _, item := range actor.Items {
+		if item.Type != "feat" && item.Type != "weapon" {
+			continue
+		}
+		section := foundryFlag(item.Flags, "section")
+		if section == "" {
+			section = "TRAITS"
+			for title, activation := range foundryActivations {
+				if activation != "" && activation == item.System.Activation.Type {
+					section = title
+				}
+			}
+		}
+		actions, ok := sections[section]
+		if !ok {
+			continue
+		}
+		*actions = append(*actions, Action{Name: item.actionName(), Description: htmlToMarkdown(item.System.Description.Value)})
+	}
+	if len(creature.LegendaryActions) > 0 {
+		creature.LegendaryActionCount = system.Resources.Legact.Max
+	}
+
+	return nil
+}
+
+// ToFoundry writes the creature as a Foundry VTT dnd5e NPC actor that can be
+// imported with "Import Data". Every action becomes a feature item.
+func (creature *Creature) ToFoundry() (string, error) {
+	actor := foundryActor{Name: creature.Name, Type: "npc", Items: []foundryItem{}}
+	system := &actor.System
+
+	for code, size := range foundrySizes {
+		if strings.EqualFold(size, creature.Size) {
+			system.Traits.Size = code
+		}
+	}
+	system.Details.Type.Value = strings.ToLower(creature.Type)
+	system.Details.Type.Subtype = creature.Species
+	system.Details.Alignment = creature.Alignment
+	system.Details.Biography.Value = markdownToHTML(creature.Description)
+
+	system.Attributes.AC.Flat = creature.ArmorClass
+	system.Attributes.AC.Calc = "flat"
+	if creature.ArmorType != "" {
+		actor.Flags = map[string]any{foundryFlagScope: map[string]string{"armorType": creature.ArmorType}}
+	}
+	average, formula, _ := strings.Cut(creature.HitPoints, " (")
+	system.Attributes.HP.Max, _ = strconv.Atoi(strings.TrimSpace(average))
+	system.Attributes.HP.Value = system.Attributes.HP.Max
+	system.Attributes.HP.Formula = strings.TrimSuffix(formula, ")")
+
+	movement := &system.Attributes.Movement
+	movement.Walk = creature.Speed.Base
+	movement.Burrow = creature.Speed.Burrow
+	movement.Climb = creature.Speed.Climb
+	movement.Fly = creature.Speed.Fly
+	movement.Swim = creature.Speed.Swim
+	movement.Hover = creature.Speed.Hover
+	movement.Units = "ft"
+
+	scores := map[string]int{
+		"str": creature.AbilityScores.Strength,
+		"dex": creature.AbilityScores.Dexterity,
+		"con": creature.AbilityScores.Constitution,
+		"int": creature.AbilityScores.Intelligence,
+		"wis": creature.AbilityScores.Wisdom,
+		"cha": creature.AbilityScores.Charisma,
+	}
+	system.Abilities = map[string]foundryAbility{}
+	for ability, score := range scores {
+		system.Abilities[ability] = foundryAbility{Value: score}
+	}
+	for _, save := range splitList(creature.SavingThrows) {
+		if match := fiveEToolsSaveRegex.FindStringSubmatch(save); len(match) > 2 {
+			ability := strings.ToLower(match[1])
+			if value, ok := system.Abilities[ability]; ok {
+				value.Proficient = 1
+				system.Abilities[ability] = value
+			}
+		}
+	}
+
+	rating := challengeRating(creature.ChallengeRating)
+	system.Details.CR, _ = ChallengeRatingValue(rating)
+	proficiency := ProficiencyForChallenge(rating)
+
+	system.Skills = map[string]foundrySkill{}
+	for _, skill := range splitList(creature.Skills) {
+		match := fiveEToolsSkillRegex.FindStringSubmatch(skill)
+		if len(match) < 3 {
+			continue
+		}
+		for code, name := range foundrySkills {
+			if !strings.EqualFold(name, match[1]) {
+				continue
+			}
+			ability := foundrySkillAbility(code)
+			bonus, _ := strconv.Atoi(match[2])
+			value := float64(bonus-modifier(scores[ability])) / float64(proficiency)
+			system.Skills[code] = foundrySkill{Value: math.Round(value*2) / 2, Ability: ability}
+		}
+	}
+
+	system.Traits.DV = foundryTraitFromText(creature.DamageVulnerabilities, foundryDamageTypes)
+	system.Traits.DR = foundryTraitFromText(creature.DamageResistances, foundryDamageTypes)
+	system.Traits.DI = foundryTraitFromText(creature.DamageImmunities, foundryDamageTypes)
+	system.Traits.CI = foundryTraitFromText(creature.ConditionImmunities, foundryConditions)
+	languageNames := make([]string, 0, len(foundryLanguages))
+	for _, name := range foundryLanguages {
+		languageNames = append(languageNames, name)
+	}
+	system.Traits.Languages = foundryTraitFromText(creature.Languages, languageNames)
+	for i, language := range system.Traits.Languages.Value {
+		for code, name := range foundryLanguages {
+			if strings.EqualFold(name, language) {
+				system.Traits.Languages.Value[i] = code
+			}
+		}
+	}
+
+	senses := &system.Attributes.Senses
+	senses.Units = "ft"
+	var special []string
+	for _, sense := range splitList(creature.Senses) {
+		if fiveEToolsPassiveRegex.MatchString(sense) {
+			continue
+		}
+		match := foundrySenseRegex.FindStringSubmatch(sense)
+		if len(match) < 3 {
+			special = append(special, sense)
+			continue
+		}
+		distance, _ := strconv.Atoi(match[2])
+		switch match[1] {
+		case "darkvision":
+			senses.Darkvision = distance
+		case "blindsight":
+			senses.Blindsight = distance
+		case "tremorsense":
+			senses.Tremorsense = distance
+		case "truesight":
+			senses.Truesight = distance
+		}
+	}
+	senses.Special = strings.Join(special, ", ")
+
+	for _, section := range []struct {
+		title   string
+		actions []Action
+	}{
+		{"TRAITS", creature.Traits},
+		{"ACTIONS", creature.Actions},
+		{"BONUS ACTIONS", creature.BonusActions},
+		{"REACTIONS", creature.Reactions},
+		{"LEGENDARY ACTIONS", creature.LegendaryActions},
+		{"MYTHIC ACTIONS", creature.MythicActions},
+		{"LAIR ACTIONS", creature.LairActions},
+		{"REGIONAL EFFECTS", creature.RegionalEffects},
+		{"OPTIONS", creature.Options},
+	} {
+		for _, action := range section.actions {
+			actor.Items = append(actor.Items, foundryItemFromAction(action, section.title))
+		}
+	}
+
+	if len(creature.LegendaryActions) > 0 {
+		system.Resources.Legact = foundryResource{Value: creature.LegendaryActionCount, Max: creature.LegendaryActionCount}
+	}
+	for _, resource := range ResourcesFromCreature(*creature) {
+		if resource.Kind == ResourceLegendaryResistance {
+			system.Resources.Legres = foundryResource{Value: resource.Max, Max: resource.Max}
+		}
+	}
+	system.Resources.Lair.Value = len(creature.LairActions) > 0
+	system.Resources.Lair.Initiative = LairInitiative
+
+	data, err := json.MarshalIndent(actor, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func foundryItemFromAction(action Action, section string) foundryItem {
+	item := foundryItem{Name: action.Name, Type: "feat"}
+	item.Flags = map[string]any{foundryFlagScope: map[string]string{"section": section}}
+	item.System.Description.Value = markdownToHTML(action.Description)
+	item.System.Activation.Type = foundryActivations[section]
+	if item.System.Activation.Type != "" {
+		item.System.Activation.Cost = 1
+	}
+
+	if match := rechargeRegex.FindStringSubmatch(action.Name); len(match) > 1 {
+		item.Name = strings.TrimSpace(strings.Replace(action.Name, match[0], "", 1))
+		item.System.Recharge.Value, _ = strconv.Atoi(match[1])
+		item.System.Recharge.Charged = true
+	} else if match := perDayRegex.FindStringSubmatch(action.Name); len(match) > 1 && !strings.Contains(match[0], "each") {
+		item.Name = strings.TrimSpace(strings.Replace(action.Name, match[0], "", 1))
+		item.System.Uses.Value, _ = strconv.Atoi(match[1])
+		item.System.Uses.Max = match[1]
+		item.System.Uses.Per = "day"
+	}
+
+	if match := foundryAttackRegex.FindStringSubmatch(action.Description); len(match) > 2 {
+		item.System.ActionType = strings.ToLower(match[1][:1]) + map[string]string{"Weapon": "wak", "Spell": "sak"}[match[2]]
+	}
+	for _, match := range foundryDamageRegex.FindAllStringSubmatch(action.Description, -1) {
+		item.System.Damage.Parts = append(item.System.Damage.Parts, [2]string{match[1], match[2]})
+	}
+	return item
+}
+
+// actionName restores the recharge or per-day suffix Foundry keeps in the
+// item's recharge and uses fields.
+func (item foundryItem) actionName() string {
+	name := item.Name
+	if item.System.Recharge.Value == 6 {
+		name += " (Recharge 6)"
+	} else if item.System.Recharge.Value > 0 {
+		name += fmt.Sprintf(" (Recharge %d–6)", item.System.Recharge.Value)
+	} else if item.System.Uses.Per == "day" && item.System.Uses.Max != "" {
+		name += fmt.Sprintf(" (%s/Day)", item.System.Uses.Max)
+	}
+	return name
+}
+
+func foundryFlag(flags map[string]any, key string) string {
+	scope, ok := flags[foundryFlagScope].(map[string]any)
+	if !ok {
+		return ""
+	}
+	value, _ := scope[key].(string)
+	return value
+}
+
+// foundryTraitText lists the trait's values, then its custom text after a
+// semicolon. Values are looked up in names when given.
+func foundryTraitText(trait foundryTrait, names map[string]string) string {
+	values := make([]string, len(trait.Value))
+	for i, value := range trait.Value {
+		values[i] = value
+		if name, ok := names[value]; ok {
+			values[i] = name
+		}
+	}
+	text := strings.Join(values, ", ")
+	if trait.Custom != "" {
+		if text != "" {
+			text += "; "
+		}
+		text += trait.Custom
+	}
+	return text
+}
+
+// foundryTraitFromText is the reverse of foundryTraitText. The first group is
+// split into values when every entry is in known; anything else is custom.
+func foundryTraitFromText(text string, known []string) foundryTrait {
+	trait := foundryTrait{Value: []string{}}
+	if text == "" || text == "—" {
+		return trait
+	}
+	groups := strings.Split(text, "; ")
+	parts := splitList(groups[0])
+	allKnown := true
+	for _, part := range parts {
+		found := false
+		for _, k := range known {
+			if strings.EqualFold(k, part) {
+				found = true
+			}
+		}
+		allKnown = allKnown && found
+	}
+	if allKnown {
+		trait.Value = parts
+		groups = groups[1:]
+	}
+	trait.Custom = strings.Join(groups, "; ")
+	return trait
+}
+
+// markdownToHTML converts stat block text to the HTML Foundry stores in
+// descriptions: paragraphs, "- " lists and bold and italic emphasis.
+func markdownToHTML(text string) string {
+	if text == "" {
+		return ""
+	}
+	var sb strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		lines := strings.Split(paragraph, "\n")
+		isList := true
+		for _, line := range lines {
+			if !strings.HasPrefix(line, "- ") {
+				isList = false
+			}
+		}
+		if isList {
+			sb.WriteString("<ul>")
+			for _, line := range lines {
+				sb.WriteString("<li>" + markdownInlineToHTML(strings.TrimPrefix(line, "- ")) + "</li>")
+			}
+			sb.WriteString("</ul>")
+			continue
+		}
+		sb.WriteString("<p>" + strings.ReplaceAll(markdownInlineToHTML(paragraph), "\n", "<br>") + "</p>")
+	}
+	return sb.String()
+}
+
+func markdownInlineToHTML(text string) string {
+	text = html.EscapeString(text)
+	text = foundryBoldItalic.ReplaceAllString(text, "<strong><em>$1</em></strong>")
+	text = foundryBold.ReplaceAllString(text, "<strong>$1</strong>")
+	return foundryItalic.ReplaceAllString(text, "<em>$1</em>")
+}
+
+func htmlToMarkdown(text string) string {
+	replacer := strings.NewReplacer(
+		"<strong><em>", "***", "</em></strong>", "***",
+		"<strong>", "**", "</strong>", "**", "<b>", "**", "</b>", "**",
+		"<em>", "*", "</em>", "*", "<i>", "*", "</i>", "*",
+		"</p>", "\n\n", "<br>", "\n", "<br/>", "\n", "<br />", "\n",
+		"<li>", "- ", "</li>", "\n", "</ul>", "\n",
+	)
+	text = replacer.Replace(text)
+	text = foundryHTMLTagRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatureFromFoundry(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "foundry", "goblin.json"))
+	assert.NoError(t, err)
+
+	var expected Creature
+	expected.Name = "Goblin"
+	expected.Size = "Small"
+	expected.Type = "humanoid"
+	expected.Species = "goblinoid"
+	expected.Alignment = "neutral evil"
+	expected.ArmorClass = 15
+	expected.HitPoints = "7 (2d6)"
+	expected.Speed.Base = 30
+	expected.AbilityScores.Strength = 8
+	expected.AbilityScores.Dexterity = 14
+	expected.AbilityScores.Constitution = 10
+	expected.AbilityScores.Intelligence = 10
+	expected.AbilityScores.Wisdom = 8
+	expected.AbilityScores.Charisma = 8
+	expected.Skills = "Stealth +6"
+	expected.Senses = "darkvision 60 ft., passive Perception 9"
+	expected.Languages = "Common, Goblin"
+	expected.ChallengeRating = "1/4 (50 XP)"
+	expected.Description = "Goblins are small, black-hearted humanoids that lair in despoiled dungeons."
+	expected.Traits = []Action{{Name: "Nimble Escape", Description: "The goblin can take the Disengage or Hide action as a bonus action on each of its turns."}}
+	expected.Actions = []Action{
+		{Name: "Scimitar", Description: "*Melee Weapon Attack:* +4 to hit, reach 5 ft., one target. *Hit:* 5 (1d6 + 2) slashing damage."},
+		{Name: "Shortbow", Description: "*Ranged Weapon Attack:* +4 to hit, range 80/320 ft., one target. *Hit:* 5 (1d6 + 2) piercing damage."},
+	}
+
+	var creature Creature
+	assert.NoError(t, creature.FromFoundry(string(content)))
+	assert.Equal(t, expected, creature)
+}
+
+func TestCreatureFromFoundryLegendary(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "foundry", "adult-red-dragon.json"))
+	assert.NoError(t, err)
+
+	var creature Creature
+	assert.NoError(t, creature.FromFoundry(string(content)))
+
+	assert.Equal(t, "natural armor", creature.ArmorType)
+	assert.Equal(t, "Dex +6, Con +13, Wis +7, Cha +11", creature.SavingThrows)
+	assert.Equal(t, "Perception +13, Stealth +6", creature.Skills)
+	assert.Equal(t, "blindsight 60 ft., darkvision 120 ft., passive Perception 23", creature.Senses)
+	assert.Equal(t, "fire", creature.DamageImmunities)
+	assert.Equal(t, []string{"Legendary Resistance (3/Day)"}, actionNames(creature.Traits))
+	assert.Equal(t, []string{"Fire Breath (Recharge 5–6)"}, actionNames(creature.Actions))
+	assert.Equal(t, []string{"Wing Attack (Costs 2 Actions)"}, actionNames(creature.LegendaryActions))
+	assert.Equal(t, 3, creature.LegendaryActionCount)
+	assert.Equal(t, []string{"Magma Eruption"}, actionNames(creature.LairActions))
+	assert.Equal(t, "Small earthquakes are common within 6 miles of the dragon's lair.", creature.RegionalEffects[0].Description)
+}
+
+func TestCreatureFoundryRoundTrip(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "foundry", "*.json"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			assert.NoError(t, err)
+
+			var creature Creature
+			assert.NoError(t, creature.FromFoundry(string(content)))
+			exported, err := creature.ToFoundry()
+			assert.NoError(t, err)
+
+			var reparsed Creature
+			assert.NoError(t, reparsed.FromFoundry(exported))
+			assert.Equal(t, creature, reparsed)
+		})
+	}
+}
+
+func TestCreatureToFoundryItems(t *testing.T) {
+	creature := Creature{
+		Name:            "Adult Red Dragon",
+		ChallengeRating: "17 (18,000 XP)",
+		Actions: []Action{
+			{Name: "Bite", Description: "*Melee Weapon Attack:* +14 to hit, reach 10 ft., one target. *Hit:* 19 (2d10 + 8) piercing damage plus 7 (2d6) fire damage."},
+			{Name: "Fire Breath (Recharge 5–6)", Description: "Each creature must make a DC 21 Dexterity saving throw."},
+		},
+	}
+
+	exported, err := creature.ToFoundry()
+	assert.NoError(t, err)
+
+	var actor foundryActor
+	assert.NoError(t, json.Unmarshal([]byte(exported), &actor))
+	assert.Equal(t, "npc", actor.Type)
+	assert.Equal(t, float64(17), actor.System.Details.CR)
+	assert.Len(t, actor.Items, 2)
+
+	bite := actor.Items[0]
+	assert.Equal(t, "action", bite.System.Activation.Type)
+	assert.Equal(t, "mwak", bite.System.ActionType)
+	assert.Equal(t, [][2]string{{"2d10 + 8", "piercing"}, {"2d6", "fire"}}, bite.System.Damage.Parts)
+	assert.Equal(t, "<p><em>Melee Weapon Attack:</em> +14 to hit, reach 10 ft., one target. <em>Hit:</em> 19 (2d10 + 8) piercing damage plus 7 (2d6) fire damage.</p>", bite.System.Description.Value)
+
+	breath := actor.Items[1]
+	assert.Equal(t, "Fire Breath", breath.Name)
+	assert.Equal(t, 5, breath.System.Recharge.Value)
+}
+
+func actionNames(actions []Action) []string {
+	names := []string{}
+	for _, a := range actions {
+		names = append(names, a.Name)
+	}
+	return names
+}
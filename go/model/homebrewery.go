@@ -0,0 +1,188 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	homebreweryPropertyRegex       = regexp.MustCompile(`^\*\*(.+?)\*\*\s*::\s*(.*)$`)
+	homebreweryLegacyPropertyRegex = regexp.MustCompile(`^-?\s*\*\*(.+?)\*\*\s+(.*)$`)
+	homebreweryTypeLineRegex       = regexp.MustCompile(`^\*([^*].*?)\*$`)
+)
+
+// homebreweryEarlyProperties are written above the ability score table; the
+// rest follow it, matching the Homebrewery monster snippet.
+var homebreweryEarlyProperties = map[string]bool{
+	"Armor Class": true,
+	"Hit Points":  true,
+	"Speed":       true,
+}
+
+// splitHomebreweryProperties splits rows at the last early property, so that
+// extra rows stay next to the property they followed.
+func splitHomebreweryProperties(rows []statBlockField) ([]statBlockField, []statBlockField) {
+	split := 0
+	for i, row := range rows {
+		if homebreweryEarlyProperties[row.name] {
+			split = i + 1
+		}
+	}
+	return rows[:split], rows[split:]
+}
+
+// FromHomebrewery reads a Homebrewery V3 ({{monster ... }}) or legacy GM
+// Binder ("> ## Name") stat block.
+func (creature *Creature) FromHomebrewery(content string) error {
+	creature.AbilityScores.Strength = 10
+	creature.AbilityScores.Dexterity = 10
+	creature.AbilityScores.Constitution = 10
+	creature.AbilityScores.Intelligence = 10
+	creature.AbilityScores.Wisdom = 10
+	creature.AbilityScores.Charisma = 10
+
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") {
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+		}
+		if strings.HasPrefix(trimmed, "{{") || trimmed == "}}" {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+
+	for _, table := range ParseTables(strings.Join(lines, "\n")) {
+		if table.HasColumns("STR", "DEX") && len(table.Rows) > 0 {
+			creature.setAbilityScores(table)
+		}
+	}
+
+	currentSection := ""
+	previousSection := ""
+	previousProperty := ""
+	var sectionContent []string
+	seenTypeLine := false
+	pastProperties := false
+
+	processSection := func() {
+		text := strings.TrimSpace(strings.Join(sectionContent, "\n"))
+		sectionContent = nil
+		if text == "" {
+			return
+		}
+		title := currentSection
+		if title == "" {
+			title = "TRAITS"
+		}
+		creature.setSection(title, text, previousSection)
+		previousSection = title
+	}
+
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "## "):
+			creature.Name = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			continue
+		case strings.HasPrefix(line, "### "):
+			processSection()
+			currentSection = strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(line, "### ")))
+			pastProperties = true
+			continue
+		case strings.HasPrefix(line, "|"):
+			continue
+		case line == "___" || line == "---":
+			continue
+		case line == ":":
+			sectionContent = append(sectionContent, "")
+			continue
+		}
+
+		if !seenTypeLine {
+			if match := homebreweryTypeLineRegex.FindStringSubmatch(line); len(match) > 1 && creature.setTypeLine(match[1]) {
+				seenTypeLine = true
+				continue
+			}
+		}
+
+		if !pastProperties && !strings.HasPrefix(line, "***") {
+			match := homebreweryPropertyRegex.FindStringSubmatch(line)
+			if len(match) < 3 {
+				match = homebreweryLegacyPropertyRegex.FindStringSubmatch(line)
+			}
+			if len(match) > 2 {
+				property := strings.TrimSpace(match[1])
+				value := strings.TrimSpace(match[2])
+				if !creature.setProperty(property, value) {
+					creature.addExtraProperty(property, value, previousProperty)
+				}
+				previousProperty = property
+				continue
+			}
+		}
+
+		if line == "" && len(sectionContent) == 0 {
+			continue
+		}
+		if strings.HasPrefix(line, "***") {
+			pastProperties = true
+			if len(sectionContent) > 0 && sectionContent[len(sectionContent)-1] != "" {
+				sectionContent = append(sectionContent, "")
+			}
+		}
+		sectionContent = append(sectionContent, line)
+	}
+	processSection()
+
+	return nil
+}
+
+// ToHomebrewery writes the creature as a Homebrewery V3 monster block.
+func (creature *Creature) ToHomebrewery() (string, error) {
+	md := "{{monster,frame\n"
+	md += fmt.Sprintf("## %s\n", creature.Name)
+	if typeLine := creature.typeLine(); typeLine != "" {
+		md += fmt.Sprintf("*%s*\n", typeLine)
+	}
+	md += "___\n"
+
+	early, late := splitHomebreweryProperties(placeExtras(creature.propertyRows(), creature.ExtraProperties))
+	for _, row := range early {
+		md += fmt.Sprintf("**%s** :: %s\n", row.name, row.value)
+	}
+	md += "___\n"
+
+	abilities := creature.abilityTable()
+	md += abilities.String() + "\n"
+	md += "___\n"
+
+	for _, row := range late {
+		md += fmt.Sprintf("**%s** :: %s\n", row.name, row.value)
+	}
+	md += "___\n"
+
+	for i, section := range placeExtras(creature.sections(), creature.ExtraSections) {
+		body := strings.ReplaceAll(section.value, "\n\n***", "\n:\n***")
+		if section.name == "TRAITS" {
+			md += body + "\n"
+			continue
+		}
+		if i > 0 {
+			md += ":\n"
+		}
+		md += fmt.Sprintf("### %s\n%s\n", homebreweryTitle(section.name), body)
+	}
+	md += "}}"
+
+	return md, nil
+}
+
+func homebreweryTitle(section string) string {
+	words := strings.Fields(strings.ToLower(section))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatureFromHomebrewery(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected func(*Creature)
+	}{
+		{
+			name: "V3 monster block",
+			content: `{{monster,frame
+## Goblin
+*Small humanoid (goblinoid), neutral evil*
+___
+**Armor Class** :: 15 (leather armor, shield)
+**Hit Points** :: 7 (2d6)
+**Speed** :: 30 ft.
+___
+|  STR  |  DEX  |  CON  |  INT  |  WIS  |  CHA  |
+|:-----:|:-----:|:-----:|:-----:|:-----:|:-----:|
+|8 (-1)|14 (+2)|10 (+0)|10 (+0)|8 (-1)|8 (-1)|
+___
+**Skills** :: Stealth +6
+**Senses** :: darkvision 60 ft., passive Perception 9
+**Languages** :: Common, Goblin
+**Challenge** :: 1/4 (50 XP)
+___
+***Nimble Escape.*** The goblin can take the Disengage or Hide action as a bonus action on each of its turns.
+:
+### Actions
+***Scimitar.*** *Melee Weapon Attack:* +4 to hit, reach 5 ft., one target. *Hit:* 5 (1d6 + 2) slashing damage.
+:
+***Shortbow.*** *Ranged Weapon Attack:* +4 to hit, range 80/320 ft., one target. *Hit:* 5 (1d6 + 2) piercing damage.
+}}`,
+			expected: func(c *Creature) {
+				c.Name = "Goblin"
+				c.Size = "Small"
+				c.Type = "humanoid"
+				c.Species = "goblinoid"
+				c.Alignment = "neutral evil"
+				c.ArmorClass = 15
+				c.ArmorType = "leather armor, shield"
+				c.HitPoints = "7 (2d6)"
+				c.Speed.Base = 30
+				c.AbilityScores.Strength = 8
+				c.AbilityScores.Dexterity = 14
+				c.AbilityScores.Constitution = 10
+				c.AbilityScores.Intelligence = 10
+				c.AbilityScores.Wisdom = 8
+				c.AbilityScores.Charisma = 8
+				c.Skills = "Stealth +6"
+				c.Senses = "darkvision 60 ft., passive Perception 9"
+				c.Languages = "Common, Goblin"
+				c.ChallengeRating = "1/4 (50 XP)"
+				c.Traits = []Action{{Name: "Nimble Escape", Description: "The goblin can take the Disengage or Hide action as a bonus action on each of its turns."}}
+				c.Actions = []Action{
+					{Name: "Scimitar", Description: "*Melee Weapon Attack:* +4 to hit, reach 5 ft., one target. *Hit:* 5 (1d6 + 2) slashing damage."},
+					{Name: "Shortbow", Description: "*Ranged Weapon Attack:* +4 to hit, range 80/320 ft., one target. *Hit:* 5 (1d6 + 2) piercing damage."},
+				}
+			},
+		},
+		{
+			name: "Legacy GM Binder block",
+			content: `___
+> ## Bandit
+>*Medium humanoid (any race), any non-lawful alignment*
+> ___
+> - **Armor Class** 12 (leather armor)
+> - **Hit Points** 11 (2d8 + 2)
+> - **Speed** 30 ft.
+>___
+>|STR|DEX|CON|INT|WIS|CHA|
+>|:---:|:---:|:---:|:---:|:---:|:---:|
+>|11 (+0)|12 (+1)|12 (+1)|10 (+0)|10 (+0)|10 (+0)|
+>___
+> - **Senses** passive Perception 10
+> - **Languages** any one language (usually Common)
+> - **Challenge** 1/8 (25 XP)
+> ___
+> ### Actions
+> ***Scimitar.*** *Melee Weapon Attack:* +3 to hit, reach 5 ft., one target. *Hit:* 4 (1d6 + 1) slashing damage.`,
+			expected: func(c *Creature) {
+				c.Name = "Bandit"
+				c.Size = "Medium"
+				c.Type = "humanoid"
+				c.Species = "any race"
+				c.Alignment = "any non-lawful alignment"
+				c.ArmorClass = 12
+				c.ArmorType = "leather armor"
+				c.HitPoints = "11 (2d8 + 2)"
+				c.Speed.Base = 30
+				c.AbilityScores.Strength = 11
+				c.AbilityScores.Dexterity = 12
+				c.AbilityScores.Constitution = 12
+				c.AbilityScores.Intelligence = 10
+				c.AbilityScores.Wisdom = 10
+				c.AbilityScores.Charisma = 10
+				c.Senses = "passive Perception 10"
+				c.Languages = "any one language (usually Common)"
+				c.ChallengeRating = "1/8 (25 XP)"
+				c.Actions = []Action{
+					{Name: "Scimitar", Description: "*Melee Weapon Attack:* +3 to hit, reach 5 ft., one target. *Hit:* 4 (1d6 + 1) slashing damage."},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected Creature
+			tt.expected(&expected)
+
+			var creature Creature
+			assert.NoError(t, creature.FromHomebrewery(tt.content))
+			assert.Equal(t, expected, creature)
+		})
+	}
+}
+
+func TestCreatureHomebreweryRoundTrip(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "statblocks", "*.md"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			content, err := os.ReadFile(input)
+			assert.NoError(t, err)
+
+			var creature Creature
+			assert.NoError(t, creature.FromMarkdown(string(content)))
+			homebrewery, err := creature.ToHomebrewery()
+			assert.NoError(t, err)
+			assert.Contains(t, homebrewery, "{{monster,frame\n## "+creature.Name+"\n")
+
+			var reparsed Creature
+			assert.NoError(t, reparsed.FromHomebrewery(homebrewery))
+			assert.Equal(t, creature, reparsed)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// improvedInitiativeEncounter is the saved encounter state of the Improved
+// Initiative app.
+type improvedInitiativeEncounter struct {
+	Combatants        []improvedInitiativeCombatant `json:"Combatants"`
+	ActiveCombatantId string                        `json:"ActiveCombatantId"`
+	RoundCounter      int                           `json:"RoundCounter"`
+}
+
+type improvedInitiativeCombatant struct {
+	Id         string `json:"Id"`
+	Alias      string `json:"Alias"`
+	IndexLabel int    `json:"IndexLabel"`
+	MaxHP      int    `json:"MaxHP"`
+	// CurrentHP is missing from some exports; those combatants are undamaged.
+	CurrentHP  *int                        `json:"CurrentHP"`
+	Initiative int                         `json:"Initiative"`
+	StatBlock  improvedInitiativeStatBlock `json:"StatBlock"`
+}
+
+type improvedInitiativeStatBlock struct {
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	AC          struct {
+		Value int `json:"Value"`
+	} `json:"AC"`
+	HP struct {
+		Value int `json:"Value"`
+	} `json:"HP"`
+	InitiativeModifier int `json:"InitiativeModifier"`
+	LegendaryActions   []struct {
+		Name    string `json:"Name"`
+		Content string `json:"Content"`
+	} `json:"LegendaryActions"`
+}
+
+// creature holds the legendary actions of the stat block, taking their count
+// from the description or the actions themselves when one of them gives it.
+func (block improvedInitiativeStatBlock) creature() Creature {
+	creature := Creature{Name: block.Name}
+	texts := []string{block.Description}
+	for _, action := range block.LegendaryActions {
+		creature.LegendaryActions = append(creature.LegendaryActions, Action{Name: action.Name, Description: action.Content})
+		texts = append(texts, action.Content)
+	}
+	for _, text := range texts {
+		if match := legendaryCountRegex.FindStringSubmatch(text); len(match) > 1 {
+			creature.LegendaryActionCount, _ = strconv.Atoi(match[1])
+			break
+		}
+	}
+	return creature
+}
+
+// FromImprovedInitiative reads an encounter exported from Improved
+// Initiative. Damage is the difference between maximum and current hit
+// points, and the active combatant becomes the current turn.
+func (it *InitiativeTracker) FromImprovedInitiative(content string) error {
+	var encounter improvedInitiativeEncounter
+	if err := json.Unmarshal([]byte(content), &encounter); err != nil {
+		return err
+	}
+	if encounter.Combatants == nil {
+		return errors.New("Improved Initiative encounter has no combatants")
+	}
+
+	it.Combatants = []Combatant{}
+	it.Round = encounter.RoundCounter
+	if it.Round < 1 {
+		it.Round = 1
+	}
+	it.Turn = 0
+
+	active := ""
+	for _, c := range encounter.Combatants {
+		name := c.Alias
+		if name == "" {
+			name = c.StatBlock.Name
+			if c.IndexLabel > 0 {
+				name += " " + strconv.Itoa(c.IndexLabel)
+			}
+		}
+		maxHP := c.MaxHP
+		if maxHP == 0 {
+			maxHP = c.StatBlock.HP.Value
+		}
+		combatant := Combatant{
+			Name:            name,
+			Initiative:      c.Initiative,
+			ArmorClass:      c.StatBlock.AC.Value,
+			HitPoints:       maxHP,
+			InitiativeBonus: c.StatBlock.InitiativeModifier,
+		}
+		if c.CurrentHP != nil {
+			combatant.Damage = max(maxHP-*c.CurrentHP, 0)
+		}
+		combatant.setLegendaryActions(c.StatBlock.creature())
+		if c.Id == encounter.ActiveCombatantId {
+			active = name
+		}
+		it.Combatants = append(it.Combatants, combatant)
+	}
+
+	it.Sort()
+	for i, c := range it.Combatants {
+		if c.Name == active {
+			it.Turn = i
+		}
+	}
+	return nil
+}
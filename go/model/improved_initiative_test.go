@@ -0,0 +1,66 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitiativeTrackerFromImprovedInitiative(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "improved-initiative", "encounter.json"))
+	assert.NoError(t, err)
+
+	var it InitiativeTracker
+	assert.NoError(t, it.FromImprovedInitiative(string(content)))
+
+	assert.Equal(t, InitiativeTracker{
+		Round: 2,
+		Turn:  0,
+		Combatants: []Combatant{
+			{Name: "Valeria", Initiative: 17, Damage: 4, ArmorClass: 17, HitPoints: 24, InitiativeBonus: 3},
+			{Name: "Goblin 1", Initiative: 14, Damage: 4, ArmorClass: 15, HitPoints: 7, InitiativeBonus: 2},
+			{Name: "Young Green Dragon", Initiative: 11, Damage: 0, ArmorClass: 18, HitPoints: 136, InitiativeBonus: 1, LegendaryActions: 3, LegendaryActionsMax: 3},
+			{Name: "Goblin Boss", Initiative: 8, Damage: 0, ArmorClass: 15, HitPoints: 9, InitiativeBonus: 2},
+		},
+	}, it)
+
+	markdown, err := it.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, markdown, "| Goblin 1 | 14 | 4 |")
+
+	assert.Error(t, it.FromImprovedInitiative(`{"Name": "Empty"}`))
+	assert.Error(t, it.FromImprovedInitiative("not json"))
+}
+
+func TestImprovedInitiativeActiveCombatant(t *testing.T) {
+	content := `{"ActiveCombatantId": "b", "RoundCounter": 0, "Combatants": [
+		{"Id": "a", "StatBlock": {"Name": "Orc"}, "MaxHP": 15, "CurrentHP": 15, "Initiative": 20},
+		{"Id": "b", "StatBlock": {"Name": "Ogre"}, "MaxHP": 59, "CurrentHP": 40, "Initiative": 5}
+	]}`
+
+	var it InitiativeTracker
+	assert.NoError(t, it.FromImprovedInitiative(content))
+	assert.Equal(t, 1, it.Round)
+	assert.Equal(t, 1, it.Turn)
+	assert.Equal(t, "Ogre", it.Combatants[it.Turn].Name)
+	assert.Equal(t, 19, it.Combatants[1].Damage)
+}
+
+func TestImprovedInitiativeStatBlockDetails(t *testing.T) {
+	content := `{"Combatants": [
+		{"Id": "a", "StatBlock": {"Name": "Lich", "HP": {"Value": 135}, "LegendaryActions": [
+			{"Name": "Legendary Actions", "Content": "The lich can take 4 legendary actions, choosing from the options below."},
+			{"Name": "Cantrip", "Content": "The lich casts a cantrip."}
+		]}, "Initiative": 15},
+		{"Id": "b", "StatBlock": {"Name": "Vampire", "Description": "It can take 2 legendary actions.", "LegendaryActions": [{"Name": "Move", "Content": "The vampire moves."}]}, "MaxHP": 144, "CurrentHP": 0, "Initiative": 10}
+	]}`
+
+	var it InitiativeTracker
+	assert.NoError(t, it.FromImprovedInitiative(content))
+	assert.Equal(t, 0, it.Combatants[0].Damage, "a missing CurrentHP means undamaged")
+	assert.Equal(t, 4, it.Combatants[0].LegendaryActionsMax)
+	assert.Equal(t, 144, it.Combatants[1].Damage)
+	assert.Equal(t, 2, it.Combatants[1].LegendaryActions)
+}
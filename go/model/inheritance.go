@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreatureLookup returns the creature a base reference points to, e.g. by
+// loading the stat block on the referenced page.
+type CreatureLookup func(reference string) (Creature, error)
+
+// ResolveCreature fills in what a creature inherits from its base, and from
+// the base's base in turn. The creature's own values win: a non-empty field
+// replaces the inherited one, and an action replaces the inherited action of
+// the same name, or removes it when it has no description. A chain of bases
+// that leads back to itself is an error.
+func ResolveCreature(creature Creature, lookup CreatureLookup) (Creature, error) {
+	return resolveCreature(creature, lookup, []string{normalizeBaseReference(creature.Name)})
+}
+
+// FlattenCreature resolves the creature and drops its base, giving a stat
+// block that stands on its own.
+func FlattenCreature(creature Creature, lookup CreatureLookup) (Creature, error) {
+	resolved, err := ResolveCreature(creature, lookup)
+	if err != nil {
+		return Creature{}, err
+	}
+	resolved.Base = ""
+	return resolved, nil
+}
+
+func resolveCreature(creature Creature, lookup CreatureLookup, chain []string) (Creature, error) {
+	if creature.Base == "" {
+		return creature, nil
+	}
+
+	reference := normalizeBaseReference(creature.Base)
+	for _, seen := range chain {
+		if strings.EqualFold(seen, reference) {
+			return Creature{}, fmt.Errorf("base creature cycle: %s -> %s", strings.Join(chain, " -> "), reference)
+		}
+	}
+
+	base, err := lookup(reference)
+	if err != nil {
+		return Creature{}, fmt.Errorf("base creature %s: %w", reference, err)
+	}
+	base, err = resolveCreature(base, lookup, append(chain, reference))
+	if err != nil {
+		return Creature{}, err
+	}
+	return inheritCreature(base, creature), nil
+}
+
+// normalizeBaseReference writes a bare creature name as a page reference, so
+// that "Bandit" and "[[Bandit]]" name the same base.
+func normalizeBaseReference(reference string) string {
+	reference = strings.TrimSpace(reference)
+	if pageReferenceRegex.MatchString(reference) || blockReferenceRegex.MatchString(reference) {
+		return reference
+	}
+	return "[[" + reference + "]]"
+}
+
+// inheritCreature lays the values the creature sets over its base. The type
+// line, armor class and speed are each taken as a whole.
+func inheritCreature(base Creature, creature Creature) Creature {
+	result := copyCreature(base)
+	result.Base = creature.Base
+
+	if creature.Type != "" {
+		result.Size, result.Type, result.Species, result.Alignment = creature.Size, creature.Type, creature.Species, creature.Alignment
+	}
+	if creature.ArmorClass != 0 {
+		result.ArmorClass, result.ArmorType = creature.ArmorClass, creature.ArmorType
+	}
+	if creature.Speed != (Creature{}).Speed {
+		result.Speed = creature.Speed
+	}
+
+	resultFields := creatureFields(&result)
+	for i, field := range creatureFields(&creature) {
+		switch {
+		case field.name == "size", field.name == "type", field.name == "species", field.name == "alignment",
+			field.name == "armorClass", field.name == "armorType", strings.HasPrefix(field.name, "speed."):
+			continue
+		case !field.value.IsZero():
+			resultFields[i].value.Set(field.value)
+		}
+	}
+
+	resultLists := result.actionLists()
+	for i, list := range creature.actionLists() {
+		actions := resultLists[i].actions
+		for _, action := range *list.actions {
+			index := -1
+			for j, inherited := range *actions {
+				if inherited.Name == action.Name {
+					index = j
+				}
+			}
+			switch {
+			case index < 0 && action.Description != "":
+				*actions = append(*actions, action)
+			case index >= 0 && action.Description == "":
+				*actions = append((*actions)[:index], (*actions)[index+1:]...)
+			case index >= 0:
+				(*actions)[index] = action
+			}
+		}
+	}
+
+	for _, name := range []string{"extraProperties", "extraSections"} {
+		extras := result.extras(name)
+		for key, value := range creature.extras(name) {
+			if extras == nil {
+				extras = map[string]Extension{}
+			}
+			extras[key] = value
+		}
+		result.setExtras(name, extras)
+	}
+
+	return result
+}
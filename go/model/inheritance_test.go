@@ -0,0 +1,140 @@
+package model
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const banditArcher = `### Bandit Archer
+---
+| Property | Value |
+| :--- | :--- |
+| **Base Creature** | [[Bandit Captain]] |
+| **Armor Class** | 14 (leather armor) |
+---
+| STR | DEX | CON | INT | WIS | CHA |
+| :-: | :-: | :-: | :-: | :-: | :-: |
+| — | 18 (+4) | — | — | — | — |
+---
+
+**ACTIONS**
+---
+***Multiattack.*** The archer makes two longbow attacks.
+
+***Scimitar.***
+
+***Longbow.*** *Ranged Weapon Attack:* +6 to hit, range 150/600 ft., one target. *Hit:* 8 (1d8 + 4) piercing damage.
+
+**REACTIONS**
+---
+***Parry.***`
+
+func creatureLookup(t *testing.T, creatures map[string]string) CreatureLookup {
+	return func(reference string) (Creature, error) {
+		var creature Creature
+		if reference == "[[Bandit Captain]]" {
+			content, err := os.ReadFile(filepath.Join("testdata", "statblocks", "bandit-captain.md"))
+			assert.NoError(t, err)
+			return creature, creature.FromMarkdown(string(content))
+		}
+		content, ok := creatures[reference]
+		if !ok {
+			return creature, errors.New("no stat block found")
+		}
+		return creature, creature.FromMarkdown(content)
+	}
+}
+
+func TestCreatureFromMarkdownWithBase(t *testing.T) {
+	var creature Creature
+	assert.NoError(t, creature.FromMarkdown(banditArcher))
+
+	assert.Equal(t, "[[Bandit Captain]]", creature.Base)
+	assert.Equal(t, 0, creature.AbilityScores.Strength)
+	assert.Equal(t, 18, creature.AbilityScores.Dexterity)
+	assert.Equal(t, "", creature.Type)
+	assert.Equal(t, []Action{{Name: "Parry", Description: ""}}, creature.Reactions)
+
+	markdown, err := creature.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, banditArcher, markdown)
+}
+
+func TestResolveCreature(t *testing.T) {
+	var archer Creature
+	assert.NoError(t, archer.FromMarkdown(banditArcher))
+	lookup := creatureLookup(t, nil)
+	captain, err := lookup("[[Bandit Captain]]")
+	assert.NoError(t, err)
+
+	resolved, err := ResolveCreature(archer, lookup)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Bandit Archer", resolved.Name)
+	assert.Equal(t, "[[Bandit Captain]]", resolved.Base)
+	assert.Equal(t, captain.typeLine(), resolved.typeLine())
+	assert.Equal(t, 14, resolved.ArmorClass)
+	assert.Equal(t, "leather armor", resolved.ArmorType)
+	assert.Equal(t, captain.HitPoints, resolved.HitPoints)
+	assert.Equal(t, captain.Speed, resolved.Speed)
+	assert.Equal(t, captain.AbilityScores.Strength, resolved.AbilityScores.Strength)
+	assert.Equal(t, 18, resolved.AbilityScores.Dexterity)
+	assert.Equal(t, []string{"Multiattack", "Longbow"}, actionNames(resolved.Actions))
+	assert.Equal(t, "The archer makes two longbow attacks.", resolved.Actions[0].Description)
+	assert.Empty(t, resolved.Reactions)
+	assert.Equal(t, captain.ExtraSections, resolved.ExtraSections)
+
+	inheritCreature(captain, archer)
+	assert.Equal(t, []string{"Multiattack", "Scimitar"}, actionNames(captain.Actions), "inheriting should not change the base")
+	assert.Equal(t, []string{"Parry"}, actionNames(captain.Reactions))
+
+	flattened, err := FlattenCreature(archer, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, "", flattened.Base)
+	markdown, err := flattened.ToMarkdown()
+	assert.NoError(t, err)
+	assert.NotContains(t, markdown, "Base Creature")
+	assert.NotContains(t, markdown, "—")
+}
+
+func TestResolveCreatureChain(t *testing.T) {
+	lookup := creatureLookup(t, map[string]string{
+		"[[Bandit Archer]]": banditArcher,
+	})
+	creature := Creature{Name: "Bandit Sharpshooter", Base: "Bandit Archer", Skills: "Perception +4"}
+
+	resolved, err := ResolveCreature(creature, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bandit Sharpshooter", resolved.Name)
+	assert.Equal(t, "Perception +4", resolved.Skills)
+	assert.Equal(t, 14, resolved.ArmorClass)
+	assert.Equal(t, "65 (10d8 + 20)", resolved.HitPoints)
+}
+
+func TestResolveCreatureErrors(t *testing.T) {
+	lookup := creatureLookup(t, map[string]string{
+		"[[Cultist]]":    "### Cultist\n---\n| Property | Value |\n| :--- | :--- |\n| **Base Creature** | [[Acolyte]] |",
+		"[[Acolyte]]":    "### Acolyte\n---\n| Property | Value |\n| :--- | :--- |\n| **Base Creature** | Cultist |",
+		"((block-uuid))": "### Echo\n---\n| Property | Value |\n| :--- | :--- |\n| **Base Creature** | [[Echo]] |",
+	})
+
+	tests := []struct {
+		name     string
+		creature Creature
+		expected string
+	}{
+		{"cycle", Creature{Name: "Fanatic", Base: "[[Cultist]]"}, "base creature cycle: [[Fanatic]] -> [[Cultist]] -> [[Acolyte]] -> [[Cultist]]"},
+		{"self", Creature{Name: "Echo", Base: "((block-uuid))"}, "base creature cycle: [[Echo]] -> ((block-uuid)) -> [[Echo]]"},
+		{"missing", Creature{Name: "Thug", Base: "[[Ruffian]]"}, "base creature [[Ruffian]]: no stat block found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveCreature(tt.creature, lookup)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
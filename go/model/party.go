@@ -0,0 +1,211 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Party is the group of player characters that adventure together.
+type Party struct {
+	Name    string        `json:"name"`
+	Members []PartyMember `json:"members"`
+	Notes   string        `json:"notes,omitempty"`
+}
+
+// PartyMember is what the party page keeps about a character, usually copied
+// from the character's sheet.
+type PartyMember struct {
+	Name                 string   `json:"name"`
+	Reference            string   `json:"reference,omitempty"`
+	Player               string   `json:"player,omitempty"`
+	Level                int      `json:"level"`
+	ArmorClass           int      `json:"armorClass,omitempty"`
+	HitPoints            int      `json:"hitPoints,omitempty"`
+	InitiativeBonus      int      `json:"initiativeBonus"`
+	PassivePerception    int      `json:"passivePerception,omitempty"`
+	PassiveInsight       int      `json:"passiveInsight,omitempty"`
+	PassiveInvestigation int      `json:"passiveInvestigation,omitempty"`
+	Languages            []string `json:"languages,omitempty"`
+	XP                   int      `json:"xp,omitempty"`
+}
+
+var partyColumns = []string{"Member", "Player", "Level", "AC", "HP", "Initiative", "Passive Perception", "Passive Insight", "Passive Investigation", "Languages", "XP"}
+
+// NewPartyMember takes a party member's details from their character sheet.
+// The reference is the page the sheet is on, e.g. "[[Mira Thorne]]".
+func NewPartyMember(character Character, reference string) PartyMember {
+	return PartyMember{
+		Name:                 character.Name,
+		Reference:            reference,
+		Player:               character.Player,
+		Level:                character.Level(),
+		ArmorClass:           character.ArmorClass,
+		HitPoints:            character.MaxHitPoints,
+		InitiativeBonus:      character.InitiativeBonus,
+		PassivePerception:    character.PassiveScore("Perception"),
+		PassiveInsight:       character.PassiveScore("Insight"),
+		PassiveInvestigation: character.PassiveScore("Investigation"),
+		Languages:            splitList(character.Languages),
+	}
+}
+
+// Levels lists the level of each member, as the difficulty calculator
+// expects.
+func (party *Party) Levels() []int {
+	levels := make([]int, len(party.Members))
+	for i, member := range party.Members {
+		levels[i] = member.Level
+	}
+	return levels
+}
+
+func (party *Party) AverageLevel() int {
+	if len(party.Members) == 0 {
+		return 0
+	}
+	total := 0
+	for _, member := range party.Members {
+		total += member.Level
+	}
+	return (total + len(party.Members)/2) / len(party.Members)
+}
+
+func (party *Party) TotalXP() int {
+	total := 0
+	for _, member := range party.Members {
+		total += member.XP
+	}
+	return total
+}
+
+// Languages lists every language at least one member speaks.
+func (party *Party) Languages() []string {
+	seen := map[string]string{}
+	for _, member := range party.Members {
+		for _, language := range member.Languages {
+			if _, ok := seen[strings.ToLower(language)]; !ok {
+				seen[strings.ToLower(language)] = language
+			}
+		}
+	}
+	languages := []string{}
+	for _, key := range sortedKeys(seen) {
+		languages = append(languages, seen[key])
+	}
+	return languages
+}
+
+// Difficulty rates an encounter with monsters of the given challenge ratings
+// against the party.
+func (party *Party) Difficulty(challenges []string) DifficultyRating {
+	return EncounterDifficulty(party.Levels(), challenges)
+}
+
+func (party *Party) FromMarkdown(content string) error {
+	*party = Party{Members: []PartyMember{}}
+	if content == "" {
+		return nil
+	}
+
+	var inNotes bool
+	var notes []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if match := sectionHeaderRegex.FindStringSubmatch(trimmedLine); len(match) > 1 {
+			inNotes = strings.TrimSpace(match[1]) == "NOTES"
+			continue
+		}
+		if strings.HasPrefix(trimmedLine, "### ") {
+			party.Name = strings.TrimSpace(strings.TrimPrefix(trimmedLine, "### "))
+			continue
+		}
+		if inNotes && trimmedLine != "---" {
+			notes = append(notes, line)
+		}
+	}
+	party.Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+
+	for _, table := range ParseTables(content) {
+		if !table.HasColumns("Member", "Level") {
+			continue
+		}
+		for i := range table.Rows {
+			member := PartyMember{Name: table.Get(i, "Member"), Player: table.Get(i, "Player")}
+			if match := pageReferenceRegex.FindStringSubmatch(member.Name); len(match) > 1 {
+				member.Name, member.Reference = match[1], match[0]
+			}
+			member.Level, _ = strconv.Atoi(table.Get(i, "Level"))
+			member.ArmorClass, _ = strconv.Atoi(table.Get(i, "AC"))
+			member.HitPoints, _ = strconv.Atoi(table.Get(i, "HP"))
+			member.InitiativeBonus, _ = strconv.Atoi(table.Get(i, "Initiative"))
+			member.PassivePerception, _ = strconv.Atoi(table.Get(i, "Passive Perception"))
+			member.PassiveInsight, _ = strconv.Atoi(table.Get(i, "Passive Insight"))
+			member.PassiveInvestigation, _ = strconv.Atoi(table.Get(i, "Passive Investigation"))
+			member.Languages = splitList(table.Get(i, "Languages"))
+			member.XP, _ = strconv.Atoi(strings.ReplaceAll(table.Get(i, "XP"), ",", ""))
+			party.Members = append(party.Members, member)
+		}
+	}
+	return nil
+}
+
+func (party *Party) ToMarkdown() (string, error) {
+	md := fmt.Sprintf("### %s\n", party.Name)
+	md += "---\n"
+
+	members := Table{Header: partyColumns, Align: []Alignment{
+		AlignLeft, AlignLeft, AlignCenter, AlignCenter, AlignCenter, AlignCenter,
+		AlignCenter, AlignCenter, AlignCenter, AlignLeft, AlignCenter,
+	}}
+	for _, member := range party.Members {
+		name := member.Name
+		if member.Reference != "" {
+			name = member.Reference
+		}
+		xp := ""
+		if member.XP != 0 {
+			xp = formatThousands(member.XP)
+		}
+		members.AddRow(name, member.Player, strconv.Itoa(member.Level), optionalInt(member.ArmorClass), optionalInt(member.HitPoints),
+			fmt.Sprintf("%+d", member.InitiativeBonus), optionalInt(member.PassivePerception), optionalInt(member.PassiveInsight),
+			optionalInt(member.PassiveInvestigation), strings.Join(member.Languages, ", "), xp)
+	}
+	md += members.String() + "\n"
+	md += "---\n"
+
+	summary := fmt.Sprintf("**Average Level:** %d, **Total XP:** %s", party.AverageLevel(), formatThousands(party.TotalXP()))
+	if languages := party.Languages(); len(languages) > 0 {
+		summary += fmt.Sprintf(", **Languages:** %s", strings.Join(languages, ", "))
+	}
+	md += summary + "\n"
+
+	if party.Notes != "" {
+		md += fmt.Sprintf("\n**NOTES**\n---\n%s\n", party.Notes)
+	}
+	return strings.TrimSpace(md), nil
+}
+
+// ToCombatant makes the member ready for the initiative tracker.
+func (member *PartyMember) ToCombatant(initiative int) Combatant {
+	return Combatant{
+		Name:            member.Name,
+		Initiative:      initiative,
+		Reference:       member.Reference,
+		ArmorClass:      member.ArmorClass,
+		HitPoints:       member.HitPoints,
+		InitiativeBonus: member.InitiativeBonus,
+	}
+}
+
+// AddParty adds every member of the party to the tracker, rolling each one's
+// initiative as a d20 plus their initiative bonus.
+func (it *InitiativeTracker) AddParty(party Party, roll Roller) {
+	if roll == nil {
+		roll = RollDie
+	}
+	for _, member := range party.Members {
+		it.Combatants = append(it.Combatants, member.ToCombatant(roll(20)+member.InitiativeBonus))
+	}
+	it.Sort()
+}
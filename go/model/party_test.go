@@ -0,0 +1,101 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartyFromMarkdown(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "parties", "lantern-bearers.md"))
+	assert.NoError(t, err)
+
+	var party Party
+	assert.NoError(t, party.FromMarkdown(string(content)))
+
+	assert.Equal(t, "The Lantern Bearers", party.Name)
+	assert.Len(t, party.Members, 3)
+	assert.Equal(t, PartyMember{
+		Name:                 "Mira Thorne",
+		Reference:            "[[Mira Thorne]]",
+		Player:               "Sam",
+		Level:                5,
+		ArmorClass:           16,
+		HitPoints:            38,
+		InitiativeBonus:      4,
+		PassivePerception:    14,
+		PassiveInsight:       11,
+		PassiveInvestigation: 19,
+		Languages:            []string{"Common", "Elvish", "Draconic"},
+		XP:                   6500,
+	}, party.Members[0])
+	assert.Equal(t, -1, party.Members[1].InitiativeBonus)
+	assert.Equal(t, "Pip", party.Members[2].Name)
+	assert.Equal(t, "", party.Members[2].Reference)
+	assert.Equal(t, "Hired by the Lanternwright Guild to escort the spring caravan.", party.Notes)
+
+	assert.Equal(t, []int{5, 5, 4}, party.Levels())
+	assert.Equal(t, 5, party.AverageLevel())
+	assert.Equal(t, 16800, party.TotalXP())
+	assert.Equal(t, []string{"Common", "Draconic", "Dwarvish", "Elvish", "Halfling"}, party.Languages())
+}
+
+func TestPartyMarkdownRoundTrip(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "parties", "lantern-bearers.md"))
+	assert.NoError(t, err)
+
+	var party Party
+	assert.NoError(t, party.FromMarkdown(string(content)))
+	markdown, err := party.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, string(content), markdown+"\n")
+}
+
+func TestNewPartyMember(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "characters", "mira.md"))
+	assert.NoError(t, err)
+	var character Character
+	assert.NoError(t, character.FromMarkdown(string(content)))
+
+	member := NewPartyMember(character, "[[Mira Thorne]]")
+	assert.Equal(t, "Mira Thorne", member.Name)
+	assert.Equal(t, "[[Mira Thorne]]", member.Reference)
+	assert.Equal(t, 5, member.Level)
+	assert.Equal(t, 38, member.HitPoints)
+	assert.Equal(t, 4, member.InitiativeBonus)
+	assert.Equal(t, 14, member.PassivePerception)
+	assert.Equal(t, 19, member.PassiveInvestigation)
+	assert.Equal(t, []string{"Common", "Elvish", "Draconic"}, member.Languages)
+}
+
+func TestPartyDifficulty(t *testing.T) {
+	party := Party{Members: []PartyMember{{Level: 3}, {Level: 3}, {Level: 3}, {Level: 3}}}
+	assert.Equal(t, EncounterDifficulty([]int{3, 3, 3, 3}, []string{"2"}), party.Difficulty([]string{"2"}))
+}
+
+func TestInitiativeTrackerAddParty(t *testing.T) {
+	party := Party{Members: []PartyMember{
+		{Name: "Mira Thorne", Reference: "[[Mira Thorne]]", ArmorClass: 16, HitPoints: 38, InitiativeBonus: 4},
+		{Name: "Pip", ArmorClass: 15, HitPoints: 27, InitiativeBonus: 3},
+	}}
+	it := InitiativeTracker{Round: 1, Combatants: []Combatant{{Name: "Goblin", Initiative: 12}}}
+
+	it.AddParty(party, func(int) int { return 10 })
+
+	assert.Equal(t, []Combatant{
+		{Name: "Mira Thorne", Initiative: 14, Reference: "[[Mira Thorne]]", ArmorClass: 16, HitPoints: 38, InitiativeBonus: 4},
+		{Name: "Pip", Initiative: 13, ArmorClass: 15, HitPoints: 27, InitiativeBonus: 3},
+		{Name: "Goblin", Initiative: 12},
+	}, it.Combatants)
+
+	markdown, err := it.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, markdown, "| Name | Initiative | Damage | Creature | AC | HP | Bonus |")
+	assert.Contains(t, markdown, "| Mira Thorne | 14 | 0 | [[Mira Thorne]] | 16 | 38 | +4 |")
+
+	var parsed InitiativeTracker
+	assert.NoError(t, parsed.FromMarkdown(markdown))
+	assert.Equal(t, it.Combatants, parsed.Combatants)
+}
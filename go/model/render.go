@@ -0,0 +1,94 @@
+package model
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+)
+
+type HTMLLayout string
+
+const (
+	// HTMLLayoutCard is a single column stat block for viewing on screen.
+	HTMLLayoutCard HTMLLayout = "card"
+	// HTMLLayoutPrint lays the stat block out in two columns on a letter page.
+	HTMLLayoutPrint HTMLLayout = "print"
+)
+
+//go:embed templates/statblock.html
+var statBlockTemplateText string
+
+var statBlockTemplate = template.Must(template.New("statblock").Parse(statBlockTemplateText))
+
+type renderedProperty struct {
+	Name  string
+	Value template.HTML
+}
+
+type renderedAbility struct {
+	Name     string
+	Score    int
+	Modifier string
+}
+
+type renderedSection struct {
+	Title string
+	Body  template.HTML
+}
+
+// ToHTML renders the creature as a self-contained HTML page in the classic
+// parchment style, with its CSS inline.
+func (creature *Creature) ToHTML(layout HTMLLayout) (string, error) {
+	if layout != HTMLLayoutCard && layout != HTMLLayoutPrint {
+		return "", fmt.Errorf("unknown stat block layout %q", layout)
+	}
+
+	data := struct {
+		Name            string
+		TypeLine        string
+		Print           bool
+		EarlyProperties []renderedProperty
+		LateProperties  []renderedProperty
+		Abilities       []renderedAbility
+		Sections        []renderedSection
+	}{
+		Name:     creature.Name,
+		TypeLine: creature.typeLine(),
+		Print:    layout == HTMLLayoutPrint,
+	}
+
+	early, late := splitHomebreweryProperties(placeExtras(creature.propertyRows(), creature.ExtraProperties))
+	for _, row := range early {
+		data.EarlyProperties = append(data.EarlyProperties, renderedProperty{row.name, template.HTML(markdownInlineToHTML(row.value))})
+	}
+	for _, row := range late {
+		data.LateProperties = append(data.LateProperties, renderedProperty{row.name, template.HTML(markdownInlineToHTML(row.value))})
+	}
+
+	scores := []int{
+		creature.AbilityScores.Strength,
+		creature.AbilityScores.Dexterity,
+		creature.AbilityScores.Constitution,
+		creature.AbilityScores.Intelligence,
+		creature.AbilityScores.Wisdom,
+		creature.AbilityScores.Charisma,
+	}
+	for i, name := range []string{"STR", "DEX", "CON", "INT", "WIS", "CHA"} {
+		data.Abilities = append(data.Abilities, renderedAbility{name, scores[i], GetModifier(scores[i])})
+	}
+
+	for _, section := range placeExtras(creature.sections(), creature.ExtraSections) {
+		title := homebreweryTitle(section.name)
+		if section.name == "TRAITS" {
+			title = ""
+		}
+		data.Sections = append(data.Sections, renderedSection{title, template.HTML(markdownToHTML(section.value))})
+	}
+
+	var buf bytes.Buffer
+	if err := statBlockTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatureToHTML(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "statblocks", "adult-red-dragon.md"))
+	assert.NoError(t, err)
+
+	var creature Creature
+	assert.NoError(t, creature.FromMarkdown(string(content)))
+
+	tests := []struct {
+		layout   HTMLLayout
+		expected []string
+		absent   []string
+	}{
+		{
+			layout: HTMLLayoutCard,
+			expected: []string{
+				"<h1>Adult Red Dragon</h1>",
+				`<p class="type-line">Huge dragon, chaotic evil</p>`,
+				"<strong>Armor Class</strong> <span>19 (natural armor)</span>",
+				"<div><strong>STR</strong><span>27 (&#43;8)</span></div>",
+				"<h3>Legendary Actions</h3>",
+				"<p><strong><em>Detect.</em></strong> The dragon makes a Wisdom (Perception) check.</p>",
+				"creature&#39;s turn",
+				`<svg class="rule"`,
+			},
+			absent: []string{`class="stat-block print"`},
+		},
+		{
+			layout:   HTMLLayoutPrint,
+			expected: []string{`<div class="stat-block print">`, "<h3>Lair Actions</h3>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.layout), func(t *testing.T) {
+			html, err := creature.ToHTML(tt.layout)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+			for _, fragment := range tt.expected {
+				assert.Contains(t, html, fragment)
+			}
+			for _, fragment := range tt.absent {
+				assert.NotContains(t, html, fragment)
+			}
+		})
+	}
+}
+
+func TestCreatureToHTMLEscapes(t *testing.T) {
+	creature := Creature{
+		Name:    "<script>alert(1)</script>",
+		Actions: []Action{{Name: "Bite", Description: "<img src=x onerror=alert(1)>"}},
+	}
+
+	html, err := creature.ToHTML(HTMLLayoutCard)
+	assert.NoError(t, err)
+	assert.NotContains(t, html, "<script>alert(1)</script>")
+	assert.NotContains(t, html, "<img src=x")
+}
+
+func TestCreatureToHTMLUnknownLayout(t *testing.T) {
+	var creature Creature
+	_, err := creature.ToHTML("poster")
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ResourceKind string
+
+const (
+	ResourceRecharge            ResourceKind = "recharge"
+	ResourcePerDay              ResourceKind = "day"
+	ResourceLegendaryResistance ResourceKind = "legendary"
+	ResourceSpellSlot           ResourceKind = "slot"
+	// ResourceShortRest and ResourceLongRest are features that come back
+	// after a short or a long rest.
+	ResourceShortRest ResourceKind = "short"
+	ResourceLongRest  ResourceKind = "long"
+)
+
+type Resource struct {
+	Name       string       `json:"name"`
+	Kind       ResourceKind `json:"kind"`
+	Remaining  int          `json:"remaining"`
+	Max        int          `json:"max"`
+	RechargeOn int          `json:"rechargeOn,omitempty"`
+	Level      int          `json:"level,omitempty"`
+}
+
+type RechargeRoll struct {
+	Combatant string `json:"combatant"`
+	Resource  string `json:"resource"`
+	Roll      int    `json:"roll"`
+	Recharged bool   `json:"recharged"`
+}
+
+// Roller returns a random number between 1 and sides inclusive.
+type Roller func(sides int) int
+
+func RollDie(sides int) int {
+	return rand.Intn(sides) + 1
+}
+
+// SeededRoller rolls the same sequence of dice every time for a seed.
+func SeededRoller(seed int64) Roller {
+	r := rand.New(rand.NewSource(seed))
+	return func(sides int) int {
+		return r.Intn(sides) + 1
+	}
+}
+
+var (
+	rechargeRegex     = regexp.MustCompile(`(?i)\(Recharge (\d)(?:\s*[–-]\s*6)?\)`)
+	perDayRegex       = regexp.MustCompile(`(?i)\((\d+)/Day(?: each)?\)`)
+	restRegex         = regexp.MustCompile(`(?i)\(Recharges after a (Short or )?Long Rest\)`)
+	spellSlotRegex    = regexp.MustCompile(`(?i)(\d)(?:st|nd|rd|th) level \((\d+) slots?\)`)
+	resourceCellRegex = regexp.MustCompile(`^(.*?) \[(\w+)(?: (\d+))?\] (\d+)/(\d+)$`)
+)
+
+// ResourcesFromCreature derives limited-use resources from the action names
+// of a creature, e.g. "Fire Breath (Recharge 5–6)" or "Legendary Resistance
+// (3/Day)", and from spell slots listed in spellcasting descriptions.
+func ResourcesFromCreature(creature Creature) []Resource {
+	resources := []Resource{}
+	sections := [][]Action{
+		creature.Traits,
+		creature.Actions,
+		creature.BonusActions,
+		creature.Reactions,
+		creature.LegendaryActions,
+		creature.MythicActions,
+		creature.Options,
+	}
+
+	for _, actions := range sections {
+		for _, action := range actions {
+			name := strings.TrimSpace(action.Name)
+			if idx := strings.Index(name, " ("); idx != -1 {
+				name = strings.TrimSpace(name[:idx])
+			}
+
+			if match := rechargeRegex.FindStringSubmatch(action.Name); len(match) > 1 {
+				on, _ := strconv.Atoi(match[1])
+				resources = append(resources, Resource{Name: name, Kind: ResourceRecharge, Remaining: 1, Max: 1, RechargeOn: on})
+			} else if match := perDayRegex.FindStringSubmatch(action.Name); len(match) > 1 {
+				uses, _ := strconv.Atoi(match[1])
+				kind := ResourcePerDay
+				if strings.EqualFold(name, "Legendary Resistance") {
+					kind = ResourceLegendaryResistance
+				}
+				resources = append(resources, Resource{Name: name, Kind: kind, Remaining: uses, Max: uses})
+			} else if match := restRegex.FindStringSubmatch(action.Name); match != nil {
+				kind := ResourceLongRest
+				if match[1] != "" {
+					kind = ResourceShortRest
+				}
+				resources = append(resources, Resource{Name: name, Kind: kind, Remaining: 1, Max: 1})
+			}
+
+			for _, match := range spellSlotRegex.FindAllStringSubmatch(action.Description, -1) {
+				level, _ := strconv.Atoi(match[1])
+				slots, _ := strconv.Atoi(match[2])
+				resources = append(resources, Resource{Name: spellSlotName(level), Kind: ResourceSpellSlot, Remaining: slots, Max: slots, Level: level})
+			}
+		}
+	}
+
+	return resources
+}
+
+func spellSlotName(level int) string {
+	return spellLevelName(level) + " slots"
+}
+
+// spellLevelName writes a spell level the way spell descriptions do, e.g.
+// "3rd-level".
+func spellLevelName(level int) string {
+	suffix := "th"
+	switch level {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s-level", level, suffix)
+}
+
+func (c *Combatant) UseResource(name string) error {
+	for i := range c.Resources {
+		resource := &c.Resources[i]
+		if !strings.EqualFold(resource.Name, name) {
+			continue
+		}
+		if resource.Remaining <= 0 {
+			return fmt.Errorf("%s has no uses of %s remaining", c.Name, resource.Name)
+		}
+		resource.Remaining--
+		return nil
+	}
+	return errors.New(c.Name + " has no resource named " + name)
+}
+
+// RollRecharges rolls a d6 for every spent recharge ability, restoring those
+// that meet their recharge number.
+func (c *Combatant) RollRecharges(roll Roller) []RechargeRoll {
+	if roll == nil {
+		roll = RollDie
+	}
+	rolls := []RechargeRoll{}
+	for i := range c.Resources {
+		resource := &c.Resources[i]
+		if resource.Kind != ResourceRecharge || resource.Remaining >= resource.Max {
+			continue
+		}
+		result := RechargeRoll{Combatant: c.Name, Resource: resource.Name, Roll: roll(6)}
+		if result.Roll >= resource.RechargeOn {
+			resource.Remaining = resource.Max
+			result.Recharged = true
+		}
+		rolls = append(rolls, result)
+	}
+	return rolls
+}
+
+func formatResources(resources []Resource) string {
+	cells := []string{}
+	for _, r := range resources {
+		tag := string(r.Kind)
+		switch r.Kind {
+		case ResourceRecharge:
+			tag += " " + strconv.Itoa(r.RechargeOn)
+		case ResourceSpellSlot:
+			tag += " " + strconv.Itoa(r.Level)
+		}
+		cells = append(cells, fmt.Sprintf("%s [%s] %d/%d", r.Name, tag, r.Remaining, r.Max))
+	}
+	return strings.Join(cells, "; ")
+}
+
+func parseResources(cell string) []Resource {
+	resources := []Resource{}
+	for _, part := range strings.Split(cell, ";") {
+		match := resourceCellRegex.FindStringSubmatch(strings.TrimSpace(part))
+		if len(match) < 6 {
+			continue
+		}
+		resource := Resource{Name: match[1], Kind: ResourceKind(match[2])}
+		resource.Remaining, _ = strconv.Atoi(match[4])
+		resource.Max, _ = strconv.Atoi(match[5])
+		if match[3] != "" {
+			value, _ := strconv.Atoi(match[3])
+			switch resource.Kind {
+			case ResourceRecharge:
+				resource.RechargeOn = value
+			case ResourceSpellSlot:
+				resource.Level = value
+			}
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
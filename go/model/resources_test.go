@@ -0,0 +1,100 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourcesFromCreature(t *testing.T) {
+	creature := Creature{
+		Name: "Adult Red Dragon",
+		Actions: []Action{
+			{Name: "Multiattack", Description: "The dragon makes three attacks."},
+			{Name: "Fire Breath (Recharge 5–6)", Description: "The dragon exhales fire in a 60-foot cone."},
+			{Name: "Spellcasting", Description: "The dragon has the following wizard spells prepared:\n\n1st level (4 slots): shield, magic missile\n\n2nd level (3 slots): misty step"},
+		},
+		BonusActions: []Action{
+			{Name: "Misty Step (2/Day)", Description: "The dragon teleports up to 30 feet."},
+		},
+		Reactions: []Action{
+			{Name: "Wing Buffet (Recharges after a Short or Long Rest)", Description: "The dragon beats its wings."},
+		},
+		Options: []Action{
+			{Name: "Legendary Resistance (3/Day)", Description: "If the dragon fails a saving throw, it can choose to succeed instead."},
+		},
+	}
+
+	assert.Equal(t, []Resource{
+		{Name: "Fire Breath", Kind: ResourceRecharge, Remaining: 1, Max: 1, RechargeOn: 5},
+		{Name: "1st-level slots", Kind: ResourceSpellSlot, Remaining: 4, Max: 4, Level: 1},
+		{Name: "2nd-level slots", Kind: ResourceSpellSlot, Remaining: 3, Max: 3, Level: 2},
+		{Name: "Misty Step", Kind: ResourcePerDay, Remaining: 2, Max: 2},
+		{Name: "Wing Buffet", Kind: ResourceShortRest, Remaining: 1, Max: 1},
+		{Name: "Legendary Resistance", Kind: ResourceLegendaryResistance, Remaining: 3, Max: 3},
+	}, ResourcesFromCreature(creature))
+}
+
+func TestCombatantResources(t *testing.T) {
+	combatant := Combatant{
+		Name: "Dragon",
+		Resources: []Resource{
+			{Name: "Fire Breath", Kind: ResourceRecharge, Remaining: 1, Max: 1, RechargeOn: 5},
+			{Name: "Legendary Resistance", Kind: ResourceLegendaryResistance, Remaining: 1, Max: 3},
+		},
+	}
+
+	assert.NoError(t, combatant.UseResource("fire breath"))
+	assert.Error(t, combatant.UseResource("Fire Breath"))
+	assert.Error(t, combatant.UseResource("Tail Attack"))
+
+	rolls := combatant.RollRecharges(func(sides int) int { return 4 })
+	assert.Equal(t, []RechargeRoll{{Combatant: "Dragon", Resource: "Fire Breath", Roll: 4}}, rolls)
+	assert.Equal(t, 0, combatant.Resources[0].Remaining)
+
+	rolls = combatant.RollRecharges(func(sides int) int { return 6 })
+	assert.True(t, rolls[0].Recharged)
+	assert.Equal(t, 1, combatant.Resources[0].Remaining)
+	assert.Empty(t, combatant.RollRecharges(func(sides int) int { return 6 }))
+}
+
+func TestResourcesSurviveInitiativeMarkdown(t *testing.T) {
+	dragon := Creature{
+		Name:          "Adult Red Dragon",
+		AbilityScores: AbilityScores{Dexterity: 10},
+		Actions:       []Action{{Name: "Fire Breath (Recharge 5–6)", Description: "The dragon exhales fire."}},
+		Options:       []Action{{Name: "Legendary Resistance (3/Day)", Description: "The dragon succeeds instead."}},
+	}
+
+	it := InitiativeTracker{Round: 1}
+	it.AddCreature(dragon, "", 15)
+	it.Combatants = append(it.Combatants, Combatant{Name: "Player 1", Initiative: 10})
+	assert.NoError(t, it.Combatants[0].UseResource("Fire Breath"))
+
+	markdown, err := it.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, markdown, "| Name | Initiative | Damage | Resources |")
+	assert.Contains(t, markdown, "| Adult Red Dragon | 15 | 0 | Fire Breath [recharge 5] 0/1; Legendary Resistance [legendary] 3/3 |")
+
+	// The table doesn't keep the action names; they come from the stat block
+	// when a creature is added.
+	expected := append([]Combatant{}, it.Combatants...)
+	expected[0].Actions = nil
+
+	var parsed InitiativeTracker
+	assert.NoError(t, parsed.FromMarkdown(markdown))
+	assert.Equal(t, expected, parsed.Combatants)
+
+	parsed.NextTurn(nil)
+	rolls := parsed.NextTurn(func(sides int) int { return 5 })
+	assert.Equal(t, []RechargeRoll{{Combatant: "Adult Red Dragon", Resource: "Fire Breath", Roll: 5, Recharged: true}}, rolls)
+}
+
+func TestSeededRoller(t *testing.T) {
+	first, second := SeededRoller(1), SeededRoller(1)
+	for i := 0; i < 20; i++ {
+		rolled := first(20)
+		assert.Equal(t, rolled, second(20))
+		assert.True(t, rolled >= 1 && rolled <= 20)
+	}
+}
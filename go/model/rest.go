@@ -0,0 +1,140 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+type RestKind string
+
+const (
+	RestShort RestKind = "short"
+	RestLong  RestKind = "long"
+)
+
+// ShortRest spends up to maxHitDice hit dice, largest first, each healing a
+// roll of the die plus the Constitution modifier, and stops once the
+// character is at full hit points. Features that recharge on a short rest
+// come back. It returns a log of what happened.
+func (character *Character) ShortRest(maxHitDice int, roll Roller) []string {
+	if roll == nil {
+		roll = RollDie
+	}
+	log := []string{}
+	con := modifier(character.AbilityScores.Constitution)
+
+	spent := 0
+	for _, dice := range character.hitDiceLargestFirst() {
+		for dice.Remaining > 0 && spent < maxHitDice && character.HitPoints < character.MaxHitPoints {
+			rolled := roll(dice.Die)
+			healed := min(max(rolled+con, 0), character.MaxHitPoints-character.HitPoints)
+			character.HitPoints += healed
+			dice.Remaining--
+			spent++
+			log = append(log, fmt.Sprintf("%s spends a d%d hit die (%d%+d) and regains %d hit points.",
+				character.Name, dice.Die, rolled, con, healed))
+		}
+	}
+
+	log = append(log, restoreResources(character.Name, character.Resources, RestShort)...)
+	return log
+}
+
+// LongRest restores all hit points, half of the character's hit dice
+// (at least one), spell slots and limited-use features, and lowers
+// exhaustion by one level. It returns a log of what happened.
+func (character *Character) LongRest() []string {
+	log := []string{}
+
+	if character.HitPoints < character.MaxHitPoints || character.TemporaryHitPoints > 0 {
+		character.HitPoints = character.MaxHitPoints
+		character.TemporaryHitPoints = 0
+		log = append(log, fmt.Sprintf("%s regains all hit points (%d).", character.Name, character.MaxHitPoints))
+	}
+
+	total := 0
+	for _, dice := range character.HitDice {
+		total += dice.Max
+	}
+	regain := max(total/2, 1)
+	regained := 0
+	for _, dice := range character.hitDiceLargestFirst() {
+		restored := min(dice.Max-dice.Remaining, regain-regained)
+		dice.Remaining += restored
+		regained += restored
+	}
+	if regained > 0 {
+		noun := "dice"
+		if regained == 1 {
+			noun = "die"
+		}
+		log = append(log, fmt.Sprintf("%s regains %d hit %s.", character.Name, regained, noun))
+	}
+
+	if character.Exhaustion > 0 {
+		character.Exhaustion--
+		log = append(log, fmt.Sprintf("%s's exhaustion drops to %d.", character.Name, character.Exhaustion))
+	}
+
+	log = append(log, restoreResources(character.Name, character.Resources, RestLong)...)
+	return log
+}
+
+// Rest applies a short or long rest to a combatant. A short rest recharges
+// abilities and short-rest features; a long rest also heals all damage and
+// restores every resource and legendary action.
+func (c *Combatant) Rest(kind RestKind) []string {
+	log := []string{}
+	if kind == RestLong {
+		if c.Damage > 0 {
+			c.Damage = 0
+			log = append(log, fmt.Sprintf("%s regains all hit points.", c.Name))
+		}
+		c.LegendaryActions = c.LegendaryActionsMax
+	}
+	return append(log, restoreResources(c.Name, c.Resources, kind)...)
+}
+
+// Rest gives every combatant in the tracker a short or long rest.
+func (it *InitiativeTracker) Rest(kind RestKind) ([]string, error) {
+	if kind != RestShort && kind != RestLong {
+		return nil, fmt.Errorf("unknown rest %q", kind)
+	}
+	log := []string{}
+	for i := range it.Combatants {
+		if !it.Combatants[i].Lair {
+			log = append(log, it.Combatants[i].Rest(kind)...)
+		}
+	}
+	return log, nil
+}
+
+// restoreResources refills the spent resources a rest restores: recharge
+// abilities and short-rest features after any rest, and everything after a
+// long rest.
+func restoreResources(owner string, resources []Resource, kind RestKind) []string {
+	log := []string{}
+	for i := range resources {
+		resource := &resources[i]
+		if resource.Remaining >= resource.Max {
+			continue
+		}
+		if kind == RestShort && resource.Kind != ResourceShortRest && resource.Kind != ResourceRecharge {
+			continue
+		}
+		resource.Remaining = resource.Max
+		log = append(log, fmt.Sprintf("%s regains %s (%d/%d).", owner, resource.Name, resource.Remaining, resource.Max))
+	}
+	return log
+}
+
+// hitDiceLargestFirst points at the character's hit dice in the order a
+// rest spends and regains them.
+func (character *Character) hitDiceLargestFirst() []*HitDice {
+	dice := make([]*HitDice, len(character.HitDice))
+	for i := range character.HitDice {
+		dice[i] = &character.HitDice[i]
+	}
+	sort.SliceStable(dice, func(i, j int) bool { return dice[i].Die > dice[j].Die })
+	return dice
+}
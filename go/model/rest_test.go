@@ -0,0 +1,130 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacterRest(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "characters", "mira.md"))
+	assert.NoError(t, err)
+	var character Character
+	assert.NoError(t, character.FromMarkdown(string(content)))
+	character.Exhaustion = 2
+
+	log := character.ShortRest(5, func(int) int { return 3 })
+	assert.Equal(t, []string{
+		"Mira Thorne spends a d10 hit die (3+2) and regains 5 hit points.",
+		"Mira Thorne spends a d6 hit die (3+2) and regains 2 hit points.",
+		"Mira Thorne regains Second Wind (1/1).",
+	}, log)
+	assert.Equal(t, 38, character.HitPoints)
+	assert.Equal(t, 4, character.TemporaryHitPoints)
+	assert.Equal(t, []HitDice{{Die: 10, Remaining: 0, Max: 2}, {Die: 6, Remaining: 2, Max: 3}}, character.HitDice)
+	assert.Equal(t, 2, character.Resources[0].Remaining, "spell slots only come back after a long rest")
+
+	log = character.LongRest()
+	assert.Equal(t, []string{
+		"Mira Thorne regains all hit points (38).",
+		"Mira Thorne regains 2 hit dice.",
+		"Mira Thorne's exhaustion drops to 1.",
+		"Mira Thorne regains 1st-level slots (4/4).",
+	}, log)
+	assert.Equal(t, 0, character.TemporaryHitPoints)
+	assert.Equal(t, []HitDice{{Die: 10, Remaining: 2, Max: 2}, {Die: 6, Remaining: 2, Max: 3}}, character.HitDice)
+
+	markdown, err := character.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, markdown, "| **Exhaustion** | 1 |")
+	var parsed Character
+	assert.NoError(t, parsed.FromMarkdown(markdown))
+	assert.Equal(t, 1, parsed.Exhaustion)
+}
+
+func TestCharacterShortRestLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		character  Character
+		maxHitDice int
+		roll       int
+		hitPoints  int
+		remaining  int
+	}{
+		{"stops at max dice", Character{HitPoints: 1, MaxHitPoints: 40, AbilityScores: AbilityScores{Constitution: 10}, HitDice: []HitDice{{Die: 8, Remaining: 3, Max: 3}}}, 2, 4, 9, 1},
+		{"stops at full", Character{HitPoints: 38, MaxHitPoints: 40, AbilityScores: AbilityScores{Constitution: 10}, HitDice: []HitDice{{Die: 8, Remaining: 3, Max: 3}}}, 3, 4, 40, 2},
+		{"no dice left", Character{HitPoints: 1, MaxHitPoints: 40, AbilityScores: AbilityScores{Constitution: 10}, HitDice: []HitDice{{Die: 8, Remaining: 0, Max: 3}}}, 3, 4, 1, 0},
+		{"never negative", Character{HitPoints: 1, MaxHitPoints: 40, AbilityScores: AbilityScores{Constitution: 6}, HitDice: []HitDice{{Die: 8, Remaining: 1, Max: 1}}}, 1, 1, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.character.ShortRest(tt.maxHitDice, func(int) int { return tt.roll })
+			assert.Equal(t, tt.hitPoints, tt.character.HitPoints)
+			assert.Equal(t, tt.remaining, tt.character.HitDice[0].Remaining)
+		})
+	}
+}
+
+func TestInitiativeTrackerRest(t *testing.T) {
+	tracker := InitiativeTracker{Round: 3, Combatants: []Combatant{
+		{Name: "Dragon", Damage: 40, LegendaryActions: 1, LegendaryActionsMax: 3, Resources: []Resource{
+			{Name: "Fire Breath", Kind: ResourceRecharge, Remaining: 0, Max: 1},
+			{Name: "Legendary Resistance", Kind: ResourceLegendaryResistance, Remaining: 1, Max: 3},
+		}},
+		{Name: "Lair", Lair: true},
+		{Name: "Mira Thorne", Damage: 5, Resources: []Resource{
+			{Name: "Second Wind", Kind: ResourceShortRest, Remaining: 0, Max: 1},
+			{Name: "1st-level slots", Kind: ResourceSpellSlot, Remaining: 1, Max: 4, Level: 1},
+		}},
+	}}
+
+	log, err := tracker.Rest(RestShort)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Dragon regains Fire Breath (1/1).",
+		"Mira Thorne regains Second Wind (1/1).",
+	}, log)
+	assert.Equal(t, 40, tracker.Combatants[0].Damage)
+	assert.Equal(t, 1, tracker.Combatants[0].LegendaryActions)
+
+	log, err = tracker.Rest(RestLong)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Dragon regains all hit points.",
+		"Dragon regains Legendary Resistance (3/3).",
+		"Mira Thorne regains all hit points.",
+		"Mira Thorne regains 1st-level slots (4/4).",
+	}, log)
+	assert.Equal(t, 0, tracker.Combatants[0].Damage)
+	assert.Equal(t, 3, tracker.Combatants[0].LegendaryActions)
+}
+
+func TestCreatureShortRest(t *testing.T) {
+	creature := Creature{Name: "Dragon", Reactions: []Action{
+		{Name: "Wing Buffet (Recharges after a Short or Long Rest)", Description: "The dragon beats its wings."},
+		{Name: "Frightful Roar (Recharges after a Long Rest)", Description: "The dragon roars."},
+	}}
+	tracker := InitiativeTracker{Round: 2, Combatants: []Combatant{NewCombatant(creature, "", 12)}}
+	assert.Equal(t, []Resource{
+		{Name: "Wing Buffet", Kind: ResourceShortRest, Remaining: 1, Max: 1},
+		{Name: "Frightful Roar", Kind: ResourceLongRest, Remaining: 1, Max: 1},
+	}, tracker.Combatants[0].Resources)
+	assert.NoError(t, tracker.Combatants[0].UseResource("Wing Buffet"))
+	assert.NoError(t, tracker.Combatants[0].UseResource("Frightful Roar"))
+
+	log, err := tracker.Rest(RestShort)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Dragon regains Wing Buffet (1/1)."}, log)
+	assert.Equal(t, 0, tracker.Combatants[0].Resources[1].Remaining)
+
+	log, err = tracker.Rest(RestLong)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Dragon regains Frightful Roar (1/1)."}, log)
+
+	assert.NoError(t, tracker.Combatants[0].UseResource("Frightful Roar"))
+	_, err = tracker.Rest(RestKind("bogus"))
+	assert.EqualError(t, err, `unknown rest "bogus"`)
+	assert.Equal(t, 0, tracker.Combatants[0].Resources[1].Remaining)
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+type RuleKind string
+
+const (
+	RuleCondition  RuleKind = "condition"
+	RuleDamageType RuleKind = "damageType"
+	RuleSkill      RuleKind = "skill"
+	RuleAbility    RuleKind = "ability"
+	RuleSection    RuleKind = "rule"
+)
+
+// Rule is an entry in the rules reference: a condition, damage type, skill,
+// ability or section of the rules.
+type Rule struct {
+	Kind        RuleKind `json:"kind"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	// Ability is the ability a skill uses.
+	Ability string `json:"ability,omitempty"`
+}
+
+// RulesReference looks up and searches rules.
+type RulesReference struct {
+	rules []Rule
+}
+
+//go:embed data/rules.json
+var rulesJSON []byte
+
+var defaultRules = mustParseRules(rulesJSON)
+
+func mustParseRules(data []byte) []Rule {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// DefaultRules is the SRD rules reference built into the plugin, for use
+// offline.
+func DefaultRules() *RulesReference {
+	return NewRulesReference(defaultRules)
+}
+
+// SkillAbility returns the ability a skill uses, such as "Dexterity" for
+// "Stealth", from the built-in rules reference.
+func SkillAbility(skill string) (string, bool) {
+	for _, rule := range defaultRules {
+		if rule.Kind == RuleSkill && strings.EqualFold(rule.Name, skill) {
+			return rule.Ability, rule.Ability != ""
+		}
+	}
+	return "", false
+}
+
+func NewRulesReference(rules []Rule) *RulesReference {
+	reference := &RulesReference{rules: append([]Rule{}, rules...)}
+	sort.SliceStable(reference.rules, func(i, j int) bool {
+		if reference.rules[i].Kind != reference.rules[j].Kind {
+			return reference.rules[i].Kind < reference.rules[j].Kind
+		}
+		return reference.rules[i].Name < reference.rules[j].Name
+	})
+	return reference
+}
+
+// Merge returns a reference with the given rules replacing those of the same
+// kind and name, keeping the rest.
+func (reference *RulesReference) Merge(rules []Rule) *RulesReference {
+	replacements := NewRulesReference(rules)
+	merged := append([]Rule{}, rules...)
+	for _, rule := range reference.rules {
+		if _, ok := replacements.Find(rule.Kind, rule.Name); !ok {
+			merged = append(merged, rule)
+		}
+	}
+	return NewRulesReference(merged)
+}
+
+// Rules lists the rules of a kind, or every rule for an empty kind.
+func (reference *RulesReference) Rules(kind RuleKind) []Rule {
+	rules := []Rule{}
+	for _, rule := range reference.rules {
+		if kind == "" || rule.Kind == kind {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Find looks up a rule by name, ignoring case. An empty kind matches any.
+func (reference *RulesReference) Find(kind RuleKind, name string) (Rule, bool) {
+	for _, rule := range reference.rules {
+		if (kind == "" || rule.Kind == kind) && strings.EqualFold(rule.Name, strings.TrimSpace(name)) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Search returns the rules whose name or description contains every word of
+// the query. Rules named by the query come first, then those with the most
+// mentions of it.
+func (reference *RulesReference) Search(query string) []Rule {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return []Rule{}
+	}
+
+	type result struct {
+		rule  Rule
+		score int
+	}
+	var results []result
+	for _, rule := range reference.rules {
+		name := strings.ToLower(rule.Name)
+		description := strings.ToLower(rule.Description)
+		score := 0
+		for _, term := range terms {
+			mentions := strings.Count(description, term)
+			if strings.Contains(name, term) {
+				mentions += 100
+			}
+			if mentions == 0 {
+				score = 0
+				break
+			}
+			score += mentions
+		}
+		if score > 0 {
+			if name == strings.Join(terms, " ") {
+				score += 1000
+			}
+			results = append(results, result{rule, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	rules := make([]Rule, len(results))
+	for i, result := range results {
+		rules[i] = result.rule
+	}
+	return rules
+}
+
+// Document makes the rule searchable, tagged with its kind.
+func (rule *Rule) Document() Document {
+	return Document{Kind: string(rule.Kind), Name: rule.Name, Text: rule.Description}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRules(t *testing.T) {
+	rules := DefaultRules()
+
+	assert.Len(t, rules.Rules(RuleCondition), 15)
+	assert.Len(t, rules.Rules(RuleDamageType), 13)
+	assert.Len(t, rules.Rules(RuleAbility), 6)
+	assert.Len(t, rules.Rules(RuleSkill), 18)
+	assert.NotEmpty(t, rules.Rules(RuleSection))
+
+	grappled, ok := rules.Find(RuleCondition, "grappled")
+	assert.True(t, ok)
+	assert.Equal(t, "Grappled", grappled.Name)
+	assert.Contains(t, grappled.Description, "speed becomes 0")
+
+	stealth, ok := rules.Find("", "Stealth")
+	assert.True(t, ok)
+	assert.Equal(t, RuleSkill, stealth.Kind)
+	assert.Equal(t, "Dexterity", stealth.Ability)
+
+	_, ok = rules.Find(RuleCondition, "Fire")
+	assert.False(t, ok)
+}
+
+func TestRulesSearch(t *testing.T) {
+	rules := NewRulesReference([]Rule{
+		{Kind: RuleCondition, Name: "Grappled", Description: "A grappled creature's speed becomes 0."},
+		{Kind: RuleSection, Name: "Grappling", Description: "You can use the Attack action to make a grapple. If you succeed, the target is grappled."},
+		{Kind: RuleCondition, Name: "Prone", Description: "A prone creature's only movement option is to crawl."},
+		{Kind: RuleCondition, Name: "Restrained", Description: "A restrained creature's speed becomes 0."},
+	})
+	names := func(rules []Rule) []string {
+		result := []string{}
+		for _, rule := range rules {
+			result = append(result, rule.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"grappled", []string{"Grappled", "Grappling"}},
+		{"grappling", []string{"Grappling"}},
+		{"grapple", []string{"Grappled", "Grappling"}},
+		{"speed 0", []string{"Grappled", "Restrained"}},
+		{"crawl", []string{"Prone"}},
+		{"flying", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.expected, names(rules.Search(tt.query)))
+		})
+	}
+}
+
+func TestRulesMerge(t *testing.T) {
+	rules := NewRulesReference([]Rule{
+		{Kind: RuleCondition, Name: "Prone", Description: "Old."},
+		{Kind: RuleCondition, Name: "Stunned", Description: "Stunned."},
+	}).Merge([]Rule{
+		{Kind: RuleCondition, Name: "Prone", Description: "New."},
+		{Kind: RuleDamageType, Name: "Fire", Description: "Fire."},
+	})
+
+	assert.Len(t, rules.Rules(""), 3)
+	prone, _ := rules.Find(RuleCondition, "Prone")
+	assert.Equal(t, "New.", prone.Description)
+	_, ok := rules.Find(RuleCondition, "Stunned")
+	assert.True(t, ok)
+}
+
+func TestSkillAbility(t *testing.T) {
+	ability, ok := SkillAbility("sleight of hand")
+	assert.True(t, ok)
+	assert.Equal(t, "Dexterity", ability)
+
+	_, ok = SkillAbility("Basket Weaving")
+	assert.False(t, ok)
+}
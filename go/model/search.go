@@ -0,0 +1,218 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Document is a record in the search index, such as a monster, spell, item
+// or rule. Kind tags the results, e.g. "spell", and Reference, if set, says
+// where the record lives, e.g. the block of a homebrew creature.
+type Document struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Text      string `json:"text"`
+	Reference string `json:"reference,omitempty"`
+}
+
+type SearchResult struct {
+	Kind      string  `json:"kind"`
+	Name      string  `json:"name"`
+	Reference string  `json:"reference,omitempty"`
+	Score     float64 `json:"score"`
+}
+
+// SearchIndex is an inverted index over documents. Words are matched by their
+// stems, so "grappled" finds "grappling", and a match in a name counts for
+// more than one in the text.
+type SearchIndex struct {
+	documents []Document
+	postings  map[string][]posting
+}
+
+type searchField int
+
+const (
+	fieldName searchField = iota
+	fieldText
+)
+
+var fieldBoosts = [...]float64{fieldName: 5, fieldText: 1}
+
+// posting records where a term appears in one field of one document.
+type posting struct {
+	document  int
+	field     searchField
+	positions []int
+}
+
+func NewSearchIndex(documents []Document) *SearchIndex {
+	index := &SearchIndex{documents: documents, postings: map[string][]posting{}}
+	for i, document := range documents {
+		index.add(i, fieldName, document.Name)
+		index.add(i, fieldText, document.Text)
+	}
+	return index
+}
+
+func (index *SearchIndex) add(document int, field searchField, text string) {
+	positions := map[string][]int{}
+	var order []string
+	for position, term := range tokenize(text) {
+		if _, ok := positions[term]; !ok {
+			order = append(order, term)
+		}
+		positions[term] = append(positions[term], position)
+	}
+	for _, term := range order {
+		index.postings[term] = append(index.postings[term], posting{document, field, positions[term]})
+	}
+}
+
+// Search finds the documents that contain every word of the query. Words in
+// double quotes must appear together as a phrase. At most limit results are
+// returned, best first; a limit of 0 returns them all.
+func (index *SearchIndex) Search(query string, limit int) []SearchResult {
+	var phrases [][]string
+	for i, part := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			if terms := tokenize(part); len(terms) > 0 {
+				phrases = append(phrases, terms)
+			}
+			continue
+		}
+		for _, term := range tokenize(part) {
+			phrases = append(phrases, []string{term})
+		}
+	}
+	if len(phrases) == 0 {
+		return []SearchResult{}
+	}
+
+	var scores map[int]float64
+	for _, phrase := range phrases {
+		matches := index.matchPhrase(phrase)
+		if scores == nil {
+			scores = matches
+			continue
+		}
+		for document, score := range scores {
+			if match, ok := matches[document]; ok {
+				scores[document] = score + match
+			} else {
+				delete(scores, document)
+			}
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for document, score := range scores {
+		d := index.documents[document]
+		results = append(results, SearchResult{Kind: d.Kind, Name: d.Name, Reference: d.Reference, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Name < results[j].Name
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// matchPhrase scores the documents in which the terms appear one after the
+// other in the same field.
+func (index *SearchIndex) matchPhrase(terms []string) map[int]float64 {
+	scores := map[int]float64{}
+	for _, first := range index.postings[terms[0]] {
+		count := 0
+		for _, start := range first.positions {
+			if index.followedBy(first.document, first.field, start, terms[1:]) {
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(len(index.documents))/float64(len(index.postings[terms[0]])))
+		scores[first.document] += fieldBoosts[first.field] * (1 + math.Log(float64(count))) * idf * float64(len(terms))
+	}
+	return scores
+}
+
+func (index *SearchIndex) followedBy(document int, field searchField, start int, terms []string) bool {
+	for offset, term := range terms {
+		p, ok := index.posting(term, document, field)
+		if !ok {
+			return false
+		}
+		i := sort.SearchInts(p.positions, start+offset+1)
+		if i == len(p.positions) || p.positions[i] != start+offset+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// posting finds where a term appears in one field of a document. Postings
+// are added in document and field order, so they can be searched.
+func (index *SearchIndex) posting(term string, document int, field searchField) (posting, bool) {
+	postings := index.postings[term]
+	i := sort.Search(len(postings), func(i int) bool {
+		return postings[i].document > document || (postings[i].document == document && postings[i].field >= field)
+	})
+	if i < len(postings) && postings[i].document == document && postings[i].field == field {
+		return postings[i], true
+	}
+	return posting{}, false
+}
+
+// tokenize splits text into lower-case word stems.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.Trim(word, "'"); word != "" {
+			terms = append(terms, stem(word))
+		}
+	}
+	return terms
+}
+
+// stem strips common English endings so that forms of a word match, e.g.
+// "grapple", "grappled" and "grappling" all become "grappl". It is a small
+// subset of the Porter stemmer, enough for rules text.
+func stem(word string) string {
+	word = strings.TrimSuffix(word, "'s")
+	if len(word) <= 3 {
+		return word
+	}
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"):
+	case strings.HasSuffix(word, "s"):
+		word = word[:len(word)-1]
+	}
+	for _, suffix := range []string{"ing", "ed"} {
+		if base, ok := strings.CutSuffix(word, suffix); ok && len(base) >= 3 && strings.ContainsAny(base, "aeiouy") {
+			word = base
+			if n := len(word); word[n-1] == word[n-2] && !strings.ContainsRune("lsz", rune(word[n-1])) {
+				word = word[:n-1]
+			}
+			break
+		}
+	}
+	if len(word) > 4 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
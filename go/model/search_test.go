@@ -0,0 +1,137 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"grapple":   "grappl",
+		"grappled":  "grappl",
+		"grappling": "grappl",
+		"dragons":   "dragon",
+		"abilities": "ability",
+		"running":   "run",
+		"falling":   "fall",
+		"class":     "class",
+		"bless":     "bless",
+		"undead":    "undead",
+		"fire":      "fire",
+		"owlbear's": "owlbear",
+	}
+	for word, expected := range tests {
+		t.Run(word, func(t *testing.T) {
+			assert.Equal(t, expected, stem(word))
+		})
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	documents := []Document{
+		{Kind: "condition", Name: "Grappled", Text: "A grappled creature's speed becomes 0, and it can't benefit from any bonus to its speed."},
+		{Kind: "rule", Name: "Grappling", Text: "When you want to grab a creature or wrestle with it, you can use the Attack action to make a special melee attack, a grapple."},
+		{Kind: "spell", Name: "Fireball", Text: "3rd-level evocation\nA bright streak flashes from your pointing finger and then blossoms with a low roar into an explosion of flame."},
+		{Kind: "spell", Name: "Fire Bolt", Text: "Evocation cantrip\nYou hurl a mote of fire at a creature or object within range."},
+		{Kind: "monster", Name: "Red Dragon Wyrmling", Text: "Medium dragon, chaotic evil\nFire Breath. The dragon exhales fire in a 15-foot cone."},
+		{Kind: "item", Name: "Potion of Fire Breath", Text: "Potion, uncommon\nAfter drinking this potion, you can use a bonus action to exhale fire at a target within 30 feet of you."},
+	}
+	index := NewSearchIndex(documents)
+
+	names := func(results []SearchResult) []string {
+		names := []string{}
+		for _, result := range results {
+			names = append(names, result.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		limit    int
+		expected []string
+	}{
+		{"stems match", "grapple", 0, []string{"Grappled", "Grappling"}},
+		{"every word must match", "grappled speed", 0, []string{"Grappled"}},
+		{"names count for more", "fire", 0, []string{"Fire Bolt", "Potion of Fire Breath", "Red Dragon Wyrmling"}},
+		{"phrase", `"fire breath"`, 0, []string{"Potion of Fire Breath", "Red Dragon Wyrmling"}},
+		{"phrase in order", `"breath fire"`, 0, []string{}},
+		{"phrase and word", `"exhale fire" potion`, 0, []string{"Potion of Fire Breath"}},
+		{"limit", "fire", 1, []string{"Fire Bolt"}},
+		{"case and punctuation", "FIREBALL!", 0, []string{"Fireball"}},
+		{"no match", "tarrasque", 0, []string{}},
+		{"empty", "  ", 0, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, names(index.Search(tt.query, tt.limit)))
+		})
+	}
+
+	results := index.Search("fireball", 0)
+	assert.Equal(t, "spell", results[0].Kind)
+	assert.Greater(t, results[0].Score, 0.0)
+}
+
+func TestSearchIndexDocuments(t *testing.T) {
+	spell := Spell{Name: "Fireball", Level: 3, School: "Evocation", Description: "An explosion of flame."}
+	rule, _ := DefaultRules().Find(RuleCondition, "Prone")
+	index := NewSearchIndex([]Document{spell.Document(), longsword.Document(), rule.Document()})
+
+	assert.Equal(t, []SearchResult{{Kind: "spell", Name: "Fireball"}}, withoutScores(index.Search("evocation flame", 0)))
+	assert.Equal(t, []SearchResult{{Kind: "item", Name: "Longsword"}}, withoutScores(index.Search("martial weapon", 0)))
+	assert.Equal(t, []SearchResult{{Kind: "condition", Name: "Prone"}}, withoutScores(index.Search("crawl", 0)))
+}
+
+func withoutScores(results []SearchResult) []SearchResult {
+	for i := range results {
+		results[i].Score = 0
+	}
+	return results
+}
+
+// benchmarkDocuments makes a corpus about the size of the SRD: some 2,000
+// monsters, spells, items and rules.
+func benchmarkDocuments() []Document {
+	var documents []Document
+	for _, rule := range DefaultRules().Rules("") {
+		documents = append(documents, rule.Document())
+	}
+	for i := 0; len(documents) < 2000; i++ {
+		for _, rule := range defaultRules {
+			documents = append(documents, Document{
+				Kind: "monster",
+				Name: fmt.Sprintf("%s Creature %d", rule.Name, i),
+				Text: rule.Description + " The creature attacks with claws and bites, dealing slashing and piercing damage.",
+			})
+		}
+	}
+	return documents
+}
+
+func BenchmarkNewSearchIndex(b *testing.B) {
+	documents := benchmarkDocuments()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewSearchIndex(documents)
+	}
+}
+
+func BenchmarkSearchIndexSearch(b *testing.B) {
+	index := NewSearchIndex(benchmarkDocuments())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Search("grappled creature", 20)
+	}
+}
+
+func BenchmarkSearchIndexPhrase(b *testing.B) {
+	index := NewSearchIndex(benchmarkDocuments())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.Search(`"saving throw" dexterity`, 20)
+	}
+}
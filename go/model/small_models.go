@@ -1,19 +1,43 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+const (
+	LairInitiative = 20
+	lairSuffix     = " (Lair)"
+)
+
+var initiativeColumns = []string{"Name", "Initiative", "Damage", "Creature", "AC", "HP", "Bonus", "Legendary", "Resources", "Conditions"}
+
 type InitiativeTracker struct {
 	Combatants []Combatant `json:"combatants"`
 	Round      int         `json:"round"`
+	Turn       int         `json:"turn"`
+	// ExtraColumns holds hand-added table columns so they survive a save.
+	ExtraColumns []string `json:"extraColumns,omitempty"`
 }
 
 type Combatant struct {
-	Name       string `json:"name"`
-	Initiative int    `json:"initiative"`
-	Damage     int    `json:"damage"`
+	Name                string            `json:"name"`
+	Initiative          int               `json:"initiative"`
+	Damage              int               `json:"damage"`
+	Reference           string            `json:"reference,omitempty"`
+	ArmorClass          int               `json:"armorClass,omitempty"`
+	HitPoints           int               `json:"hitPoints,omitempty"`
+	InitiativeBonus     int               `json:"initiativeBonus,omitempty"`
+	Actions             []string          `json:"actions,omitempty"`
+	Lair                bool              `json:"lair,omitempty"`
+	LegendaryActions    int               `json:"legendaryActions,omitempty"`
+	LegendaryActionsMax int               `json:"legendaryActionsMax,omitempty"`
+	Resources           []Resource        `json:"resources,omitempty"`
+	Conditions          []string          `json:"conditions,omitempty"`
+	Extra               map[string]string `json:"extra,omitempty"`
 }
 
 type Action struct {
@@ -35,44 +59,350 @@ func (it *InitiativeTracker) FromMarkdown(content string) error {
 					it.Round = round
 				}
 			}
+			if strings.HasPrefix(line, "Turn: ") {
+				turn, err := strconv.Atoi(strings.TrimPrefix(line, "Turn: "))
+				if err == nil && turn > 0 {
+					it.Turn = turn - 1
+				}
+			}
 		}
 	}
 
-	startIndex := -1
+	for _, table := range ParseTables(content) {
+		if !table.HasColumns("Name", "Initiative") {
+			continue
+		}
 
-	for i, line := range lines {
-		if strings.Contains(line, "| Name | Initiative | Damage |") && i+1 < len(lines) && strings.Contains(lines[i+1], "|---|---|---") {
-			startIndex = i + 2
-			break
+		it.ExtraColumns = nil
+		for _, column := range table.Header {
+			if !isInitiativeColumn(column) {
+				it.ExtraColumns = append(it.ExtraColumns, column)
+			}
 		}
-	}
 
-	if startIndex != -1 {
-		for i := startIndex; i < len(lines); i++ {
-			line := strings.TrimSpace(lines[i])
-			if strings.HasPrefix(line, "|") && strings.HasSuffix(line, "|") {
-				parts := strings.Split(line, "|")
-				var filteredParts []string
-				for _, part := range parts {
-					if strings.TrimSpace(part) != "" {
-						filteredParts = append(filteredParts, strings.TrimSpace(part))
-					}
-				}
+		for i := range table.Rows {
+			name := table.Get(i, "Name")
+			if name == "" {
+				continue
+			}
 
-				if len(filteredParts) >= 3 {
-					name := filteredParts[0]
-					initiative, err1 := strconv.Atoi(filteredParts[1])
-					damage, err2 := strconv.Atoi(filteredParts[2])
+			initiative, err1 := strconv.Atoi(table.Get(i, "Initiative"))
+			damage, err2 := strconv.Atoi(table.Get(i, "Damage"))
+			if err1 != nil || (err2 != nil && table.Get(i, "Damage") != "") {
+				continue
+			}
 
-					if err1 == nil && err2 == nil {
-						it.Combatants = append(it.Combatants, Combatant{Name: name, Initiative: initiative, Damage: damage})
+			combatant := Combatant{Name: name, Initiative: initiative, Damage: damage}
+			combatant.Lair = strings.HasSuffix(combatant.Name, lairSuffix)
+			combatant.Reference = table.Get(i, "Creature")
+			combatant.ArmorClass, _ = strconv.Atoi(table.Get(i, "AC"))
+			combatant.HitPoints, _ = strconv.Atoi(table.Get(i, "HP"))
+			combatant.InitiativeBonus, _ = strconv.Atoi(table.Get(i, "Bonus"))
+			if legendary := table.Get(i, "Legendary"); legendary != "" {
+				fmt.Sscanf(legendary, "%d/%d", &combatant.LegendaryActions, &combatant.LegendaryActionsMax)
+			}
+			if resources := table.Get(i, "Resources"); resources != "" {
+				combatant.Resources = parseResources(resources)
+			}
+			combatant.Conditions = splitList(table.Get(i, "Conditions"))
+			for _, column := range it.ExtraColumns {
+				if value := table.Get(i, column); value != "" {
+					if combatant.Extra == nil {
+						combatant.Extra = map[string]string{}
 					}
+					combatant.Extra[column] = value
 				}
-			} else {
-				break
 			}
+			it.Combatants = append(it.Combatants, combatant)
 		}
+		break
 	}
 
 	return nil
 }
+
+func isInitiativeColumn(column string) bool {
+	for _, known := range initiativeColumns {
+		if strings.EqualFold(column, known) {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *InitiativeTracker) ToMarkdown() (string, error) {
+	// Sort a copy; Sort builds a new slice, so the receiver is left as it is.
+	sorted := *it
+	sorted.Sort()
+	it = &sorted
+
+	hasReference := false
+	hasStats := false
+	hasBonus := false
+	hasLegendary := false
+	hasResources := false
+	hasConditions := false
+	for _, c := range it.Combatants {
+		if c.Reference != "" {
+			hasReference = true
+		}
+		if c.ArmorClass > 0 || c.HitPoints > 0 {
+			hasStats = true
+		}
+		if c.InitiativeBonus != 0 {
+			hasBonus = true
+		}
+		if c.LegendaryActionsMax > 0 {
+			hasLegendary = true
+		}
+		if len(c.Resources) > 0 {
+			hasResources = true
+		}
+		if len(c.Conditions) > 0 {
+			hasConditions = true
+		}
+	}
+
+	table := Table{Header: []string{"Name", "Initiative", "Damage"}}
+	if hasReference {
+		table.Header = append(table.Header, "Creature")
+	}
+	if hasStats {
+		table.Header = append(table.Header, "AC", "HP")
+	}
+	if hasBonus {
+		table.Header = append(table.Header, "Bonus")
+	}
+	if hasLegendary {
+		table.Header = append(table.Header, "Legendary")
+	}
+	if hasResources {
+		table.Header = append(table.Header, "Resources")
+	}
+	if hasConditions {
+		table.Header = append(table.Header, "Conditions")
+	}
+	table.Header = append(table.Header, it.ExtraColumns...)
+
+	for _, c := range it.Combatants {
+		row := []string{c.Name, strconv.Itoa(c.Initiative), strconv.Itoa(c.Damage)}
+		if hasReference {
+			row = append(row, c.Reference)
+		}
+		if hasStats {
+			row = append(row, optionalInt(c.ArmorClass), optionalInt(c.HitPoints))
+		}
+		if hasBonus {
+			bonus := ""
+			if c.InitiativeBonus != 0 {
+				bonus = fmt.Sprintf("%+d", c.InitiativeBonus)
+			}
+			row = append(row, bonus)
+		}
+		if hasLegendary {
+			legendary := ""
+			if c.LegendaryActionsMax > 0 {
+				legendary = fmt.Sprintf("%d/%d", c.LegendaryActions, c.LegendaryActionsMax)
+			}
+			row = append(row, legendary)
+		}
+		if hasResources {
+			row = append(row, formatResources(c.Resources))
+		}
+		if hasConditions {
+			row = append(row, strings.Join(c.Conditions, ", "))
+		}
+		for _, column := range it.ExtraColumns {
+			row = append(row, c.Extra[column])
+		}
+		table.AddRow(row...)
+	}
+
+	md := fmt.Sprintf("Round: %d\n", it.Round)
+	if it.Turn > 0 {
+		md += fmt.Sprintf("Turn: %d\n", it.Turn+1)
+	}
+	md += table.String()
+
+	return strings.TrimSpace(md), nil
+}
+
+// Sort orders combatants by initiative, highest first. Lair turns lose
+// initiative ties, as described in the Monster Manual. Once combat is under
+// way (past round 1 or the first turn), the current turn stays with the same
+// combatant; before that a newcomer can still take the top of the order.
+func (it *InitiativeTracker) Sort() {
+	order := make([]int, len(it.Combatants))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := it.Combatants[order[i]], it.Combatants[order[j]]
+		if a.Initiative != b.Initiative {
+			return a.Initiative > b.Initiative
+		}
+		return !a.Lair && b.Lair
+	})
+
+	started := it.Round > 1 || it.Turn > 0
+	sorted := make([]Combatant, len(it.Combatants))
+	turn := it.Turn
+	for i, from := range order {
+		sorted[i] = it.Combatants[from]
+		if started && from == it.Turn {
+			turn = i
+		}
+	}
+	it.Combatants = sorted
+	it.Turn = turn
+}
+
+// AddCreature adds a creature to the tracker with its legendary action
+// budget, and inserts a lair turn at initiative count 20 if it has lair actions.
+func (it *InitiativeTracker) AddCreature(creature Creature, name string, initiative int) {
+	it.Combatants = append(it.Combatants, NewCombatant(creature, name, initiative))
+	it.addLair(creature)
+	it.Sort()
+}
+
+func (it *InitiativeTracker) addLair(creature Creature) {
+	if len(creature.LairActions) == 0 {
+		return
+	}
+	lairName := creature.Name + lairSuffix
+	for _, c := range it.Combatants {
+		if c.Name == lairName {
+			return
+		}
+	}
+	it.Combatants = append(it.Combatants, Combatant{Name: lairName, Initiative: LairInitiative, Lair: true})
+}
+
+func NewCombatant(creature Creature, name string, initiative int) Combatant {
+	if name == "" {
+		name = creature.Name
+	}
+	combatant := Combatant{
+		Name:            name,
+		Initiative:      initiative,
+		ArmorClass:      creature.ArmorClass,
+		InitiativeBonus: modifier(creature.AbilityScores.Dexterity),
+	}
+	combatant.HitPoints, _, _ = ParseHitPoints(creature.HitPoints)
+	for _, a := range creature.Actions {
+		combatant.Actions = append(combatant.Actions, a.Name)
+	}
+	if resources := ResourcesFromCreature(creature); len(resources) > 0 {
+		combatant.Resources = resources
+	}
+	combatant.setLegendaryActions(creature)
+	return combatant
+}
+
+// setLegendaryActions gives the combatant a full budget of the creature's
+// legendary actions, three unless the stat block says otherwise.
+func (c *Combatant) setLegendaryActions(creature Creature) {
+	if len(creature.LegendaryActions) == 0 {
+		return
+	}
+	c.LegendaryActionsMax = creature.LegendaryActionCount
+	if c.LegendaryActionsMax == 0 {
+		c.LegendaryActionsMax = 3
+	}
+	c.LegendaryActions = c.LegendaryActionsMax
+}
+
+// NextTurn advances to the next combatant, starting a new round after the
+// last one. The combatant whose turn begins regains its legendary actions and
+// rolls to recharge any spent recharge abilities.
+func (it *InitiativeTracker) NextTurn(roll Roller) []RechargeRoll {
+	if len(it.Combatants) == 0 {
+		return nil
+	}
+	if it.Round == 0 {
+		it.Round = 1
+	}
+
+	it.Turn++
+	if it.Turn >= len(it.Combatants) {
+		it.Turn = 0
+		it.Round++
+	}
+
+	current := &it.Combatants[it.Turn]
+	current.LegendaryActions = current.LegendaryActionsMax
+	return current.RollRecharges(roll)
+}
+
+// NextRound skips to the top of the next round, beginning each remaining turn
+// on the way so legendary actions and recharges are not missed.
+func (it *InitiativeTracker) NextRound(roll Roller) []RechargeRoll {
+	if len(it.Combatants) == 0 {
+		it.Round++
+		return nil
+	}
+	var rolls []RechargeRoll
+	for {
+		rolls = append(rolls, it.NextTurn(roll)...)
+		if it.Turn == 0 {
+			return rolls
+		}
+	}
+}
+
+// RemoveCombatant takes a combatant out of the order, keeping the current
+// turn with the same combatant, or passing it to the next one if the current
+// combatant is the one removed.
+func (it *InitiativeTracker) RemoveCombatant(index int) error {
+	if index < 0 || index >= len(it.Combatants) {
+		return fmt.Errorf("no combatant at index %d", index)
+	}
+	it.Combatants = append(it.Combatants[:index:index], it.Combatants[index+1:]...)
+	if index < it.Turn {
+		it.Turn--
+	}
+	if it.Turn >= len(it.Combatants) {
+		it.Turn = 0
+	}
+	return nil
+}
+
+func (it *InitiativeTracker) SpendLegendaryAction(index int, cost int) error {
+	if index < 0 || index >= len(it.Combatants) {
+		return fmt.Errorf("no combatant at index %d", index)
+	}
+	combatant := &it.Combatants[index]
+	if combatant.LegendaryActions < cost {
+		return errors.New(combatant.Name + " does not have enough legendary actions remaining")
+	}
+	combatant.LegendaryActions -= cost
+	return nil
+}
+
+// AddCondition applies a condition such as "Grappled" to the combatant,
+// unless it already has it.
+func (c *Combatant) AddCondition(condition string) {
+	condition = strings.TrimSpace(condition)
+	for _, existing := range c.Conditions {
+		if strings.EqualFold(existing, condition) {
+			return
+		}
+	}
+	c.Conditions = append(c.Conditions, condition)
+}
+
+func (c *Combatant) RemoveCondition(condition string) {
+	for i, existing := range c.Conditions {
+		if strings.EqualFold(existing, strings.TrimSpace(condition)) {
+			c.Conditions = append(c.Conditions[:i], c.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
+func optionalInt(value int) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
@@ -54,6 +54,43 @@ Round: 1
 				},
 			},
 		},
+		{
+			name: "Legendary and lair combatants",
+			markdown: `Round: 2
+Turn: 2
+| Name | Initiative | Damage | Legendary |
+|---|---|---|---|
+| Adult Red Dragon | 21 | 40 | 1/3 |
+| Adult Red Dragon (Lair) | 20 | 0 | |
+| Player 1 | 12 | 0 | |
+`,
+			expected: InitiativeTracker{
+				Round: 2,
+				Turn:  1,
+				Combatants: []Combatant{
+					{Name: "Adult Red Dragon", Initiative: 21, Damage: 40, LegendaryActions: 1, LegendaryActionsMax: 3},
+					{Name: "Adult Red Dragon (Lair)", Initiative: 20, Damage: 0, Lair: true},
+					{Name: "Player 1", Initiative: 12, Damage: 0},
+				},
+			},
+		},
+		{
+			name: "Hand edited table",
+			markdown: `Round: 2
+|Initiative| Name |  Damage | Concentrating |
+|:---:|:--|--:|---|
+| 18 | Bob \| the Brave | 3 | Bless |
+|12|Goblin|0||
+`,
+			expected: InitiativeTracker{
+				Round:        2,
+				ExtraColumns: []string{"Concentrating"},
+				Combatants: []Combatant{
+					{Name: "Bob | the Brave", Initiative: 18, Damage: 3, Extra: map[string]string{"Concentrating": "Bless"}},
+					{Name: "Goblin", Initiative: 12, Damage: 0},
+				},
+			},
+		},
 		{
 			name:     "Malformed table",
 			markdown: "Round: 1\n| Name | Initiative |\n|---|---|",
@@ -78,3 +115,200 @@ Round: 1
 		})
 	}
 }
+
+func TestInitiativeTrackerToMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		tracker  InitiativeTracker
+		expected string
+	}{
+		{
+			name: "Sorted by initiative",
+			tracker: InitiativeTracker{
+				Round: 3,
+				Turn:  1,
+				Combatants: []Combatant{
+					{Name: "Monster 1", Initiative: 10, Damage: 0},
+					{Name: "Player 1", Initiative: 20, Damage: 10},
+				},
+			},
+			expected: "Round: 3\n| Name | Initiative | Damage |\n| --- | --- | --- |\n| Player 1 | 20 | 10 |\n| Monster 1 | 10 | 0 |",
+		},
+		{
+			name: "Legendary column and lair losing ties",
+			tracker: InitiativeTracker{
+				Round: 1,
+				Combatants: []Combatant{
+					{Name: "Dragon (Lair)", Initiative: 20, Lair: true},
+					{Name: "Player 1", Initiative: 20},
+					{Name: "Dragon", Initiative: 15, LegendaryActions: 2, LegendaryActionsMax: 3},
+				},
+			},
+			expected: "Round: 1\n| Name | Initiative | Damage | Legendary |\n| --- | --- | --- | --- |\n| Player 1 | 20 | 0 |  |\n| Dragon (Lair) | 20 | 0 |  |\n| Dragon | 15 | 0 | 2/3 |",
+		},
+		{
+			name: "Conditions column",
+			tracker: InitiativeTracker{
+				Round: 2,
+				Combatants: []Combatant{
+					{Name: "Player 1", Initiative: 18, Conditions: []string{"Grappled", "Poisoned"}},
+					{Name: "Goblin", Initiative: 12, Damage: 4},
+				},
+			},
+			expected: "Round: 2\n| Name | Initiative | Damage | Conditions |\n| --- | --- | --- | --- |\n| Player 1 | 18 | 0 | Grappled, Poisoned |\n| Goblin | 12 | 4 |  |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsorted := append([]Combatant{}, tt.tracker.Combatants...)
+			markdown, err := tt.tracker.ToMarkdown()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, markdown)
+			assert.Equal(t, unsorted, tt.tracker.Combatants)
+
+			tt.tracker.Sort()
+			var parsed InitiativeTracker
+			assert.NoError(t, parsed.FromMarkdown(markdown))
+			assert.Equal(t, tt.tracker.Combatants, parsed.Combatants)
+		})
+	}
+}
+
+func TestInitiativeTrackerSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		round    int
+		turn     int
+		expected string
+	}{
+		{"before combat the newcomer goes first", 1, 0, "Bugbear"},
+		{"later rounds keep the top of the order", 2, 0, "Goblin"},
+		{"later turns keep the current combatant", 1, 1, "Wolf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := InitiativeTracker{Round: tt.round, Turn: tt.turn, Combatants: []Combatant{
+				{Name: "Goblin", Initiative: 15},
+				{Name: "Wolf", Initiative: 10},
+			}}
+			it.Combatants = append(it.Combatants, Combatant{Name: "Bugbear", Initiative: 20})
+			it.Sort()
+			assert.Equal(t, tt.expected, it.Combatants[it.Turn].Name)
+		})
+	}
+}
+
+func TestInitiativeTrackerPreservesExtraColumns(t *testing.T) {
+	markdown := "Round: 1\n| Name | Initiative | Damage | Concentrating |\n| --- | --- | --- | --- |\n| Bob \\| the Brave | 18 | 3 | Bless |\n| Goblin | 12 | 0 |  |"
+
+	var it InitiativeTracker
+	assert.NoError(t, it.FromMarkdown(markdown))
+	output, err := it.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, markdown, output)
+}
+
+func TestInitiativeTrackerLegendaryAndLairTurns(t *testing.T) {
+	dragon := Creature{
+		Name:                 "Adult Red Dragon",
+		LegendaryActions:     []Action{{Name: "Tail Attack", Description: "The dragon makes a tail attack."}},
+		LegendaryActionCount: 3,
+		LairActions:          []Action{{Name: "Magma", Description: "Magma erupts from a point on the ground."}},
+	}
+
+	it := InitiativeTracker{Round: 1}
+	it.Combatants = append(it.Combatants, Combatant{Name: "Player 1", Initiative: 22})
+	it.AddCreature(dragon, "", 12)
+
+	assert.Equal(t, []string{"Player 1", "Adult Red Dragon (Lair)", "Adult Red Dragon"}, combatantNames(it.Combatants))
+	assert.True(t, it.Combatants[1].Lair)
+	assert.Equal(t, LairInitiative, it.Combatants[1].Initiative)
+	assert.Equal(t, 3, it.Combatants[2].LegendaryActionsMax)
+
+	assert.NoError(t, it.SpendLegendaryAction(2, 2))
+	assert.Error(t, it.SpendLegendaryAction(2, 2))
+	assert.Equal(t, 1, it.Combatants[2].LegendaryActions)
+
+	it.NextTurn(nil)
+	assert.Equal(t, 1, it.Turn)
+	assert.Equal(t, 1, it.Combatants[2].LegendaryActions)
+
+	it.NextTurn(nil)
+	assert.Equal(t, 2, it.Turn)
+	assert.Equal(t, 3, it.Combatants[2].LegendaryActions)
+
+	it.NextTurn(nil)
+	assert.Equal(t, 0, it.Turn)
+	assert.Equal(t, 2, it.Round)
+
+	it.AddCreature(dragon, "Adult Red Dragon 2", 5)
+	assert.Len(t, it.Combatants, 4)
+}
+
+func TestInitiativeTrackerNextRound(t *testing.T) {
+	it := InitiativeTracker{Round: 2, Combatants: []Combatant{
+		{Name: "Player 1", Initiative: 22},
+		{Name: "Dragon", Initiative: 12, LegendaryActionsMax: 3},
+		{Name: "Goblin", Initiative: 8, Resources: []Resource{{Name: "Breath", Kind: ResourceRecharge, Max: 1, RechargeOn: 5}}},
+	}}
+
+	rolls := it.NextRound(func(sides int) int { return 6 })
+	assert.Equal(t, 3, it.Round)
+	assert.Equal(t, 0, it.Turn)
+	assert.Equal(t, 3, it.Combatants[1].LegendaryActions)
+	assert.Equal(t, []RechargeRoll{{Combatant: "Goblin", Resource: "Breath", Roll: 6, Recharged: true}}, rolls)
+
+	empty := InitiativeTracker{Round: 1}
+	assert.Empty(t, empty.NextRound(nil))
+	assert.Equal(t, 2, empty.Round)
+}
+
+func TestInitiativeTrackerRemoveCombatant(t *testing.T) {
+	tests := []struct {
+		name     string
+		turn     int
+		remove   int
+		expected string
+	}{
+		{"before the current turn", 2, 0, "Goblin"},
+		{"after the current turn", 1, 2, "Wolf"},
+		{"the current combatant", 1, 1, "Goblin"},
+		{"the last combatant on its turn", 2, 2, "Player 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := InitiativeTracker{Round: 2, Turn: tt.turn, Combatants: []Combatant{
+				{Name: "Player 1", Initiative: 20},
+				{Name: "Wolf", Initiative: 15},
+				{Name: "Goblin", Initiative: 10},
+			}}
+			assert.NoError(t, it.RemoveCombatant(tt.remove))
+			assert.Len(t, it.Combatants, 2)
+			assert.Equal(t, tt.expected, it.Combatants[it.Turn].Name)
+		})
+	}
+
+	it := InitiativeTracker{}
+	assert.Error(t, it.RemoveCombatant(0))
+}
+
+func combatantNames(combatants []Combatant) []string {
+	names := []string{}
+	for _, c := range combatants {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestCombatantConditions(t *testing.T) {
+	var combatant Combatant
+	combatant.AddCondition("Grappled")
+	combatant.AddCondition(" Prone ")
+	combatant.AddCondition("grappled")
+	assert.Equal(t, []string{"Grappled", "Prone"}, combatant.Conditions)
+
+	combatant.RemoveCondition("GRAPPLED")
+	combatant.RemoveCondition("Stunned")
+	assert.Equal(t, []string{"Prone"}, combatant.Conditions)
+}
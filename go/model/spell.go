@@ -0,0 +1,232 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Spell is a spell as it appears on a spell card.
+type Spell struct {
+	Name          string   `json:"name"`
+	Level         int      `json:"level"`
+	School        string   `json:"school"`
+	CastingTime   string   `json:"castingTime"`
+	Range         string   `json:"range"`
+	Components    []string `json:"components"`
+	Material      string   `json:"material,omitempty"`
+	Duration      string   `json:"duration"`
+	Concentration bool     `json:"concentration,omitempty"`
+	Ritual        bool     `json:"ritual,omitempty"`
+	Classes       []string `json:"classes,omitempty"`
+	Description   string   `json:"description"`
+	HigherLevels  string   `json:"higherLevels,omitempty"`
+}
+
+// SpellFilter narrows a spell search. Empty fields match every spell; Level
+// is a pointer because 0 is a cantrip.
+type SpellFilter struct {
+	Query  string `json:"query,omitempty"`
+	Class  string `json:"class,omitempty"`
+	Level  *int   `json:"level,omitempty"`
+	School string `json:"school,omitempty"`
+}
+
+// SpellIndex holds spells ordered by level and name for searching.
+type SpellIndex struct {
+	spells []Spell
+}
+
+var (
+	spellLevelLineRegex  = regexp.MustCompile(`^\*(?:(\d)(?:st|nd|rd|th)-level (\w+)|(\w+) cantrip)( \(ritual\))?\*$`)
+	spellComponentsRegex = regexp.MustCompile(`^([VSM, ]+?)(?: \((.+)\))?$`)
+	higherLevelsRegex    = regexp.MustCompile(`^\*\*\*At Higher Levels\.\*\*\* `)
+)
+
+func NewSpellIndex(spells []Spell) *SpellIndex {
+	index := &SpellIndex{spells: append([]Spell{}, spells...)}
+	sort.SliceStable(index.spells, func(i, j int) bool {
+		if index.spells[i].Level != index.spells[j].Level {
+			return index.spells[i].Level < index.spells[j].Level
+		}
+		return index.spells[i].Name < index.spells[j].Name
+	})
+	return index
+}
+
+// Find returns the spell with the given name, ignoring case.
+func (index *SpellIndex) Find(name string) (Spell, bool) {
+	for _, spell := range index.spells {
+		if strings.EqualFold(spell.Name, name) {
+			return spell, true
+		}
+	}
+	return Spell{}, false
+}
+
+// Search returns the spells that match the filter. A query matches the name
+// or the description; spells whose name matches come first.
+func (index *SpellIndex) Search(filter SpellFilter) []Spell {
+	query := strings.ToLower(strings.TrimSpace(filter.Query))
+	byName, byDescription := []Spell{}, []Spell{}
+	for _, spell := range index.spells {
+		if !spell.matches(filter) {
+			continue
+		}
+		switch {
+		case strings.Contains(strings.ToLower(spell.Name), query):
+			byName = append(byName, spell)
+		case strings.Contains(strings.ToLower(spell.Description), query):
+			byDescription = append(byDescription, spell)
+		}
+	}
+	return append(byName, byDescription...)
+}
+
+func (spell *Spell) matches(filter SpellFilter) bool {
+	if filter.Level != nil && spell.Level != *filter.Level {
+		return false
+	}
+	if filter.School != "" && !strings.EqualFold(spell.School, filter.School) {
+		return false
+	}
+	if filter.Class == "" {
+		return true
+	}
+	for _, class := range spell.Classes {
+		if strings.EqualFold(class, filter.Class) {
+			return true
+		}
+	}
+	return false
+}
+
+// LevelLine describes the spell's level and school, e.g. "3rd-level
+// evocation" or "Conjuration cantrip".
+func (spell *Spell) LevelLine() string {
+	line := fmt.Sprintf("%s %s", spellLevelName(spell.Level), strings.ToLower(spell.School))
+	if spell.Level == 0 {
+		line = fmt.Sprintf("%s cantrip", skillTitle(spell.School))
+	}
+	if spell.Ritual {
+		line += " (ritual)"
+	}
+	return line
+}
+
+func (spell *Spell) ToMarkdown() (string, error) {
+	md := fmt.Sprintf("### %s\n", spell.Name)
+	md += fmt.Sprintf("*%s*\n", spell.LevelLine())
+	md += "---\n"
+
+	properties := Table{Header: []string{"Property", "Value"}, Align: []Alignment{AlignLeft, AlignLeft}}
+	addRow := func(name, value string) {
+		if value != "" {
+			properties.AddRow(fmt.Sprintf("**%s**", name), value)
+		}
+	}
+	addRow("Casting Time", spell.CastingTime)
+	addRow("Range", spell.Range)
+	components := strings.Join(spell.Components, ", ")
+	if spell.Material != "" {
+		components += fmt.Sprintf(" (%s)", spell.Material)
+	}
+	addRow("Components", components)
+	duration := spell.Duration
+	if spell.Concentration {
+		duration = "Concentration, " + lowerFirst(duration)
+	}
+	addRow("Duration", duration)
+	addRow("Classes", strings.Join(spell.Classes, ", "))
+	md += properties.String() + "\n"
+	md += "---\n"
+
+	md += spell.Description + "\n"
+	if spell.HigherLevels != "" {
+		md += fmt.Sprintf("\n***At Higher Levels.*** %s\n", spell.HigherLevels)
+	}
+	return strings.TrimSpace(md), nil
+}
+
+func (spell *Spell) FromMarkdown(content string) error {
+	*spell = Spell{}
+	if content == "" {
+		return nil
+	}
+
+	var description []string
+	separators := 0
+	for _, line := range strings.Split(content, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmedLine, "### "):
+			spell.Name = strings.TrimSpace(strings.TrimPrefix(trimmedLine, "### "))
+		case trimmedLine == "---":
+			separators++
+		case separators == 0:
+			if match := spellLevelLineRegex.FindStringSubmatch(trimmedLine); len(match) > 4 {
+				if match[3] != "" {
+					spell.School = match[3]
+				} else {
+					spell.Level = int(match[1][0] - '0')
+					spell.School = skillTitle(match[2])
+				}
+				spell.Ritual = match[4] != ""
+			}
+		case separators >= 2 && higherLevelsRegex.MatchString(trimmedLine):
+			spell.HigherLevels = higherLevelsRegex.ReplaceAllString(trimmedLine, "")
+		case separators >= 2:
+			description = append(description, line)
+		}
+	}
+	spell.Description = strings.TrimSpace(strings.Join(description, "\n"))
+
+	for _, table := range ParseTables(content) {
+		if !table.HasColumns("Property", "Value") {
+			continue
+		}
+		for i := range table.Rows {
+			value := table.Get(i, "Value")
+			switch strings.Trim(table.Get(i, "Property"), "* ") {
+			case "Casting Time":
+				spell.CastingTime = value
+			case "Range":
+				spell.Range = value
+			case "Components":
+				if match := spellComponentsRegex.FindStringSubmatch(value); len(match) > 2 {
+					spell.Components = splitList(match[1])
+					spell.Material = match[2]
+				}
+			case "Duration":
+				if rest, ok := strings.CutPrefix(value, "Concentration, "); ok {
+					spell.Concentration = true
+					value = upperFirst(rest)
+				}
+				spell.Duration = value
+			case "Classes":
+				spell.Classes = splitList(value)
+			}
+		}
+	}
+	return nil
+}
+
+func lowerFirst(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToLower(text[:1]) + text[1:]
+}
+
+func upperFirst(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+// Document makes the spell searchable.
+func (spell *Spell) Document() Document {
+	return Document{Kind: "spell", Name: spell.Name, Text: strings.Join([]string{spell.LevelLine(), spell.Description, spell.HigherLevels}, "\n")}
+}
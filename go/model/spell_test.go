@@ -0,0 +1,94 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpellFromMarkdown(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "spells", "fireball.md"))
+	assert.NoError(t, err)
+
+	var spell Spell
+	assert.NoError(t, spell.FromMarkdown(string(content)))
+	assert.Equal(t, "Fireball", spell.Name)
+	assert.Equal(t, 3, spell.Level)
+	assert.Equal(t, "Evocation", spell.School)
+	assert.Equal(t, "1 action", spell.CastingTime)
+	assert.Equal(t, "150 feet", spell.Range)
+	assert.Equal(t, []string{"V", "S", "M"}, spell.Components)
+	assert.Equal(t, "a tiny ball of bat guano and sulfur", spell.Material)
+	assert.Equal(t, "Instantaneous", spell.Duration)
+	assert.False(t, spell.Concentration)
+	assert.Equal(t, []string{"Sorcerer", "Wizard"}, spell.Classes)
+	assert.Contains(t, spell.Description, "\n\nThe fire spreads around corners.")
+	assert.Equal(t, "When you cast this spell using a spell slot of 4th level or higher, the damage increases by 1d6 for each slot level above 3rd.", spell.HigherLevels)
+
+	markdown, err := spell.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, string(content), markdown+"\n")
+}
+
+func TestSpellMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		spell     Spell
+		levelLine string
+	}{
+		{"cantrip", Spell{Name: "Fire Bolt", School: "Evocation", CastingTime: "1 action", Range: "120 feet", Components: []string{"V", "S"}, Duration: "Instantaneous", Classes: []string{"Sorcerer", "Wizard"}, Description: "You hurl a mote of fire."}, "*Evocation cantrip*"},
+		{"ritual", Spell{Name: "Detect Magic", Level: 1, School: "Divination", CastingTime: "1 action", Range: "Self", Components: []string{"V", "S"}, Duration: "Up to 10 minutes", Concentration: true, Ritual: true, Description: "You sense magic."}, "*1st-level divination (ritual)*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markdown, err := tt.spell.ToMarkdown()
+			assert.NoError(t, err)
+			assert.Contains(t, markdown, tt.levelLine)
+
+			var parsed Spell
+			assert.NoError(t, parsed.FromMarkdown(markdown))
+			assert.Equal(t, tt.spell, parsed)
+		})
+	}
+}
+
+func TestSpellIndexSearch(t *testing.T) {
+	index := NewSpellIndex([]Spell{
+		{Name: "Fireball", Level: 3, School: "Evocation", Classes: []string{"Sorcerer", "Wizard"}, Description: "A bright streak of flame."},
+		{Name: "Cure Wounds", Level: 1, School: "Evocation", Classes: []string{"Bard", "Cleric", "Druid"}, Description: "A creature you touch regains hit points."},
+		{Name: "Fire Bolt", Level: 0, School: "Evocation", Classes: []string{"Sorcerer", "Wizard"}, Description: "You hurl a mote of fire."},
+		{Name: "Burning Hands", Level: 1, School: "Evocation", Classes: []string{"Sorcerer", "Wizard"}, Description: "A thin sheet of fire shoots forth."},
+		{Name: "Detect Magic", Level: 1, School: "Divination", Classes: []string{"Cleric", "Wizard"}, Description: "You sense magic."},
+	})
+	level := func(level int) *int { return &level }
+
+	tests := []struct {
+		name     string
+		filter   SpellFilter
+		expected []string
+	}{
+		{"everything", SpellFilter{}, []string{"Fire Bolt", "Burning Hands", "Cure Wounds", "Detect Magic", "Fireball"}},
+		{"name before description", SpellFilter{Query: "fire"}, []string{"Fire Bolt", "Fireball", "Burning Hands"}},
+		{"class", SpellFilter{Class: "cleric"}, []string{"Cure Wounds", "Detect Magic"}},
+		{"cantrips", SpellFilter{Level: level(0)}, []string{"Fire Bolt"}},
+		{"level and school", SpellFilter{Level: level(1), School: "evocation"}, []string{"Burning Hands", "Cure Wounds"}},
+		{"nothing", SpellFilter{Query: "wish"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := []string{}
+			for _, spell := range index.Search(tt.filter) {
+				names = append(names, spell.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	spell, ok := index.Find("FIREBALL")
+	assert.True(t, ok)
+	assert.Equal(t, 3, spell.Level)
+	_, ok = index.Find("Wish")
+	assert.False(t, ok)
+}
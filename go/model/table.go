@@ -0,0 +1,192 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+type Alignment int
+
+const (
+	AlignNone Alignment = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+// Table is a GitHub Flavored Markdown table. StartLine and EndLine record the
+// lines the table occupied in the content it was parsed from.
+type Table struct {
+	Header    []string
+	Align     []Alignment
+	Rows      [][]string
+	StartLine int
+	EndLine   int
+}
+
+var delimiterCellRegex = regexp.MustCompile(`^:?-+:?$`)
+
+// ParseTables finds every table in content. A table is a header row followed
+// by a delimiter row with the same number of cells; its body runs until a
+// blank line or a line without a pipe.
+func ParseTables(content string) []Table {
+	lines := strings.Split(content, "\n")
+	tables := []Table{}
+
+	for i := 0; i+1 < len(lines); i++ {
+		if !strings.Contains(lines[i], "|") {
+			continue
+		}
+		header := SplitTableRow(lines[i])
+		align, ok := parseDelimiterRow(lines[i+1])
+		if !ok || len(align) != len(header) {
+			continue
+		}
+
+		table := Table{Header: header, Align: align, StartLine: i}
+		j := i + 2
+		for ; j < len(lines); j++ {
+			line := strings.TrimSpace(lines[j])
+			if line == "" || !strings.Contains(line, "|") {
+				break
+			}
+			row := SplitTableRow(line)
+			for len(row) < len(header) {
+				row = append(row, "")
+			}
+			table.Rows = append(table.Rows, row)
+		}
+		table.EndLine = j - 1
+		tables = append(tables, table)
+		i = j - 1
+	}
+
+	return tables
+}
+
+// SplitTableRow splits a table row into trimmed cells. Leading and trailing
+// pipes are optional and escaped pipes (\|) stay part of the cell text.
+func SplitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	cells := []string{}
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cell.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(line[i])
+	}
+	cells = append(cells, strings.TrimSpace(cell.String()))
+	return cells
+}
+
+func parseDelimiterRow(line string) ([]Alignment, bool) {
+	if !strings.Contains(line, "-") {
+		return nil, false
+	}
+	cells := SplitTableRow(line)
+	align := make([]Alignment, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, " ", "")
+		if !delimiterCellRegex.MatchString(cell) {
+			return nil, false
+		}
+		left := strings.HasPrefix(cell, ":")
+		right := strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			align[i] = AlignCenter
+		case left:
+			align[i] = AlignLeft
+		case right:
+			align[i] = AlignRight
+		}
+	}
+	return align, true
+}
+
+// Column returns the index of the named column, ignoring case, or -1.
+func (t *Table) Column(name string) int {
+	for i, h := range t.Header {
+		if strings.EqualFold(h, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *Table) HasColumns(names ...string) bool {
+	for _, name := range names {
+		if t.Column(name) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Get returns the cell in the named column of a row, or "" if either is missing.
+func (t *Table) Get(row int, name string) string {
+	col := t.Column(name)
+	if row < 0 || row >= len(t.Rows) || col == -1 || col >= len(t.Rows[row]) {
+		return ""
+	}
+	return t.Rows[row][col]
+}
+
+func (t *Table) AddRow(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+func (t *Table) String() string {
+	var sb strings.Builder
+	writeTableRow(&sb, t.Header)
+
+	delimiters := make([]string, len(t.Header))
+	for i := range delimiters {
+		align := AlignNone
+		if i < len(t.Align) {
+			align = t.Align[i]
+		}
+		switch align {
+		case AlignLeft:
+			delimiters[i] = ":---"
+		case AlignCenter:
+			delimiters[i] = ":-:"
+		case AlignRight:
+			delimiters[i] = "---:"
+		default:
+			delimiters[i] = "---"
+		}
+	}
+	sb.WriteString("| " + strings.Join(delimiters, " | ") + " |\n")
+
+	for _, row := range t.Rows {
+		writeTableRow(&sb, row)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func writeTableRow(sb *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = EscapeTableCell(cell)
+	}
+	sb.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+}
+
+func EscapeTableCell(cell string) string {
+	cell = strings.ReplaceAll(cell, "\n", " ")
+	return strings.ReplaceAll(cell, "|", `\|`)
+}
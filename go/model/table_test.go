@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTables(t *testing.T) {
+	content := `Some text
+| Name | Value |
+| :--- | ---: |
+| a \| b | 1 |
+|  c  |
+not a table
+
+Left | Center
+:-: | :-:
+x | y
+`
+
+	tables := ParseTables(content)
+	assert.Len(t, tables, 2)
+
+	assert.Equal(t, []string{"Name", "Value"}, tables[0].Header)
+	assert.Equal(t, []Alignment{AlignLeft, AlignRight}, tables[0].Align)
+	assert.Equal(t, [][]string{{"a | b", "1"}, {"c", ""}}, tables[0].Rows)
+	assert.Equal(t, 1, tables[0].StartLine)
+	assert.Equal(t, 4, tables[0].EndLine)
+
+	assert.Equal(t, []string{"Left", "Center"}, tables[1].Header)
+	assert.Equal(t, []Alignment{AlignCenter, AlignCenter}, tables[1].Align)
+	assert.Equal(t, "y", tables[1].Get(0, "center"))
+	assert.Equal(t, "", tables[1].Get(0, "Missing"))
+	assert.Equal(t, "", tables[1].Get(3, "Left"))
+}
+
+func TestParseTablesRequiresMatchingDelimiterRow(t *testing.T) {
+	assert.Empty(t, ParseTables("| Name | Initiative | Damage |\n|---|---|\n| a | 1 | 2 |"))
+	assert.Empty(t, ParseTables("| Name | Initiative |\n| Bob | 12 |"))
+}
+
+func TestTableString(t *testing.T) {
+	table := Table{Header: []string{"Name", "Notes"}, Align: []Alignment{AlignNone, AlignCenter}}
+	table.AddRow("Bob | the Brave", "line one\nline two")
+	table.AddRow("Goblin", "")
+
+	expected := "| Name | Notes |\n| --- | :-: |\n| Bob \\| the Brave | line one line two |\n| Goblin |  |"
+	assert.Equal(t, expected, table.String())
+
+	parsed := ParseTables(table.String())
+	assert.Len(t, parsed, 1)
+	assert.Equal(t, "Bob | the Brave", parsed[0].Get(0, "Name"))
+}
@@ -0,0 +1,241 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CreatureTemplate turns a creature into a variant of itself, such as its
+// zombie or half-dragon version. "{name}" in the title and in added actions
+// is replaced with the creature's name.
+type CreatureTemplate struct {
+	Name  string `json:"name"`
+	Title string `json:"title,omitempty"`
+	// Description is noted in the result's Notes along with the template's
+	// name.
+	Description string `json:"description,omitempty"`
+	// Properties overrides stat block properties by name, e.g. "Armor Class"
+	// or "Speed". Size, Type, Species and Alignment set the type line.
+	Properties map[string]string `json:"properties,omitempty"`
+	// AbilityScores replaces ability scores and AbilityAdjustments adds to
+	// them. Both are keyed by the ability's full name, e.g. "strength".
+	AbilityScores         map[string]int `json:"abilityScores,omitempty"`
+	AbilityAdjustments    map[string]int `json:"abilityAdjustments,omitempty"`
+	DamageVulnerabilities []string       `json:"damageVulnerabilities,omitempty"`
+	DamageResistances     []string       `json:"damageResistances,omitempty"`
+	DamageImmunities      []string       `json:"damageImmunities,omitempty"`
+	ConditionImmunities   []string       `json:"conditionImmunities,omitempty"`
+	Senses                []string       `json:"senses,omitempty"`
+	Languages             []string       `json:"languages,omitempty"`
+	AddTraits             []Action       `json:"addTraits,omitempty"`
+	AddActions            []Action       `json:"addActions,omitempty"`
+	AddBonusActions       []Action       `json:"addBonusActions,omitempty"`
+	AddReactions          []Action       `json:"addReactions,omitempty"`
+	// Remove names traits and actions to drop from every section.
+	Remove []string `json:"remove,omitempty"`
+}
+
+//go:embed data/creature_templates.json
+var creatureTemplatesJSON []byte
+
+var creatureTemplates = mustParseCreatureTemplates(creatureTemplatesJSON)
+
+func mustParseCreatureTemplates(data []byte) []CreatureTemplate {
+	var templates []CreatureTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// CreatureTemplates lists the built-in templates.
+func CreatureTemplates() []CreatureTemplate {
+	return append([]CreatureTemplate{}, creatureTemplates...)
+}
+
+// FindCreatureTemplate looks up a built-in template by name, ignoring case.
+func FindCreatureTemplate(name string) (CreatureTemplate, bool) {
+	for _, template := range creatureTemplates {
+		if strings.EqualFold(template.Name, name) {
+			return template, true
+		}
+	}
+	return CreatureTemplate{}, false
+}
+
+// Apply returns a copy of the creature with the template applied. The
+// original creature and template are noted in Notes.
+func (template CreatureTemplate) Apply(creature Creature) (Creature, error) {
+	result := copyCreature(creature)
+	name := strings.ToLower(creature.Name)
+	if template.Title != "" {
+		result.Name = strings.ReplaceAll(template.Title, "{name}", creature.Name)
+	}
+
+	for property, value := range template.Properties {
+		switch property {
+		case "Size":
+			result.Size = value
+		case "Type":
+			result.Type = value
+		case "Species":
+			result.Species = value
+		case "Alignment":
+			result.Alignment = value
+		default:
+			if !result.setProperty(property, value) {
+				return Creature{}, fmt.Errorf("template %s sets unknown property %q", template.Name, property)
+			}
+		}
+	}
+
+	scores := map[string]*int{
+		"strength":     &result.AbilityScores.Strength,
+		"dexterity":    &result.AbilityScores.Dexterity,
+		"constitution": &result.AbilityScores.Constitution,
+		"intelligence": &result.AbilityScores.Intelligence,
+		"wisdom":       &result.AbilityScores.Wisdom,
+		"charisma":     &result.AbilityScores.Charisma,
+	}
+	for ability, value := range template.AbilityScores {
+		score, ok := scores[ability]
+		if !ok {
+			return Creature{}, fmt.Errorf("template %s sets unknown ability %q", template.Name, ability)
+		}
+		*score = value
+	}
+	for ability, adjustment := range template.AbilityAdjustments {
+		score, ok := scores[ability]
+		if !ok {
+			return Creature{}, fmt.Errorf("template %s adjusts unknown ability %q", template.Name, ability)
+		}
+		*score = min(max(*score+adjustment, 1), 30)
+	}
+
+	result.DamageVulnerabilities = addListItems(result.DamageVulnerabilities, template.DamageVulnerabilities)
+	result.DamageImmunities = addListItems(result.DamageImmunities, template.DamageImmunities)
+	// A creature immune to a damage type gains nothing from resisting it.
+	immunities := splitList(result.DamageImmunities)
+	var resistances []string
+	for _, resistance := range template.DamageResistances {
+		if !listed(immunities, resistance) {
+			resistances = append(resistances, resistance)
+		}
+	}
+	result.DamageResistances = addListItems(result.DamageResistances, resistances)
+	result.ConditionImmunities = addListItems(result.ConditionImmunities, template.ConditionImmunities)
+	result.Senses = addListItems(result.Senses, template.Senses)
+	result.Languages = addListItems(result.Languages, template.Languages)
+
+	for _, list := range result.actionLists() {
+		var kept []Action
+		for _, action := range *list.actions {
+			if !templateRemoves(template.Remove, action.Name) {
+				kept = append(kept, action)
+			}
+		}
+		*list.actions = kept
+	}
+	result.Traits = append(result.Traits, templateActions(template.AddTraits, name)...)
+	result.Actions = append(result.Actions, templateActions(template.AddActions, name)...)
+	result.BonusActions = append(result.BonusActions, templateActions(template.AddBonusActions, name)...)
+	result.Reactions = append(result.Reactions, templateActions(template.AddReactions, name)...)
+
+	provenance := fmt.Sprintf("%s template applied to %s.", template.Name, creature.Name)
+	if template.Description != "" {
+		provenance += " " + template.Description
+	}
+	if result.Notes != "" {
+		provenance = result.Notes + "\n\n" + provenance
+	}
+	result.Notes = provenance
+	return result, nil
+}
+
+// copyCreature copies the creature's action lists and extras so that the
+// copy can be changed without touching the original.
+func copyCreature(creature Creature) Creature {
+	result := creature
+	for _, list := range result.actionLists() {
+		*list.actions = append([]Action(nil), *list.actions...)
+	}
+	for _, name := range []string{"extraProperties", "extraSections"} {
+		if extras := creature.extras(name); extras != nil {
+			copied := map[string]Extension{}
+			for key, value := range extras {
+				copied[key] = value
+			}
+			result.setExtras(name, copied)
+		}
+	}
+	return result
+}
+
+// addListItems appends items to a comma separated stat block list. An item
+// whose first word is already listed, such as a second "darkvision", is
+// skipped, and passive Perception stays last. The list is left as written
+// when nothing is added.
+func addListItems(list string, items []string) string {
+	entries := splitList(list)
+	added := false
+	var passive string
+	if len(entries) > 0 && strings.HasPrefix(entries[len(entries)-1], "passive ") {
+		passive = entries[len(entries)-1]
+		entries = entries[:len(entries)-1]
+	}
+
+	for _, item := range items {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		if !listed(entries, item) {
+			entries = append(entries, item)
+			added = true
+		}
+	}
+
+	if !added {
+		return list
+	}
+	if passive != "" {
+		entries = append(entries, passive)
+	}
+	return strings.Join(entries, ", ")
+}
+
+// listed reports whether an entry in a split stat block list starts with the
+// same word as item.
+func listed(entries []string, item string) bool {
+	fields := strings.Fields(item)
+	if len(fields) == 0 {
+		return false
+	}
+	for _, entry := range entries {
+		if words := strings.Fields(entry); len(words) > 0 && strings.EqualFold(words[0], fields[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+func templateRemoves(names []string, action string) bool {
+	for _, name := range names {
+		if strings.EqualFold(name, action) {
+			return true
+		}
+	}
+	return false
+}
+
+func templateActions(actions []Action, name string) []Action {
+	var result []Action
+	for _, action := range actions {
+		result = append(result, Action{
+			Name:        strings.ReplaceAll(action.Name, "{name}", name),
+			Description: strings.ReplaceAll(action.Description, "{name}", name),
+		})
+	}
+	return result
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatureTemplateApply(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "statblocks", "goblin.md"))
+	assert.NoError(t, err)
+	var goblin Creature
+	assert.NoError(t, goblin.FromMarkdown(string(content)))
+
+	tests := []struct {
+		template string
+		expected func(*Creature)
+	}{
+		{
+			template: "Zombie",
+			expected: func(c *Creature) {
+				c.Name = "Goblin Zombie"
+				c.Type = "undead"
+				c.Species = ""
+				c.Alignment = "neutral evil"
+				c.Languages = "understands the languages it knew in life but can't speak"
+				c.AbilityScores.Dexterity = 12
+				c.AbilityScores.Constitution = 12
+				c.AbilityScores.Intelligence = 3
+				c.AbilityScores.Wisdom = 6
+				c.AbilityScores.Charisma = 5
+				c.DamageImmunities = "poison"
+				c.ConditionImmunities = "poisoned"
+				c.Traits = append(c.Traits, Action{
+					Name:        "Undead Fortitude",
+					Description: "If damage reduces the goblin to 0 hit points, it must make a Constitution saving throw with a DC of 5 + the damage taken, unless the damage is radiant or from a critical hit. On a success, the goblin drops to 1 hit point instead.",
+				})
+				c.Notes = "Zombie template applied to Goblin. A corpse raised to shambling unlife, taken from the SRD Zombie stat block."
+			},
+		},
+		{
+			template: "Half-Red Dragon",
+			expected: func(c *Creature) {
+				c.Name = "Half-Red Dragon Goblin"
+				c.DamageResistances = "fire"
+				c.Senses = "darkvision 60 ft., blindsight 10 ft., passive Perception 9"
+				c.Languages = "Common, Goblin, Draconic"
+				c.Actions = append(c.Actions, Action{
+					Name:        "Fire Breath (Recharge 5–6)",
+					Description: "The goblin exhales fire in a 15-foot cone. Each creature in that area must make a DC 15 Dexterity saving throw, taking 24 (7d6) fire damage on a failed save, or half as much damage on a successful one.",
+				})
+				c.Notes = "Half-Red Dragon template applied to Goblin. A creature with the blood of a red dragon, taken from the SRD Half-Red Dragon Veteran stat block."
+			},
+		},
+		{
+			template: "Veteran",
+			expected: func(c *Creature) {
+				c.Name = "Veteran Goblin"
+				c.ArmorClass = 17
+				c.ArmorType = "splint"
+				c.AbilityScores.Strength = 10
+				c.AbilityScores.Constitution = 12
+				c.Actions = append(c.Actions,
+					Action{
+						Name:        "Longsword",
+						Description: "*Melee Weapon Attack:* +5 to hit, reach 5 ft., one target. *Hit:* 7 (1d8 + 3) slashing damage, or 8 (1d10 + 3) slashing damage if used with two hands.",
+					},
+					Action{
+						Name:        "Heavy Crossbow",
+						Description: "*Ranged Weapon Attack:* +3 to hit, range 100/400 ft., one target. *Hit:* 6 (1d10 + 1) piercing damage.",
+					},
+				)
+				c.Notes = "Veteran template applied to Goblin. A seasoned fighter in heavy armor, taken from the SRD Veteran stat block."
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			template, ok := FindCreatureTemplate(tt.template)
+			assert.True(t, ok)
+
+			original := copyCreature(goblin)
+			expected := copyCreature(goblin)
+			tt.expected(&expected)
+
+			result, err := template.Apply(goblin)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result)
+			assert.Equal(t, original, goblin, "applying a template should not change the original")
+		})
+	}
+}
+
+func TestCreatureTemplateRemove(t *testing.T) {
+	creature := Creature{
+		Name:   "Lich",
+		Notes:  "Keeps a phylactery.",
+		Traits: []Action{{Name: "Spellcasting"}, {Name: "Turn Resistance"}},
+	}
+	template := CreatureTemplate{Name: "Husk", Remove: []string{"spellcasting"}}
+
+	result, err := template.Apply(creature)
+	assert.NoError(t, err)
+	assert.Equal(t, "Lich", result.Name)
+	assert.Equal(t, []Action{{Name: "Turn Resistance"}}, result.Traits)
+	assert.Equal(t, "Keeps a phylactery.\n\nHusk template applied to Lich.", result.Notes)
+}
+
+func TestCreatureTemplateSkipsImmuneResistances(t *testing.T) {
+	creature := Creature{Name: "Fire Elemental", DamageImmunities: "fire, poison"}
+	template := CreatureTemplate{Name: "Frostbound", DamageResistances: []string{"fire", "cold"}}
+
+	result, err := template.Apply(creature)
+	assert.NoError(t, err)
+	assert.Equal(t, "cold", result.DamageResistances)
+	assert.Equal(t, "fire, poison", result.DamageImmunities)
+}
+
+func TestCreatureTemplateErrors(t *testing.T) {
+	tests := []CreatureTemplate{
+		{Name: "Bad Property", Properties: map[string]string{"Habitat": "swamp"}},
+		{Name: "Bad Score", AbilityScores: map[string]int{"luck": 10}},
+		{Name: "Bad Adjustment", AbilityAdjustments: map[string]int{"str": 2}},
+	}
+	for _, template := range tests {
+		t.Run(template.Name, func(t *testing.T) {
+			_, err := template.Apply(Creature{Name: "Goblin"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBuiltInCreatureTemplates(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "statblocks", "*.md"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, CreatureTemplates())
+
+	for _, template := range CreatureTemplates() {
+		for _, input := range inputs {
+			t.Run(template.Name+"/"+filepath.Base(input), func(t *testing.T) {
+				content, err := os.ReadFile(input)
+				assert.NoError(t, err)
+				var creature Creature
+				assert.NoError(t, creature.FromMarkdown(string(content)))
+
+				result, err := template.Apply(creature)
+				assert.NoError(t, err)
+				markdown, err := result.ToMarkdown()
+				assert.NoError(t, err)
+				var reparsed Creature
+				assert.NoError(t, reparsed.FromMarkdown(markdown))
+				assert.Equal(t, result, reparsed)
+			})
+		}
+	}
+}
+
+func TestAddListItems(t *testing.T) {
+	tests := []struct {
+		list     string
+		items    []string
+		expected string
+	}{
+		{"", []string{"fire"}, "fire"},
+		{"cold", []string{"fire"}, "cold, fire"},
+		{"darkvision 120 ft., passive Perception 13", []string{"darkvision 60 ft.", "blindsight 10 ft."}, "darkvision 120 ft., blindsight 10 ft., passive Perception 13"},
+		{"bludgeoning, piercing, and slashing", []string{"piercing"}, "bludgeoning, piercing, and slashing"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, addListItems(tt.list, tt.items))
+	}
+}
@@ -0,0 +1,449 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type TreasureType string
+
+const (
+	// TreasureCoins is the individual treasure a single creature carries,
+	// which is all coins.
+	TreasureCoins      TreasureType = "coins"
+	TreasureHoard      TreasureType = "hoard"
+	TreasureGems       TreasureType = "gems"
+	TreasureArtObjects TreasureType = "artObjects"
+	TreasureMagicItems TreasureType = "magicItems"
+)
+
+// TreasureRequest says which treasure table to roll on.
+type TreasureRequest struct {
+	Type TreasureType `json:"type"`
+	// Challenge picks the challenge rating band of the coin and hoard
+	// tables, e.g. "7" rolls on the CR 5–10 tables.
+	Challenge string `json:"challenge,omitempty"`
+	// Value is what each gem or art object is worth in gold pieces.
+	Value int `json:"value,omitempty"`
+	// Table is the magic item table, A to I.
+	Table string `json:"table,omitempty"`
+	// Count is how many gems, art objects or magic items to roll, one if
+	// unset.
+	Count int `json:"count,omitempty"`
+}
+
+type Coins struct {
+	Copper   int `json:"cp,omitempty"`
+	Silver   int `json:"sp,omitempty"`
+	Electrum int `json:"ep,omitempty"`
+	Gold     int `json:"gp,omitempty"`
+	Platinum int `json:"pp,omitempty"`
+}
+
+// Valuable is a gem or art object and what it is worth in gold pieces.
+type Valuable struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// MagicItem is an item rolled on one of the magic item tables.
+type MagicItem struct {
+	Name  string `json:"name"`
+	Table string `json:"table"`
+}
+
+// Treasure is the loot from a roll on the treasure tables, or several rolls
+// added together.
+type Treasure struct {
+	Name       string      `json:"name,omitempty"`
+	Coins      Coins       `json:"coins"`
+	Gems       []Valuable  `json:"gems,omitempty"`
+	ArtObjects []Valuable  `json:"artObjects,omitempty"`
+	MagicItems []MagicItem `json:"magicItems,omitempty"`
+}
+
+type treasureCoins struct {
+	Dice       string `json:"dice"`
+	Multiplier int    `json:"multiplier"`
+	Unit       string `json:"unit"`
+}
+
+type treasureValuables struct {
+	Dice  string `json:"dice"`
+	Value int    `json:"value"`
+}
+
+type treasureItems struct {
+	Dice  string `json:"dice"`
+	Table string `json:"table"`
+}
+
+// treasureRow is a d100 result on a coin or hoard table.
+type treasureRow struct {
+	Min        int                `json:"min"`
+	Max        int                `json:"max"`
+	Coins      []treasureCoins    `json:"coins"`
+	Gems       *treasureValuables `json:"gems"`
+	ArtObjects *treasureValuables `json:"artObjects"`
+	MagicItems []treasureItems    `json:"magicItems"`
+}
+
+// treasureTable is the coin or hoard table for the challenge ratings from
+// MinChallenge up to the next table's.
+type treasureTable struct {
+	MinChallenge float64         `json:"minChallenge"`
+	Coins        []treasureCoins `json:"coins"`
+	Rows         []treasureRow   `json:"rows"`
+}
+
+type magicItemRow struct {
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+	Name string `json:"name"`
+}
+
+type treasureTables struct {
+	Individual []treasureTable           `json:"individual"`
+	Hoards     []treasureTable           `json:"hoards"`
+	Gems       map[int][]string          `json:"gems"`
+	ArtObjects map[int][]string          `json:"artObjects"`
+	MagicItems map[string][]magicItemRow `json:"magicItems"`
+}
+
+//go:embed data/treasure.json
+var treasureJSON []byte
+
+var defaultTreasureTables = mustParseTreasureTables(treasureJSON)
+
+func mustParseTreasureTables(data []byte) treasureTables {
+	var tables treasureTables
+	if err := json.Unmarshal(data, &tables); err != nil {
+		panic(err)
+	}
+	return tables
+}
+
+// RollTreasure rolls on the treasure table the request names.
+func RollTreasure(request TreasureRequest, roll Roller) (Treasure, error) {
+	if roll == nil {
+		roll = RollDie
+	}
+	count := request.Count
+	if count == 0 {
+		count = 1
+	}
+
+	var treasure Treasure
+	switch request.Type {
+	case TreasureCoins, TreasureHoard:
+		tables := defaultTreasureTables.Individual
+		if request.Type == TreasureHoard {
+			tables = defaultTreasureTables.Hoards
+		}
+		table, err := treasureTableFor(tables, request.Challenge)
+		if err != nil {
+			return treasure, err
+		}
+		return table.roll(roll)
+	case TreasureGems:
+		gems, err := rollValuables(defaultTreasureTables.Gems, "gems", request.Value, count, roll)
+		treasure.Gems = gems
+		return treasure, err
+	case TreasureArtObjects:
+		art, err := rollValuables(defaultTreasureTables.ArtObjects, "art objects", request.Value, count, roll)
+		treasure.ArtObjects = art
+		return treasure, err
+	case TreasureMagicItems:
+		for i := 0; i < count; i++ {
+			item, err := rollMagicItem(request.Table, roll)
+			if err != nil {
+				return treasure, err
+			}
+			treasure.MagicItems = append(treasure.MagicItems, item)
+		}
+		return treasure, nil
+	}
+	return treasure, fmt.Errorf("unknown treasure type %q", request.Type)
+}
+
+// RollLoot rolls the treasure for defeated creatures from their challenge
+// ratings: individual treasure for each, or a single hoard for the highest.
+// Creatures without a rating carry nothing.
+func RollLoot(challenges []string, hoard bool, roll Roller) (Treasure, error) {
+	loot := Treasure{Name: "Loot"}
+	highest := ""
+	best := -1.0
+	for _, challenge := range challenges {
+		value, ok := ChallengeRatingValue(challenge)
+		if !ok {
+			continue
+		}
+		if value > best {
+			highest, best = challenge, value
+		}
+		if hoard {
+			continue
+		}
+		treasure, err := RollTreasure(TreasureRequest{Type: TreasureCoins, Challenge: challenge}, roll)
+		if err != nil {
+			return loot, err
+		}
+		loot.Add(treasure)
+	}
+	if highest == "" {
+		return loot, errors.New("no defeated creature has a challenge rating")
+	}
+	if hoard {
+		treasure, err := RollTreasure(TreasureRequest{Type: TreasureHoard, Challenge: highest}, roll)
+		if err != nil {
+			return loot, err
+		}
+		loot.Add(treasure)
+	}
+	return loot, nil
+}
+
+// Defeated lists the combatants brought to 0 hit points.
+func (it *InitiativeTracker) Defeated() []Combatant {
+	defeated := []Combatant{}
+	for _, c := range it.Combatants {
+		if !c.Lair && c.HitPoints > 0 && c.Damage >= c.HitPoints {
+			defeated = append(defeated, c)
+		}
+	}
+	return defeated
+}
+
+// DefeatedChallenges lists the challenge rating of each defeated combatant
+// from its stat block, keyed by reference. Combatants without a stat block,
+// such as the party, are left out.
+func (it *InitiativeTracker) DefeatedChallenges(statBlocks map[string]string) []string {
+	challenges := []string{}
+	for _, c := range it.Defeated() {
+		statBlock, ok := statBlocks[c.Reference]
+		if !ok {
+			continue
+		}
+		var creature Creature
+		if creature.FromMarkdown(statBlock) == nil && creature.ChallengeRating != "" {
+			challenges = append(challenges, challengeRating(creature.ChallengeRating))
+		}
+	}
+	return challenges
+}
+
+func treasureTableFor(tables []treasureTable, challenge string) (treasureTable, error) {
+	value, ok := ChallengeRatingValue(challenge)
+	if !ok || len(tables) == 0 {
+		return treasureTable{}, fmt.Errorf("unknown challenge rating %q", challenge)
+	}
+	table := tables[0]
+	for _, t := range tables {
+		if value >= t.MinChallenge {
+			table = t
+		}
+	}
+	return table, nil
+}
+
+func (table treasureTable) roll(roll Roller) (Treasure, error) {
+	var treasure Treasure
+	treasure.Coins.add(table.Coins, roll)
+
+	d100 := roll(100)
+	for _, row := range table.Rows {
+		if d100 < row.Min || d100 > row.Max {
+			continue
+		}
+		treasure.Coins.add(row.Coins, roll)
+		if row.Gems != nil {
+			gems, err := rollValuables(defaultTreasureTables.Gems, "gems", row.Gems.Value, rollCount(row.Gems.Dice, roll), roll)
+			if err != nil {
+				return treasure, err
+			}
+			treasure.Gems = gems
+		}
+		if row.ArtObjects != nil {
+			art, err := rollValuables(defaultTreasureTables.ArtObjects, "art objects", row.ArtObjects.Value, rollCount(row.ArtObjects.Dice, roll), roll)
+			if err != nil {
+				return treasure, err
+			}
+			treasure.ArtObjects = art
+		}
+		for _, items := range row.MagicItems {
+			for i, count := 0, rollCount(items.Dice, roll); i < count; i++ {
+				item, err := rollMagicItem(items.Table, roll)
+				if err != nil {
+					return treasure, err
+				}
+				treasure.MagicItems = append(treasure.MagicItems, item)
+			}
+		}
+		break
+	}
+	return treasure, nil
+}
+
+// rollCount rolls how many of something there are, one if there are no dice.
+func rollCount(dice string, roll Roller) int {
+	if dice == "" {
+		return 1
+	}
+	d, err := ParseDice(dice)
+	if err != nil {
+		return 0
+	}
+	return d.Roll(roll)
+}
+
+func rollValuables(tables map[int][]string, kind string, value int, count int, roll Roller) ([]Valuable, error) {
+	names, ok := tables[value]
+	if !ok {
+		return nil, fmt.Errorf("no %s worth %d gp", kind, value)
+	}
+	valuables := []Valuable{}
+	for i := 0; i < count; i++ {
+		valuables = append(valuables, Valuable{Name: names[roll(len(names))-1], Value: value})
+	}
+	return valuables, nil
+}
+
+func rollMagicItem(table string, roll Roller) (MagicItem, error) {
+	table = strings.ToUpper(strings.TrimSpace(table))
+	rows, ok := defaultTreasureTables.MagicItems[table]
+	if !ok {
+		return MagicItem{}, fmt.Errorf("unknown magic item table %q", table)
+	}
+	d100 := roll(100)
+	for _, row := range rows {
+		if d100 >= row.Min && d100 <= row.Max {
+			return MagicItem{Name: row.Name, Table: table}, nil
+		}
+	}
+	return MagicItem{}, fmt.Errorf("no magic item for %d on table %s", d100, table)
+}
+
+func (coins *Coins) add(rolls []treasureCoins, roll Roller) {
+	for _, c := range rolls {
+		amount := rollCount(c.Dice, roll)
+		if c.Multiplier > 0 {
+			amount *= c.Multiplier
+		}
+		switch c.Unit {
+		case "cp":
+			coins.Copper += amount
+		case "sp":
+			coins.Silver += amount
+		case "ep":
+			coins.Electrum += amount
+		case "gp":
+			coins.Gold += amount
+		case "pp":
+			coins.Platinum += amount
+		}
+	}
+}
+
+// Value is what the coins are worth in gold pieces, rounded down.
+func (coins Coins) Value() int {
+	copper := coins.Copper + coins.Silver*10 + coins.Electrum*50 + coins.Gold*100 + coins.Platinum*1000
+	return copper / 100
+}
+
+func (coins Coins) String() string {
+	var parts []string
+	for _, c := range []struct {
+		amount int
+		unit   string
+	}{
+		{coins.Copper, "cp"},
+		{coins.Silver, "sp"},
+		{coins.Electrum, "ep"},
+		{coins.Gold, "gp"},
+		{coins.Platinum, "pp"},
+	} {
+		if c.amount > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", formatThousands(c.amount), c.unit))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Add puts other's loot in with the treasure's.
+func (treasure *Treasure) Add(other Treasure) {
+	treasure.Coins.Copper += other.Coins.Copper
+	treasure.Coins.Silver += other.Coins.Silver
+	treasure.Coins.Electrum += other.Coins.Electrum
+	treasure.Coins.Gold += other.Coins.Gold
+	treasure.Coins.Platinum += other.Coins.Platinum
+	treasure.Gems = append(treasure.Gems, other.Gems...)
+	treasure.ArtObjects = append(treasure.ArtObjects, other.ArtObjects...)
+	treasure.MagicItems = append(treasure.MagicItems, other.MagicItems...)
+}
+
+// Value is what the coins, gems and art objects are worth in gold pieces.
+// Magic items are not counted.
+func (treasure *Treasure) Value() int {
+	value := treasure.Coins.Value()
+	for _, valuable := range append(append([]Valuable{}, treasure.Gems...), treasure.ArtObjects...) {
+		value += valuable.Value
+	}
+	return value
+}
+
+// ToMarkdown writes the treasure as a loot block, with the same gems, art
+// objects and magic items counted together on one row.
+func (treasure *Treasure) ToMarkdown() (string, error) {
+	name := treasure.Name
+	if name == "" {
+		name = "Loot"
+	}
+	md := fmt.Sprintf("### %s\n", name)
+	if coins := treasure.Coins.String(); coins != "" {
+		md += fmt.Sprintf("**Coins:** %s\n", coins)
+	}
+
+	type lootRow struct {
+		item, kind   string
+		count, value int
+	}
+	var rows []*lootRow
+	byKey := map[lootRow]*lootRow{}
+	add := func(item string, kind string, value int) {
+		key := lootRow{item: item, kind: kind, value: value}
+		if row, ok := byKey[key]; ok {
+			row.count++
+			return
+		}
+		row := &lootRow{item: item, kind: kind, count: 1, value: value}
+		byKey[key] = row
+		rows = append(rows, row)
+	}
+	for _, gem := range treasure.Gems {
+		add(gem.Name, "Gem", gem.Value)
+	}
+	for _, art := range treasure.ArtObjects {
+		add(art.Name, "Art object", art.Value)
+	}
+	for _, item := range treasure.MagicItems {
+		add(item.Name, fmt.Sprintf("Magic item (%s)", item.Table), 0)
+	}
+
+	if len(rows) > 0 {
+		table := Table{Header: []string{"Item", "Type", "Count", "Value"}, Align: []Alignment{AlignLeft, AlignLeft, AlignCenter, AlignCenter}}
+		for _, row := range rows {
+			value := ""
+			if row.value > 0 {
+				value = formatThousands(row.value*row.count) + " gp"
+			}
+			table.AddRow(row.item, row.kind, strconv.Itoa(row.count), value)
+		}
+		md += "---\n" + table.String() + "\n---\n"
+	}
+	md += fmt.Sprintf("**Total Value:** %s gp", formatThousands(treasure.Value()))
+	return md, nil
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollTreasure(t *testing.T) {
+	highest := func(sides int) int { return sides }
+	lowest := func(sides int) int { return 1 }
+	zircons := make([]Valuable, 12)
+	for i := range zircons {
+		zircons[i] = Valuable{Name: "Zircon", Value: 50}
+	}
+
+	tests := []struct {
+		name     string
+		request  TreasureRequest
+		roll     Roller
+		expected Treasure
+	}{
+		{"coins for a low challenge", TreasureRequest{Type: TreasureCoins, Challenge: "1/4"}, highest, Treasure{Coins: Coins{Platinum: 6}}},
+		{"coins for CR 5-10", TreasureRequest{Type: TreasureCoins, Challenge: "7"}, highest, Treasure{Coins: Coins{Gold: 120, Platinum: 18}}},
+		{"coins for CR 17 and up", TreasureRequest{Type: TreasureCoins, Challenge: "30"}, highest, Treasure{Coins: Coins{Gold: 6000, Platinum: 1200}}},
+		{"coins with a low roll", TreasureRequest{Type: TreasureCoins, Challenge: "12 (8,400 XP)"}, lowest, Treasure{Coins: Coins{Silver: 400, Gold: 100}}},
+		{"hoard", TreasureRequest{Type: TreasureHoard, Challenge: "3"}, highest, Treasure{
+			Coins:      Coins{Copper: 3600, Silver: 1800, Gold: 120},
+			Gems:       zircons,
+			MagicItems: []MagicItem{{Name: "Wings of flying", Table: "G"}},
+		}},
+		{"hoard with nothing but coins", TreasureRequest{Type: TreasureHoard, Challenge: "11"}, lowest, Treasure{Coins: Coins{Gold: 4000, Platinum: 500}}},
+		{"gems", TreasureRequest{Type: TreasureGems, Value: 10, Count: 2}, lowest, Treasure{Gems: []Valuable{{"Azurite", 10}, {"Azurite", 10}}}},
+		{"art object", TreasureRequest{Type: TreasureArtObjects, Value: 7500}, highest, Treasure{ArtObjects: []Valuable{{"Bejeweled ivory drinking horn with gold filigree", 7500}}}},
+		{"magic item", TreasureRequest{Type: TreasureMagicItems, Table: "i"}, lowest, Treasure{MagicItems: []MagicItem{{Name: "Defender", Table: "I"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			treasure, err := RollTreasure(tt.request, tt.roll)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, treasure)
+		})
+	}
+}
+
+func TestRollTreasureErrors(t *testing.T) {
+	tests := []struct {
+		request  TreasureRequest
+		expected string
+	}{
+		{TreasureRequest{Type: "trinkets"}, `unknown treasure type "trinkets"`},
+		{TreasureRequest{Type: TreasureHoard}, `unknown challenge rating ""`},
+		{TreasureRequest{Type: TreasureGems, Value: 20}, "no gems worth 20 gp"},
+		{TreasureRequest{Type: TreasureArtObjects, Value: 100}, "no art objects worth 100 gp"},
+		{TreasureRequest{Type: TreasureMagicItems, Table: "J"}, `unknown magic item table "J"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			_, err := RollTreasure(tt.request, nil)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestRollTreasureSeeded(t *testing.T) {
+	request := TreasureRequest{Type: TreasureHoard, Challenge: "9"}
+	first, err := RollTreasure(request, SeededRoller(11))
+	assert.NoError(t, err)
+	second, err := RollTreasure(request, SeededRoller(11))
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestRollLoot(t *testing.T) {
+	highest := func(sides int) int { return sides }
+
+	loot, err := RollLoot([]string{"1/4", "1/4", "", "2"}, false, highest)
+	assert.NoError(t, err)
+	assert.Equal(t, Treasure{Name: "Loot", Coins: Coins{Platinum: 18}}, loot)
+
+	loot, err = RollLoot([]string{"1/4", "2", "1/2"}, true, highest)
+	assert.NoError(t, err)
+	assert.Equal(t, Coins{Copper: 3600, Silver: 1800, Gold: 120}, loot.Coins)
+	assert.Len(t, loot.Gems, 12)
+
+	_, err = RollLoot([]string{""}, false, highest)
+	assert.Error(t, err)
+}
+
+func TestInitiativeTrackerDefeated(t *testing.T) {
+	it := InitiativeTracker{Combatants: []Combatant{
+		{Name: "Goblin 1", Reference: "[[Goblin]]", Damage: 7, HitPoints: 7},
+		{Name: "Goblin 2", Reference: "[[Goblin]]", Damage: 2, HitPoints: 7},
+		{Name: "Bandit Captain", Reference: "[[Bandit Captain]]", Damage: 70, HitPoints: 65},
+		{Name: "Mira Thorne", Reference: "[[Mira Thorne]]", Damage: 31, HitPoints: 31},
+		{Name: "Zombie", Damage: 3},
+		{Name: "Dragon (Lair)", Initiative: LairInitiative, Lair: true},
+	}}
+
+	assert.Equal(t, []string{"Goblin 1", "Bandit Captain", "Mira Thorne"}, combatantNames(it.Defeated()))
+	assert.Equal(t, []string{"1/4", "2"}, it.DefeatedChallenges(readStatBlocks(t)))
+}
+
+func TestTreasureToMarkdown(t *testing.T) {
+	treasure := Treasure{
+		Name:       "Bandit Camp",
+		Coins:      Coins{Copper: 1200, Gold: 35},
+		Gems:       []Valuable{{"Azurite", 10}, {"Jasper", 50}, {"Azurite", 10}},
+		ArtObjects: []Valuable{{"Silver ewer", 25}},
+		MagicItems: []MagicItem{{Name: "Potion of healing", Table: "A"}},
+	}
+	md, err := treasure.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, `### Bandit Camp
+**Coins:** 1,200 cp, 35 gp
+---
+| Item | Type | Count | Value |
+| :--- | :--- | :-: | :-: |
+| Azurite | Gem | 2 | 20 gp |
+| Jasper | Gem | 1 | 50 gp |
+| Silver ewer | Art object | 1 | 25 gp |
+| Potion of healing | Magic item (A) | 1 |  |
+---
+**Total Value:** 142 gp`, md)
+	assert.Equal(t, 142, treasure.Value())
+
+	md, err = (&Treasure{Coins: Coins{Platinum: 6}}).ToMarkdown()
+	assert.NoError(t, err)
+	assert.Equal(t, "### Loot\n**Coins:** 6 pp\n**Total Value:** 60 gp", md)
+}
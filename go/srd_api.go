@@ -1,11 +1,13 @@
-
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"regexp"
 	"strings"
+
+	"github.com/shiftregister-vg/logseq-odyssey/go/model"
 )
 
 const apiBaseURL = "https://5e-bits.github.io/data"
@@ -19,56 +21,382 @@ type SRDAction struct {
 }
 
 type SRDMonster struct {
-	ID                   string      `json:"id"`
-	Name                 string      `json:"name"`
-	Size                 string      `json:"size"`
-	Type                 string      `json:"type"`
-	Subtype              string      `json:"subtype"`
-	Alignment            string      `json:"alignment"`
-	ArmorClass           int         `json:"armor_class"`
-	HitPoints            int         `json:"hit_points"`
-	HitDice              string      `json:"hit_dice"`
-	Speed                string      `json:"speed"`
-	Strength             int         `json:"strength"`
-	Dexterity            int         `json:"dexterity"`
-	Constitution         int         `json:"constitution"`
-	Intelligence         int         `json:"intelligence"`
-	Wisdom               int         `json:"wisdom"`
-	Charisma             int         `json:"charisma"`
-	Proficiencies        map[string]int `json:"proficiencies"`
-	DamageVulnerabilities string      `json:"damage_vulnerabilities"`
-	DamageResistances    string      `json:"damage_resistances"`
-	DamageImmunities     string      `json:"damage_immunities"`
-	ConditionImmunities  string      `json:"condition_immunities"`
-	Senses               string      `json:"senses"`
-	Languages            string      `json:"languages"`
-	ChallengeRating      float64     `json:"challenge_rating"`
-	SpecialAbilities     []SRDAction `json:"special_abilities"`
-	Actions              []SRDAction `json:"actions"`
-	LegendaryActions     []SRDAction `json:"legendary_actions"`
-}
-
-func fetchSRD[T any](endpoint string) ([]T, error) {
-	resp, err := http.Get(fmt.Sprintf("%s/%s.json", apiBaseURL, endpoint))
+	ID                    string         `json:"id"`
+	Name                  string         `json:"name"`
+	Size                  string         `json:"size"`
+	Type                  string         `json:"type"`
+	Subtype               string         `json:"subtype"`
+	Alignment             string         `json:"alignment"`
+	ArmorClass            int            `json:"armor_class"`
+	HitPoints             int            `json:"hit_points"`
+	HitDice               string         `json:"hit_dice"`
+	Speed                 string         `json:"speed"`
+	Strength              int            `json:"strength"`
+	Dexterity             int            `json:"dexterity"`
+	Constitution          int            `json:"constitution"`
+	Intelligence          int            `json:"intelligence"`
+	Wisdom                int            `json:"wisdom"`
+	Charisma              int            `json:"charisma"`
+	Proficiencies         map[string]int `json:"proficiencies"`
+	DamageVulnerabilities string         `json:"damage_vulnerabilities"`
+	DamageResistances     string         `json:"damage_resistances"`
+	DamageImmunities      string         `json:"damage_immunities"`
+	ConditionImmunities   string         `json:"condition_immunities"`
+	Senses                string         `json:"senses"`
+	Languages             string         `json:"languages"`
+	ChallengeRating       float64        `json:"challenge_rating"`
+	SpecialAbilities      []SRDAction    `json:"special_abilities"`
+	Actions               []SRDAction    `json:"actions"`
+	LegendaryActions      []SRDAction    `json:"legendary_actions"`
+}
+
+type SRDReference struct {
+	Index string `json:"index"`
+	Name  string `json:"name"`
+}
+
+type SRDSpell struct {
+	Index         string         `json:"index"`
+	Name          string         `json:"name"`
+	Desc          []string       `json:"desc"`
+	HigherLevel   []string       `json:"higher_level"`
+	Range         string         `json:"range"`
+	Components    []string       `json:"components"`
+	Material      string         `json:"material"`
+	Ritual        bool           `json:"ritual"`
+	Duration      string         `json:"duration"`
+	Concentration bool           `json:"concentration"`
+	CastingTime   string         `json:"casting_time"`
+	Level         int            `json:"level"`
+	School        SRDReference   `json:"school"`
+	Classes       []SRDReference `json:"classes"`
+}
+
+// spellIndex is loaded on the first spell lookup.
+var spellIndex *model.SpellIndex
+
+func (s SRDSpell) ToSpell() model.Spell {
+	spell := model.Spell{
+		Name:          s.Name,
+		Level:         s.Level,
+		School:        s.School.Name,
+		CastingTime:   s.CastingTime,
+		Range:         s.Range,
+		Components:    s.Components,
+		Material:      strings.TrimSuffix(s.Material, "."),
+		Duration:      s.Duration,
+		Concentration: s.Concentration,
+		Ritual:        s.Ritual,
+		Description:   strings.Join(s.Desc, "\n\n"),
+		HigherLevels:  strings.Join(s.HigherLevel, " "),
+	}
+	for _, class := range s.Classes {
+		spell.Classes = append(spell.Classes, class.Name)
+	}
+	return spell
+}
+
+func loadSpellIndex() (*model.SpellIndex, error) {
+	if spellIndex != nil {
+		return spellIndex, nil
+	}
+	srdSpells, err := fetchSRD[SRDSpell]("spells")
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	spells := make([]model.Spell, len(srdSpells))
+	for i, s := range srdSpells {
+		spells[i] = s.ToSpell()
+	}
+	spellIndex = model.NewSpellIndex(spells)
+	return spellIndex, nil
+}
+
+func findSpell(name string) (*model.Spell, error) {
+	index, err := loadSpellIndex()
+	if err != nil {
+		return nil, err
+	}
+	if spell, ok := index.Find(name); ok {
+		return &spell, nil
+	}
+	return nil, nil
+}
 
-	var data []T
-	err = json.NewDecoder(resp.Body).Decode(&data)
+func searchSpells(filter model.SpellFilter) ([]model.Spell, error) {
+	index, err := loadSpellIndex()
 	if err != nil {
 		return nil, err
 	}
+	return index.Search(filter), nil
+}
+
+type SRDCost struct {
+	Quantity int    `json:"quantity"`
+	Unit     string `json:"unit"`
+}
+
+type SRDDamage struct {
+	DamageDice string       `json:"damage_dice"`
+	DamageType SRDReference `json:"damage_type"`
+}
+
+type SRDRange struct {
+	Normal int `json:"normal"`
+	Long   int `json:"long"`
+}
+
+type SRDArmorClass struct {
+	Base     int  `json:"base"`
+	DexBonus bool `json:"dex_bonus"`
+	MaxBonus int  `json:"max_bonus"`
+}
+
+type SRDEquipment struct {
+	Index               string         `json:"index"`
+	Name                string         `json:"name"`
+	EquipmentCategory   SRDReference   `json:"equipment_category"`
+	Cost                SRDCost        `json:"cost"`
+	Weight              float64        `json:"weight"`
+	Desc                []string       `json:"desc"`
+	WeaponCategory      string         `json:"weapon_category"`
+	WeaponRange         string         `json:"weapon_range"`
+	Damage              *SRDDamage     `json:"damage"`
+	TwoHandedDamage     *SRDDamage     `json:"two_handed_damage"`
+	Range               SRDRange       `json:"range"`
+	ThrowRange          *SRDRange      `json:"throw_range"`
+	Properties          []SRDReference `json:"properties"`
+	ArmorCategory       string         `json:"armor_category"`
+	ArmorClass          *SRDArmorClass `json:"armor_class"`
+	StrMinimum          int            `json:"str_minimum"`
+	StealthDisadvantage bool           `json:"stealth_disadvantage"`
+}
+
+type SRDMagicItem struct {
+	Index             string       `json:"index"`
+	Name              string       `json:"name"`
+	EquipmentCategory SRDReference `json:"equipment_category"`
+	Rarity            SRDReference `json:"rarity"`
+	Desc              []string     `json:"desc"`
+}
+
+// equipmentIndex is loaded on the first equipment lookup.
+var equipmentIndex *model.EquipmentIndex
+
+var attunementRegex = regexp.MustCompile(`\((requires attunement[^)]*)\)`)
+
+func (e SRDEquipment) ToEquipment() model.Equipment {
+	equipment := model.Equipment{
+		Name:        e.Name,
+		Category:    e.EquipmentCategory.Name,
+		Weight:      e.Weight,
+		Description: strings.Join(e.Desc, "\n\n"),
+	}
+	if e.Cost.Quantity != 0 {
+		equipment.Cost = fmt.Sprintf("%d %s", e.Cost.Quantity, e.Cost.Unit)
+	}
+	if e.WeaponCategory != "" {
+		weapon := &model.Weapon{Category: e.WeaponCategory, Range: e.WeaponRange}
+		if e.Damage != nil {
+			weapon.Damage, weapon.DamageType = e.Damage.DamageDice, e.Damage.DamageType.Name
+		}
+		if e.TwoHandedDamage != nil {
+			weapon.Versatile = e.TwoHandedDamage.DamageDice
+		}
+		for _, property := range e.Properties {
+			weapon.Properties = append(weapon.Properties, property.Name)
+		}
+		weaponRange := e.Range
+		if e.ThrowRange != nil {
+			weaponRange = *e.ThrowRange
+		}
+		if e.WeaponRange == "Ranged" || e.ThrowRange != nil {
+			weapon.NormalRange, weapon.LongRange = weaponRange.Normal, weaponRange.Long
+		}
+		equipment.Weapon = weapon
+	}
+	if e.ArmorClass != nil {
+		equipment.Armor = &model.Armor{
+			Category:            e.ArmorCategory,
+			BaseAC:              e.ArmorClass.Base,
+			DexBonus:            e.ArmorClass.DexBonus,
+			MaxDexBonus:         e.ArmorClass.MaxBonus,
+			StrengthMinimum:     e.StrMinimum,
+			StealthDisadvantage: e.StealthDisadvantage,
+		}
+	}
+	return equipment
+}
+
+// ToEquipment reads the item's type and attunement from the first line of
+// its description, e.g. "Wondrous item, rare (requires attunement)".
+func (m SRDMagicItem) ToEquipment() model.Equipment {
+	equipment := model.Equipment{
+		Name:     m.Name,
+		Category: m.EquipmentCategory.Name,
+		Rarity:   m.Rarity.Name,
+	}
+	desc := m.Desc
+	if len(desc) > 0 {
+		if itemType, _, ok := strings.Cut(desc[0], ","); ok {
+			equipment.ItemType = itemType
+			if match := attunementRegex.FindStringSubmatch(desc[0]); len(match) > 1 {
+				equipment.Attunement = match[1]
+			}
+			desc = desc[1:]
+		}
+	}
+	equipment.Description = strings.Join(desc, "\n\n")
+	return equipment
+}
+
+func loadEquipmentIndex() (*model.EquipmentIndex, error) {
+	if equipmentIndex != nil {
+		return equipmentIndex, nil
+	}
+	srdEquipment, err := fetchSRD[SRDEquipment]("equipment")
+	if err != nil {
+		return nil, err
+	}
+	srdMagicItems, err := fetchSRD[SRDMagicItem]("magic-items")
+	if err != nil {
+		return nil, err
+	}
+	var equipment []model.Equipment
+	for _, e := range srdEquipment {
+		equipment = append(equipment, e.ToEquipment())
+	}
+	for _, m := range srdMagicItems {
+		equipment = append(equipment, m.ToEquipment())
+	}
+	equipmentIndex = model.NewEquipmentIndex(equipment)
+	return equipmentIndex, nil
+}
+
+func findEquipment(name string) (*model.Equipment, error) {
+	index, err := loadEquipmentIndex()
+	if err != nil {
+		return nil, err
+	}
+	if equipment, ok := index.Find(name); ok {
+		return &equipment, nil
+	}
+	return nil, nil
+}
+
+func searchEquipment(query string, category string) ([]model.Equipment, error) {
+	index, err := loadEquipmentIndex()
+	if err != nil {
+		return nil, err
+	}
+	return index.Search(query, category), nil
+}
+
+type SRDRule struct {
+	Index        string       `json:"index"`
+	Name         string       `json:"name"`
+	FullName     string       `json:"full_name"`
+	Desc         []string     `json:"desc"`
+	AbilityScore SRDReference `json:"ability_score"`
+}
+
+type SRDRuleSection struct {
+	Index string `json:"index"`
+	Name  string `json:"name"`
+	Desc  string `json:"desc"`
+}
+
+// rulesReference starts from the rules built into the plugin, so lookups
+// work offline, and is updated by refreshRules.
+var rulesReference = model.DefaultRules()
+
+// refreshRules fetches the latest SRD rules reference, keeping the built-in
+// entries if it cannot.
+func refreshRules() error {
+	var rules []model.Rule
+	abilityNames := map[string]string{}
+	for _, endpoint := range []struct {
+		name string
+		kind model.RuleKind
+	}{
+		{"ability-scores", model.RuleAbility},
+		{"conditions", model.RuleCondition},
+		{"damage-types", model.RuleDamageType},
+		{"skills", model.RuleSkill},
+	} {
+		srdRules, err := fetchSRD[SRDRule](endpoint.name)
+		if err != nil {
+			return err
+		}
+		for _, r := range srdRules {
+			rule := model.Rule{Kind: endpoint.kind, Name: r.Name, Description: strings.Join(r.Desc, "\n\n")}
+			switch endpoint.kind {
+			case model.RuleAbility:
+				abilityNames[r.Name] = r.FullName
+				rule.Name = r.FullName
+			case model.RuleSkill:
+				rule.Ability = abilityNames[r.AbilityScore.Name]
+			}
+			rules = append(rules, rule)
+		}
+	}
+	sections, err := fetchSRD[SRDRuleSection]("rule-sections")
+	if err != nil {
+		return err
+	}
+	for _, section := range sections {
+		rules = append(rules, model.Rule{Kind: model.RuleSection, Name: section.Name, Description: strings.TrimSpace(section.Desc)})
+	}
+
+	rulesReference = rulesReference.Merge(rules)
+	return nil
+}
+
+// reportSkipped is told about each record that is left out of a lookup
+// because it could not be read. main logs them to the console.
+var reportSkipped = func(err error) {}
+
+// fetchSRD reads a collection from the content sources. A record that does
+// not decode is skipped and reported rather than failing the whole lookup.
+func fetchSRD[T any](collection string) ([]T, error) {
+	records, err := contentSources.Fetch(context.Background(), collection)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]T, 0, len(records))
+	for _, record := range records {
+		var item T
+		if err := json.Unmarshal(record, &item); err != nil {
+			reportSkipped(fmt.Errorf("%s: skipped %q: %w", collection, recordKey(record), err))
+			continue
+		}
+		data = append(data, item)
+	}
 
 	return data, nil
 }
 
-func findMonster(name string) (*SRDMonster, error) {
+// srdMonsters is loaded on the first monster lookup.
+var srdMonsters []SRDMonster
+
+func loadMonsters() ([]SRDMonster, error) {
+	if srdMonsters != nil {
+		return srdMonsters, nil
+	}
 	monsters, err := fetchSRD[SRDMonster]("monsters")
 	if err != nil {
 		return nil, err
 	}
+	srdMonsters = monsters
+	return srdMonsters, nil
+}
+
+func findMonster(name string) (*SRDMonster, error) {
+	monsters, err := loadMonsters()
+	if err != nil {
+		return nil, err
+	}
 
 	for _, m := range monsters {
 		if strings.EqualFold(m.Name, name) {
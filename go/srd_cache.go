@@ -0,0 +1,68 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall/js"
+)
+
+// jsCache is a FetchCache kept by the plugin, in IndexedDB, through a store
+// object whose get(key) and set(key, value) return promises of JSON strings.
+type jsCache struct {
+	store js.Value
+}
+
+func (cache jsCache) Get(key string) (CacheEntry, bool, error) {
+	value, err := await(cache.store.Call("get", key))
+	if err != nil || value.Type() != js.TypeString {
+		return CacheEntry{}, false, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal([]byte(value.String()), &entry); err != nil {
+		return CacheEntry{}, false, err
+	}
+	return entry, true, nil
+}
+
+func (cache jsCache) Set(key string, entry CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = await(cache.store.Call("set", key, string(data)))
+	return err
+}
+
+// await blocks until a promise settles.
+func await(promise js.Value) (js.Value, error) {
+	values := make(chan js.Value, 1)
+	errs := make(chan error, 1)
+	resolve := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			values <- js.Undefined()
+		} else {
+			values <- args[0]
+		}
+		return nil
+	})
+	defer resolve.Release()
+	reject := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			errs <- errors.New("promise rejected")
+		} else {
+			errs <- errors.New(args[0].Call("toString").String())
+		}
+		return nil
+	})
+	defer reject.Release()
+
+	promise.Call("then", resolve, reject)
+	select {
+	case value := <-values:
+		return value, nil
+	case err := <-errs:
+		return js.Undefined(), err
+	}
+}
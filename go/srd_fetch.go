@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a response kept by a FetchCache, with the validators used to
+// check whether it is still current.
+type CacheEntry struct {
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+// FetchCache stores responses by URL between fetches. In the browser it is
+// backed by IndexedDB so the SRD data survives restarts.
+type FetchCache interface {
+	Get(key string) (CacheEntry, bool, error)
+	Set(key string, entry CacheEntry) error
+}
+
+// MemoryCache is a FetchCache that lasts as long as the process.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]CacheEntry{}}
+}
+
+func (cache *MemoryCache) Get(key string) (CacheEntry, bool, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entry, ok := cache.entries[key]
+	return entry, ok, nil
+}
+
+func (cache *MemoryCache) Set(key string, entry CacheEntry) error {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries[key] = entry
+	return nil
+}
+
+// StatusError is returned for a response other than 200 OK or 304 Not
+// Modified.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: %s", err.URL, err.Status)
+}
+
+// retryable reports whether the request might succeed if tried again.
+func (err *StatusError) retryable() bool {
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= 500
+}
+
+// FetchClient gets JSON over HTTP, checking the status, timing out each
+// attempt, retrying failures with exponential backoff, and revalidating
+// cached responses with ETag and If-Modified-Since.
+type FetchClient struct {
+	Client *http.Client
+	Cache  FetchCache
+	// Timeout limits each attempt.
+	Timeout time.Duration
+	// Retries is how many times a failed request is tried again, waiting
+	// Backoff before the first retry and twice as long before each one after.
+	Retries int
+	Backoff time.Duration
+}
+
+func NewFetchClient(cache FetchCache) *FetchClient {
+	return &FetchClient{
+		Client:  http.DefaultClient,
+		Cache:   cache,
+		Timeout: 15 * time.Second,
+		Retries: 3,
+		Backoff: 500 * time.Millisecond,
+	}
+}
+
+// fetchClient is used by sources that do not have their own.
+var fetchClient = NewFetchClient(NewMemoryCache())
+
+// Get returns the body at url. A cached body is returned if the server says
+// it has not changed, or if every attempt to reach it fails.
+func (client *FetchClient) Get(ctx context.Context, url string) ([]byte, error) {
+	var cached *CacheEntry
+	if client.Cache != nil {
+		// A broken cache only costs a fresh download.
+		if entry, ok, err := client.Cache.Get(url); err == nil && ok {
+			cached = &entry
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt <= client.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, errors.Join(err, ctx.Err())
+			case <-time.After(client.Backoff << (attempt - 1)):
+			}
+		}
+
+		var body []byte
+		body, err = client.attempt(ctx, url, cached)
+		if err == nil {
+			return body, nil
+		}
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && !statusErr.retryable() {
+			return nil, err
+		}
+	}
+
+	if cached != nil {
+		return cached.Body, nil
+	}
+	return nil, err
+}
+
+func (client *FetchClient) attempt(ctx context.Context, url string, cached *CacheEntry) ([]byte, error) {
+	if client.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	httpClient := client.Client
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached.Body, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, &StatusError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if client.Cache != nil {
+		// Failing to cache only costs a download next time.
+		_ = client.Cache.Set(url, CacheEntry{
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    time.Now(),
+		})
+	}
+	return body, nil
+}
+
+// GetJSON decodes the JSON body at url into v.
+func (client *FetchClient) GetJSON(ctx context.Context, url string, v any) error {
+	body, err := client.Get(ctx, url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("GET %s: %w", url, err)
+	}
+	return nil
+}
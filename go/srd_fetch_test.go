@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testFetchClient(server *httptest.Server, cache FetchCache) *FetchClient {
+	return &FetchClient{
+		Client:  server.Client(),
+		Cache:   cache,
+		Timeout: time.Second,
+		Retries: 2,
+		Backoff: time.Millisecond,
+	}
+}
+
+func TestFetchClientStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		requests int32
+		body     string
+		err      string
+	}{
+		{"ok", []int{200}, 1, `["ok"]`, ""},
+		{"not found is not retried", []int{404}, 1, "", "404 Not Found"},
+		{"server errors are retried", []int{503, 500, 200}, 3, `["ok"]`, ""},
+		{"too many requests is retried", []int{429, 200}, 2, `["ok"]`, ""},
+		{"retries run out", []int{500, 500, 500, 200}, 3, "", "500 Internal Server Error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[requests.Add(1)-1]
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					fmt.Fprint(w, `["ok"]`)
+				} else {
+					fmt.Fprint(w, `<html>error</html>`)
+				}
+			}))
+			defer server.Close()
+
+			body, err := testFetchClient(server, nil).Get(context.Background(), server.URL+"/monsters.json")
+			assert.Equal(t, tt.requests, requests.Load())
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+}
+
+func TestFetchClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := testFetchClient(server, nil)
+	client.Timeout = 20 * time.Millisecond
+	client.Retries = 1
+
+	start := time.Now()
+	_, err := client.Get(context.Background(), server.URL)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Get(ctx, server.URL)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFetchClientBackoff(t *testing.T) {
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := testFetchClient(server, nil)
+	client.Retries = 3
+	client.Backoff = 10 * time.Millisecond
+
+	_, err := client.Get(context.Background(), server.URL)
+	assert.ErrorContains(t, err, "502 Bad Gateway")
+	assert.Len(t, times, 4)
+	for i, wait := range []time.Duration{10, 20, 40} {
+		assert.GreaterOrEqual(t, times[i+1].Sub(times[i]), wait*time.Millisecond)
+	}
+}
+
+func TestFetchClientRevalidation(t *testing.T) {
+	const lastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+	var down atomic.Bool
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case down.Load():
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/etag" && r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		case r.URL.Path == "/modified" && r.Header.Get("If-Modified-Since") == lastModified:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			downloads.Add(1)
+			w.Header().Set("ETag", `"v1"`)
+			if r.URL.Path == "/modified" {
+				w.Header().Del("ETag")
+				w.Header().Set("Last-Modified", lastModified)
+			}
+			fmt.Fprint(w, `["goblin"]`)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+	for _, path := range []string{"/etag", "/modified"} {
+		t.Run(path, func(t *testing.T) {
+			downloads.Store(0)
+			down.Store(false)
+			// A new client with the same cache, as after restarting the plugin.
+			for i := 0; i < 3; i++ {
+				body, err := testFetchClient(server, cache).Get(context.Background(), server.URL+path)
+				assert.NoError(t, err)
+				assert.Equal(t, `["goblin"]`, string(body))
+			}
+			assert.Equal(t, int32(1), downloads.Load())
+
+			entry, ok, err := cache.Get(server.URL + path)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.False(t, entry.FetchedAt.IsZero())
+
+			down.Store(true)
+			body, err := testFetchClient(server, cache).Get(context.Background(), server.URL+path)
+			assert.NoError(t, err, "the cached body is used when the server is down")
+			assert.Equal(t, `["goblin"]`, string(body))
+		})
+	}
+
+	_, err := testFetchClient(server, cache).Get(context.Background(), server.URL+"/uncached")
+	assert.ErrorContains(t, err, "503 Service Unavailable")
+}
+
+func TestFetchClientGetJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `not json`)
+	}))
+	defer server.Close()
+
+	var records []string
+	err := testFetchClient(server, nil).GetJSON(context.Background(), server.URL, &records)
+	assert.ErrorContains(t, err, "GET "+server.URL)
+}
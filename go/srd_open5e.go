@@ -0,0 +1,477 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Open5eSource reads collections from an Open5e-style API, which serves each
+// endpoint in pages of results linked by next. Its records are converted to
+// the 5e-bits SRD shape.
+type Open5eSource struct {
+	Label   string
+	BaseURL string
+	Client  *FetchClient
+}
+
+// open5eEndpoint is an API endpoint a collection is read from, and how to
+// convert its records.
+type open5eEndpoint struct {
+	path    string
+	convert func(record json.RawMessage) (interface{}, error)
+}
+
+// open5eCollections lists the endpoints each collection is read from.
+// Collections the API does not have are left out.
+var open5eCollections = map[string][]open5eEndpoint{
+	"monsters":    {{"monsters", convertOpen5eMonster}},
+	"spells":      {{"spells", convertOpen5eSpell}},
+	"equipment":   {{"weapons", convertOpen5eWeapon}, {"armor", convertOpen5eArmor}},
+	"magic-items": {{"magicitems", convertOpen5eMagicItem}},
+	"conditions":  {{"conditions", convertOpen5eCondition}},
+}
+
+func (source *Open5eSource) Name() string {
+	return source.Label
+}
+
+// Fetch reads every page of the collection's endpoints. A record that cannot
+// be converted is skipped and reported.
+func (source *Open5eSource) Fetch(ctx context.Context, collection string) ([]json.RawMessage, error) {
+	records := []json.RawMessage{}
+	for _, endpoint := range open5eCollections[collection] {
+		results, err := source.fetchPages(ctx, endpoint.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			converted, err := endpoint.convert(result)
+			if err == nil {
+				var record json.RawMessage
+				record, err = json.Marshal(converted)
+				if err == nil {
+					records = append(records, record)
+					continue
+				}
+			}
+			reportSkipped(fmt.Errorf("%s %s: skipped %q: %w", source.Label, endpoint.path, recordKey(result), err))
+		}
+	}
+	return records, nil
+}
+
+func (source *Open5eSource) fetchPages(ctx context.Context, path string) ([]json.RawMessage, error) {
+	next := fmt.Sprintf("%s/%s/", strings.TrimSuffix(source.BaseURL, "/"), path)
+
+	results := []json.RawMessage{}
+	visited := map[string]bool{}
+	for next != "" && !visited[next] {
+		visited[next] = true
+		var page struct {
+			Next    *string           `json:"next"`
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := orDefault(source.Client).GetJSON(ctx, next, &page); err != nil {
+			return nil, err
+		}
+		results = append(results, page.Results...)
+
+		if page.Next == nil {
+			break
+		}
+		// Resolve relative links against the page they came from.
+		base, err := url.Parse(next)
+		if err != nil {
+			return nil, err
+		}
+		link, err := base.Parse(*page.Next)
+		if err != nil {
+			return nil, err
+		}
+		next = link.String()
+	}
+	return results, nil
+}
+
+type open5eAction struct {
+	Name        string `json:"name"`
+	Desc        string `json:"desc"`
+	AttackBonus int    `json:"attack_bonus"`
+	DamageDice  string `json:"damage_dice"`
+	DamageBonus int    `json:"damage_bonus"`
+}
+
+// open5eActions is a list of actions, which the API sends as "" when there
+// are none.
+type open5eActions []open5eAction
+
+func (actions *open5eActions) UnmarshalJSON(data []byte) error {
+	if string(data) == `""` || string(data) == "null" {
+		*actions = nil
+		return nil
+	}
+	return json.Unmarshal(data, (*[]open5eAction)(actions))
+}
+
+func (actions open5eActions) srd() []SRDAction {
+	srd := []SRDAction{}
+	for _, a := range actions {
+		srd = append(srd, SRDAction{Name: a.Name, Desc: a.Desc, AttackBonus: a.AttackBonus, DamageDice: a.DamageDice, DamageBonus: a.DamageBonus})
+	}
+	return srd
+}
+
+type open5eMonster struct {
+	Slug                  string                     `json:"slug"`
+	Name                  string                     `json:"name"`
+	Size                  string                     `json:"size"`
+	Type                  string                     `json:"type"`
+	Subtype               string                     `json:"subtype"`
+	Alignment             string                     `json:"alignment"`
+	ArmorClass            int                        `json:"armor_class"`
+	HitPoints             int                        `json:"hit_points"`
+	HitDice               string                     `json:"hit_dice"`
+	Speed                 map[string]json.RawMessage `json:"speed"`
+	Strength              int                        `json:"strength"`
+	Dexterity             int                        `json:"dexterity"`
+	Constitution          int                        `json:"constitution"`
+	Intelligence          int                        `json:"intelligence"`
+	Wisdom                int                        `json:"wisdom"`
+	Charisma              int                        `json:"charisma"`
+	StrengthSave          *int                       `json:"strength_save"`
+	DexteritySave         *int                       `json:"dexterity_save"`
+	ConstitutionSave      *int                       `json:"constitution_save"`
+	IntelligenceSave      *int                       `json:"intelligence_save"`
+	WisdomSave            *int                       `json:"wisdom_save"`
+	CharismaSave          *int                       `json:"charisma_save"`
+	Skills                map[string]int             `json:"skills"`
+	DamageVulnerabilities string                     `json:"damage_vulnerabilities"`
+	DamageResistances     string                     `json:"damage_resistances"`
+	DamageImmunities      string                     `json:"damage_immunities"`
+	ConditionImmunities   string                     `json:"condition_immunities"`
+	Senses                string                     `json:"senses"`
+	Languages             string                     `json:"languages"`
+	CR                    float64                    `json:"cr"`
+	SpecialAbilities      open5eActions              `json:"special_abilities"`
+	Actions               open5eActions              `json:"actions"`
+	LegendaryActions      open5eActions              `json:"legendary_actions"`
+}
+
+func convertOpen5eMonster(record json.RawMessage) (interface{}, error) {
+	var m open5eMonster
+	if err := json.Unmarshal(record, &m); err != nil {
+		return nil, err
+	}
+	monster := SRDMonster{
+		ID:                    m.Slug,
+		Name:                  m.Name,
+		Size:                  m.Size,
+		Type:                  m.Type,
+		Subtype:               m.Subtype,
+		Alignment:             m.Alignment,
+		ArmorClass:            m.ArmorClass,
+		HitPoints:             m.HitPoints,
+		HitDice:               m.HitDice,
+		Speed:                 open5eSpeed(m.Speed),
+		Strength:              m.Strength,
+		Dexterity:             m.Dexterity,
+		Constitution:          m.Constitution,
+		Intelligence:          m.Intelligence,
+		Wisdom:                m.Wisdom,
+		Charisma:              m.Charisma,
+		Proficiencies:         map[string]int{},
+		DamageVulnerabilities: m.DamageVulnerabilities,
+		DamageResistances:     m.DamageResistances,
+		DamageImmunities:      m.DamageImmunities,
+		ConditionImmunities:   m.ConditionImmunities,
+		Senses:                m.Senses,
+		Languages:             m.Languages,
+		ChallengeRating:       m.CR,
+		SpecialAbilities:      m.SpecialAbilities.srd(),
+		Actions:               m.Actions.srd(),
+		LegendaryActions:      m.LegendaryActions.srd(),
+	}
+	for _, save := range []struct {
+		ability string
+		bonus   *int
+	}{
+		{"STR", m.StrengthSave},
+		{"DEX", m.DexteritySave},
+		{"CON", m.ConstitutionSave},
+		{"INT", m.IntelligenceSave},
+		{"WIS", m.WisdomSave},
+		{"CHA", m.CharismaSave},
+	} {
+		if save.bonus != nil {
+			monster.Proficiencies["Saving Throw: "+save.ability] = *save.bonus
+		}
+	}
+	for skill, bonus := range m.Skills {
+		monster.Proficiencies["Skill: "+titleWords(skill)] = bonus
+	}
+	return monster, nil
+}
+
+// open5eSpeed writes speeds such as {"walk": 30, "fly": 60} the way a stat
+// block does: "30 ft., fly 60 ft.".
+func open5eSpeed(speed map[string]json.RawMessage) string {
+	var modes []string
+	for mode := range speed {
+		if mode != "walk" {
+			modes = append(modes, mode)
+		}
+	}
+	sort.Strings(modes)
+
+	var parts []string
+	if feet, err := strconv.Atoi(string(speed["walk"])); err == nil {
+		parts = append(parts, fmt.Sprintf("%d ft.", feet))
+	}
+	for _, mode := range modes {
+		if feet, err := strconv.Atoi(string(speed[mode])); err == nil {
+			parts = append(parts, fmt.Sprintf("%s %d ft.", mode, feet))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+type open5eSpell struct {
+	Slug                  string `json:"slug"`
+	Name                  string `json:"name"`
+	Desc                  string `json:"desc"`
+	HigherLevel           string `json:"higher_level"`
+	Range                 string `json:"range"`
+	Components            string `json:"components"`
+	Material              string `json:"material"`
+	CanBeCastAsRitual     bool   `json:"can_be_cast_as_ritual"`
+	Duration              string `json:"duration"`
+	RequiresConcentration bool   `json:"requires_concentration"`
+	CastingTime           string `json:"casting_time"`
+	LevelInt              int    `json:"level_int"`
+	School                string `json:"school"`
+	DndClass              string `json:"dnd_class"`
+}
+
+func convertOpen5eSpell(record json.RawMessage) (interface{}, error) {
+	var s open5eSpell
+	if err := json.Unmarshal(record, &s); err != nil {
+		return nil, err
+	}
+	spell := SRDSpell{
+		Index:         s.Slug,
+		Name:          s.Name,
+		Desc:          paragraphs(s.Desc),
+		HigherLevel:   paragraphs(s.HigherLevel),
+		Range:         s.Range,
+		Components:    splitOpen5eList(s.Components),
+		Material:      s.Material,
+		Ritual:        s.CanBeCastAsRitual,
+		Duration:      s.Duration,
+		Concentration: s.RequiresConcentration,
+		CastingTime:   s.CastingTime,
+		Level:         s.LevelInt,
+		School:        SRDReference{Index: strings.ToLower(s.School), Name: titleWords(s.School)},
+		Classes:       []SRDReference{},
+	}
+	for _, class := range splitOpen5eList(s.DndClass) {
+		spell.Classes = append(spell.Classes, SRDReference{Index: strings.ToLower(class), Name: class})
+	}
+	return spell, nil
+}
+
+type open5eWeapon struct {
+	Slug       string   `json:"slug"`
+	Name       string   `json:"name"`
+	Category   string   `json:"category"`
+	Cost       string   `json:"cost"`
+	DamageDice string   `json:"damage_dice"`
+	DamageType string   `json:"damage_type"`
+	Weight     string   `json:"weight"`
+	Properties []string `json:"properties"`
+}
+
+var (
+	open5eRangeRegex     = regexp.MustCompile(`range (\d+)/(\d+)`)
+	open5eVersatileRegex = regexp.MustCompile(`versatile \((\d+d\d+)\)`)
+)
+
+func convertOpen5eWeapon(record json.RawMessage) (interface{}, error) {
+	var w open5eWeapon
+	if err := json.Unmarshal(record, &w); err != nil {
+		return nil, err
+	}
+	// Categories read like "Simple Melee Weapons".
+	category := strings.Fields(w.Category)
+	if len(category) < 2 {
+		return nil, fmt.Errorf("unknown weapon category %q", w.Category)
+	}
+	weapon := SRDEquipment{
+		Index:             w.Slug,
+		Name:              w.Name,
+		EquipmentCategory: SRDReference{Index: "weapon", Name: "Weapon"},
+		Cost:              open5eCost(w.Cost),
+		Weight:            leadingNumber(w.Weight),
+		WeaponCategory:    category[0],
+		WeaponRange:       category[1],
+		Damage:            &SRDDamage{DamageDice: w.DamageDice, DamageType: SRDReference{Index: w.DamageType, Name: titleWords(w.DamageType)}},
+		Properties:        []SRDReference{},
+	}
+	for _, property := range w.Properties {
+		name, _, _ := strings.Cut(property, " (")
+		weapon.Properties = append(weapon.Properties, SRDReference{Index: strings.ToLower(name), Name: titleWords(name)})
+		if match := open5eRangeRegex.FindStringSubmatch(property); match != nil {
+			normal, _ := strconv.Atoi(match[1])
+			long, _ := strconv.Atoi(match[2])
+			if strings.HasPrefix(property, "thrown") {
+				weapon.ThrowRange = &SRDRange{Normal: normal, Long: long}
+			} else {
+				weapon.Range = SRDRange{Normal: normal, Long: long}
+			}
+		}
+		if match := open5eVersatileRegex.FindStringSubmatch(property); match != nil {
+			weapon.TwoHandedDamage = &SRDDamage{DamageDice: match[1], DamageType: weapon.Damage.DamageType}
+		}
+	}
+	return weapon, nil
+}
+
+type open5eArmor struct {
+	Slug                string `json:"slug"`
+	Name                string `json:"name"`
+	Category            string `json:"category"`
+	BaseAC              int    `json:"base_ac"`
+	PlusDexMod          bool   `json:"plus_dex_mod"`
+	PlusMax             int    `json:"plus_max"`
+	StrengthRequirement *int   `json:"strength_requirement"`
+	Cost                string `json:"cost"`
+	Weight              string `json:"weight"`
+	StealthDisadvantage bool   `json:"stealth_disadvantage"`
+}
+
+func convertOpen5eArmor(record json.RawMessage) (interface{}, error) {
+	var a open5eArmor
+	if err := json.Unmarshal(record, &a); err != nil {
+		return nil, err
+	}
+	armor := SRDEquipment{
+		Index:               a.Slug,
+		Name:                a.Name,
+		EquipmentCategory:   SRDReference{Index: "armor", Name: "Armor"},
+		Cost:                open5eCost(a.Cost),
+		Weight:              leadingNumber(a.Weight),
+		ArmorCategory:       strings.TrimSuffix(a.Category, " Armor"),
+		ArmorClass:          &SRDArmorClass{Base: a.BaseAC, DexBonus: a.PlusDexMod, MaxBonus: a.PlusMax},
+		StealthDisadvantage: a.StealthDisadvantage,
+	}
+	if a.StrengthRequirement != nil {
+		armor.StrMinimum = *a.StrengthRequirement
+	}
+	return armor, nil
+}
+
+type open5eMagicItem struct {
+	Slug               string `json:"slug"`
+	Name               string `json:"name"`
+	Type               string `json:"type"`
+	Desc               string `json:"desc"`
+	Rarity             string `json:"rarity"`
+	RequiresAttunement string `json:"requires_attunement"`
+}
+
+// convertOpen5eMagicItem puts the item's type, rarity and attunement in the
+// first line of its description, where the SRD has them. The type, less any
+// detail in parentheses, names the SRD equipment category.
+func convertOpen5eMagicItem(record json.RawMessage) (interface{}, error) {
+	var m open5eMagicItem
+	if err := json.Unmarshal(record, &m); err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("%s, %s", m.Type, m.Rarity)
+	if m.RequiresAttunement != "" {
+		header += fmt.Sprintf(" (%s)", m.RequiresAttunement)
+	}
+	return SRDMagicItem{
+		Index:             m.Slug,
+		Name:              m.Name,
+		EquipmentCategory: SRDReference{Name: open5eMagicItemCategory(m.Type)},
+		Rarity:            SRDReference{Name: titleWords(m.Rarity)},
+		Desc:              append([]string{header}, paragraphs(m.Desc)...),
+	}, nil
+}
+
+// open5eMagicItemCategory turns an Open5e item type such as "Armor (plate)"
+// or "Wondrous item" into the SRD's category, "Armor" or "Wondrous Items".
+func open5eMagicItemCategory(itemType string) string {
+	category, _, _ := strings.Cut(itemType, " (")
+	if strings.EqualFold(category, "Wondrous item") {
+		return "Wondrous Items"
+	}
+	return category
+}
+
+func convertOpen5eCondition(record json.RawMessage) (interface{}, error) {
+	var c struct {
+		Slug string `json:"slug"`
+		Name string `json:"name"`
+		Desc string `json:"desc"`
+	}
+	if err := json.Unmarshal(record, &c); err != nil {
+		return nil, err
+	}
+	return SRDRule{Index: c.Slug, Name: c.Name, Desc: paragraphs(c.Desc)}, nil
+}
+
+// open5eCost reads a cost such as "10 gp".
+func open5eCost(cost string) SRDCost {
+	quantity, unit, _ := strings.Cut(strings.ReplaceAll(strings.TrimSpace(cost), ",", ""), " ")
+	n, err := strconv.Atoi(quantity)
+	if err != nil {
+		return SRDCost{}
+	}
+	return SRDCost{Quantity: n, Unit: unit}
+}
+
+// leadingNumber reads the number a string such as "2 lb." starts with.
+func leadingNumber(s string) float64 {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+	n, _ := strconv.ParseFloat(fields[0], 64)
+	return n
+}
+
+func paragraphs(text string) []string {
+	result := []string{}
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			result = append(result, paragraph)
+		}
+	}
+	return result
+}
+
+func splitOpen5eList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// titleWords capitalizes each word, e.g. "very rare" to "Very Rare".
+func titleWords(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
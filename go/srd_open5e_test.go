@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shiftregister-vg/logseq-odyssey/go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpen5eSource(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.String())
+		switch r.URL.String() {
+		case "/v1/monsters/":
+			fmt.Fprintf(w, `{"count": 3, "next": "http://%s/v1/monsters/?page=2", "results": [{"name": "Aboleth"}, {"name": "Goblin"}]}`, r.Host)
+		case "/v1/monsters/?page=2":
+			fmt.Fprint(w, `{"count": 3, "next": null, "results": [{"name": "Zombie"}]}`)
+		case "/v1/magicitems/":
+			fmt.Fprint(w, `{"count": 1, "next": "/v1/magicitems/?page=2", "results": [{"name": "Bag of Holding"}]}`)
+		case "/v1/magicitems/?page=2":
+			fmt.Fprint(w, `{"count": 2, "next": null, "results": [{"name": "Wand of Magic Missiles"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	source := &Open5eSource{Label: "Open5e", BaseURL: server.URL + "/v1", Client: &FetchClient{Client: server.Client()}}
+
+	monsters, err := source.Fetch(context.Background(), "monsters")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Aboleth", "Goblin", "Zombie"}, recordNames(t, monsters))
+
+	items, err := source.Fetch(context.Background(), "magic-items")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bag of Holding", "Wand of Magic Missiles"}, recordNames(t, items))
+
+	assert.Equal(t, []string{"/v1/monsters/", "/v1/monsters/?page=2", "/v1/magicitems/", "/v1/magicitems/?page=2"}, requests)
+
+	_, err = source.Fetch(context.Background(), "spells")
+	assert.Error(t, err)
+}
+
+// open5eServer serves the pages in testdata/open5e as an Open5e API at /v1.
+func open5eServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1"), "/")
+		page, err := os.ReadFile(filepath.Join("testdata", "open5e", endpoint+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(page)
+	}))
+}
+
+func decodeRecords[T any](t *testing.T, records []json.RawMessage) []T {
+	decoded := make([]T, len(records))
+	for i, record := range records {
+		assert.NoError(t, json.Unmarshal(record, &decoded[i]))
+	}
+	return decoded
+}
+
+func TestOpen5eConversion(t *testing.T) {
+	server := open5eServer(t)
+	defer server.Close()
+	source := &Open5eSource{Label: "Open5e", BaseURL: server.URL + "/v1", Client: &FetchClient{Client: server.Client()}}
+	fetch := func(collection string) []json.RawMessage {
+		records, err := source.Fetch(context.Background(), collection)
+		assert.NoError(t, err)
+		return records
+	}
+
+	monsters := decodeRecords[SRDMonster](t, fetch("monsters"))
+	assert.Len(t, monsters, 2)
+	goblin := monsters[0]
+	assert.Equal(t, "Goblin", goblin.Name)
+	assert.Equal(t, 15, goblin.ArmorClass)
+	assert.Equal(t, "30 ft.", goblin.Speed)
+	assert.Equal(t, 0.25, goblin.ChallengeRating)
+	assert.Equal(t, map[string]int{"Skill: Stealth": 6}, goblin.Proficiencies)
+	assert.Equal(t, []SRDAction{{Name: "Nimble Escape", Desc: "The goblin can take the Disengage or Hide action as a bonus action on each of its turns."}}, goblin.SpecialAbilities)
+	assert.Equal(t, SRDAction{Name: "Scimitar", Desc: "Melee Weapon Attack: +4 to hit, reach 5 ft., one target. Hit: 5 (1d6 + 2) slashing damage.", AttackBonus: 4, DamageDice: "1d6", DamageBonus: 2}, goblin.Actions[0])
+	assert.Empty(t, goblin.LegendaryActions)
+	dragon := monsters[1]
+	assert.Equal(t, "40 ft., climb 40 ft., fly 80 ft.", dragon.Speed)
+	assert.Equal(t, 10.0, dragon.ChallengeRating)
+	assert.Equal(t, 9, dragon.Proficiencies["Saving Throw: CON"])
+	assert.Equal(t, 8, dragon.Proficiencies["Skill: Perception"])
+	assert.Empty(t, dragon.SpecialAbilities)
+
+	spells := decodeRecords[SRDSpell](t, fetch("spells"))
+	assert.Len(t, spells, 1)
+	spell := spells[0].ToSpell()
+	assert.Equal(t, 1, spell.Level)
+	assert.Equal(t, "Enchantment", spell.School)
+	assert.Equal(t, []string{"V", "S"}, spell.Components)
+	assert.Equal(t, []string{"Bard", "Druid", "Sorcerer", "Warlock", "Wizard"}, spell.Classes)
+	assert.Equal(t, "You attempt to charm a humanoid you can see within range.\n\nThe charmed creature regards you as a friendly acquaintance.", spell.Description)
+	assert.False(t, spell.Concentration)
+
+	equipment := decodeRecords[SRDEquipment](t, fetch("equipment"))
+	assert.Len(t, equipment, 3)
+	spear := equipment[0].ToEquipment()
+	assert.Equal(t, "1 gp", spear.Cost)
+	assert.Equal(t, 3.0, spear.Weight)
+	assert.Equal(t, &model.Weapon{Category: "Simple", Range: "Melee", Damage: "1d6", DamageType: "Piercing", Versatile: "1d8", Properties: []string{"Thrown", "Versatile"}, NormalRange: 20, LongRange: 60}, spear.Weapon)
+	longbow := equipment[1].ToEquipment()
+	assert.Equal(t, 150, longbow.Weapon.NormalRange)
+	assert.Equal(t, 600, longbow.Weapon.LongRange)
+	chainMail := equipment[2].ToEquipment()
+	assert.Equal(t, &model.Armor{Category: "Heavy", BaseAC: 16, StrengthMinimum: 13, StealthDisadvantage: true}, chainMail.Armor)
+
+	items := decodeRecords[SRDMagicItem](t, fetch("magic-items"))
+	assert.Len(t, items, 1)
+	cloak := items[0].ToEquipment()
+	assert.Equal(t, "Wondrous Items", cloak.Category)
+	assert.Equal(t, "Wondrous item, uncommon (requires attunement)", cloak.TypeLine())
+	assert.Equal(t, "Uncommon", cloak.Rarity)
+	assert.Equal(t, "requires attunement", cloak.Attunement)
+	assert.Equal(t, "You gain a +1 bonus to AC and saving throws while you wear this cloak.", cloak.Description)
+
+	rules, err := source.Fetch(context.Background(), "rule-sections")
+	assert.NoError(t, err)
+	assert.Empty(t, rules, "Open5e has no rule sections")
+}
+
+func TestFetchSRDSkipsBadRecords(t *testing.T) {
+	open5e := open5eServer(t)
+	defer open5e.Close()
+	srd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"index": "shield", "name": "Shield", "level": 1, "school": {"index": "abjuration", "name": "Abjuration"}}]`)
+	}))
+	defer srd.Close()
+	// Records sent as the Open5e API sends them, without conversion.
+	raw, err := NewHomebrewSource("raw", []byte(`{"spells": [{"slug": "sleep", "name": "Sleep", "level": "1st-level", "school": "Enchantment"}]}`))
+	assert.NoError(t, err)
+
+	savedSources, savedReport := contentSources, reportSkipped
+	defer func() {
+		contentSources, reportSkipped, spellIndex = savedSources, savedReport, nil
+	}()
+	var skipped []string
+	reportSkipped = func(err error) { skipped = append(skipped, err.Error()) }
+	contentSources = ContentSources{
+		raw,
+		&Open5eSource{Label: "Open5e", BaseURL: open5e.URL + "/v1", Client: &FetchClient{Client: open5e.Client()}},
+		&HTTPSource{Label: "5e-bits SRD", BaseURL: srd.URL, Client: &FetchClient{Client: srd.Client()}},
+	}
+	spellIndex = nil
+
+	spells, err := searchSpells(model.SpellFilter{})
+	assert.NoError(t, err)
+	var names []string
+	for _, spell := range spells {
+		names = append(names, spell.Name)
+	}
+	assert.ElementsMatch(t, []string{"Charm Person", "Shield"}, names)
+	assert.Len(t, skipped, 1)
+	assert.Contains(t, skipped[0], `spells: skipped "sleep"`)
+}
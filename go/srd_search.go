@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/shiftregister-vg/logseq-odyssey/go/model"
+)
+
+// searchIndex covers every kind of SRD record and the compendium. It starts
+// with the built-in rules reference and is rebuilt by buildSearchIndex
+// whenever the content sources, rules or compendium change. Rebuilds run on
+// their own goroutines, so the index is swapped atomically.
+var searchIndex atomic.Pointer[model.SearchIndex]
+
+func init() {
+	searchIndex.Store(model.NewSearchIndex(searchDocuments(nil)))
+}
+
+// compendium holds the homebrew creatures from the graph, which the plugin
+// passes in with ingestCompendium.
+var compendium = model.NewCompendium()
+
+// Document makes the monster searchable by its type line, traits and
+// actions.
+func (m SRDMonster) Document() model.Document {
+	text := []string{fmt.Sprintf("%s %s %s, %s", m.Size, m.Type, m.Subtype, m.Alignment)}
+	for _, actions := range [][]SRDAction{m.SpecialAbilities, m.Actions, m.LegendaryActions} {
+		for _, action := range actions {
+			text = append(text, action.Name+". "+action.Desc)
+		}
+	}
+	return model.Document{Kind: "monster", Name: m.Name, Text: strings.Join(text, "\n")}
+}
+
+// buildSearchIndex loads the monsters, spells and equipment and indexes them
+// with the rules. Whatever fails to load is left out and its error returned,
+// so the index still covers the rest.
+func buildSearchIndex() error {
+	var documents []model.Document
+	var errs []error
+
+	monsters, err := loadMonsters()
+	errs = append(errs, err)
+	for _, monster := range monsters {
+		documents = append(documents, monster.Document())
+	}
+	spells, err := searchSpells(model.SpellFilter{})
+	errs = append(errs, err)
+	for _, spell := range spells {
+		documents = append(documents, spell.Document())
+	}
+	equipment, err := searchEquipment("", "")
+	errs = append(errs, err)
+	for _, item := range equipment {
+		documents = append(documents, item.Document())
+	}
+
+	searchIndex.Store(model.NewSearchIndex(searchDocuments(documents)))
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func searchDocuments(documents []model.Document) []model.Document {
+	documents = append(documents, compendium.Documents()...)
+	for _, rule := range rulesReference.Rules("") {
+		documents = append(documents, rule.Document())
+	}
+	return documents
+}
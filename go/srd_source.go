@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ContentSource supplies the records of a collection such as "monsters",
+// "spells" or "equipment" as raw JSON objects in the 5e-bits SRD shape.
+type ContentSource interface {
+	Name() string
+	// Fetch returns the collection's records, or none if the source does not
+	// have that collection.
+	Fetch(ctx context.Context, collection string) ([]json.RawMessage, error)
+}
+
+// ContentSources is a list of sources in priority order. A record from an
+// earlier source hides any record of the same name from a later one.
+type ContentSources []ContentSource
+
+// Fetch merges a collection from every source. Sources that fail are skipped;
+// it only returns an error if none of them could be read.
+func (sources ContentSources) Fetch(ctx context.Context, collection string) ([]json.RawMessage, error) {
+	records := []json.RawMessage{}
+	seen := map[string]bool{}
+	var errs []error
+	for _, source := range sources {
+		fetched, err := source.Fetch(ctx, collection)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+		for _, record := range fetched {
+			key := recordKey(record)
+			if key != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			records = append(records, record)
+		}
+	}
+	if len(errs) > 0 && len(errs) == len(sources) {
+		return nil, errors.Join(errs...)
+	}
+	return records, nil
+}
+
+// recordKey identifies a record across sources by its name, falling back to
+// its index or slug.
+func recordKey(record json.RawMessage) string {
+	var id struct {
+		Name  string `json:"name"`
+		Index string `json:"index"`
+		Slug  string `json:"slug"`
+	}
+	if json.Unmarshal(record, &id) != nil {
+		return ""
+	}
+	for _, key := range []string{id.Name, id.Index, id.Slug} {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// EmbeddedSource reads collections from <collection>.json files, such as
+// data built into the plugin with go:embed.
+type EmbeddedSource struct {
+	Label string
+	Files fs.FS
+}
+
+func (source *EmbeddedSource) Name() string {
+	return source.Label
+}
+
+func (source *EmbeddedSource) Fetch(ctx context.Context, collection string) ([]json.RawMessage, error) {
+	data, err := fs.ReadFile(source.Files, collection+".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []json.RawMessage
+	err = json.Unmarshal(data, &records)
+	return records, err
+}
+
+//go:embed data/srd
+var srdData embed.FS
+
+// builtinSRD is a small slice of the SRD that ships with the plugin so the
+// common monsters, spells and equipment still resolve offline.
+var builtinSRD = func() *EmbeddedSource {
+	files, err := fs.Sub(srdData, "data/srd")
+	if err != nil {
+		panic(err)
+	}
+	return &EmbeddedSource{Label: "Built-in SRD", Files: files}
+}()
+
+// HTTPSource reads collections from <BaseURL>/<collection>.json, each a JSON
+// array, the way the 5e-bits data is served.
+type HTTPSource struct {
+	Label   string
+	BaseURL string
+	// Client defaults to the shared fetchClient.
+	Client *FetchClient
+}
+
+func (source *HTTPSource) Name() string {
+	return source.Label
+}
+
+func (source *HTTPSource) Fetch(ctx context.Context, collection string) ([]json.RawMessage, error) {
+	var records []json.RawMessage
+	err := orDefault(source.Client).GetJSON(ctx, fmt.Sprintf("%s/%s.json", strings.TrimSuffix(source.BaseURL, "/"), collection), &records)
+	return records, err
+}
+
+// HomebrewSource holds collections the user provides as a single JSON
+// object, keyed by collection name:
+//
+//	{"monsters": [...], "spells": [...]}
+type HomebrewSource struct {
+	Label       string
+	collections map[string][]json.RawMessage
+}
+
+func NewHomebrewSource(label string, data []byte) (*HomebrewSource, error) {
+	source := &HomebrewSource{Label: label}
+	if err := json.Unmarshal(data, &source.collections); err != nil {
+		return nil, fmt.Errorf("homebrew %s: %w", label, err)
+	}
+	return source, nil
+}
+
+func (source *HomebrewSource) Name() string {
+	return source.Label
+}
+
+func (source *HomebrewSource) Fetch(ctx context.Context, collection string) ([]json.RawMessage, error) {
+	return source.collections[collection], nil
+}
+
+// ContentSourceConfig describes a source for the plugin to configure.
+type ContentSourceConfig struct {
+	// Type is "http", "open5e", "homebrew" or "embedded", the built-in SRD.
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+	// Data is the homebrew JSON.
+	Data string `json:"data,omitempty"`
+}
+
+func NewContentSource(config ContentSourceConfig) (ContentSource, error) {
+	name := config.Name
+	if name == "" {
+		name = config.URL
+	}
+	switch config.Type {
+	case "http":
+		return &HTTPSource{Label: name, BaseURL: config.URL}, nil
+	case "open5e":
+		return &Open5eSource{Label: name, BaseURL: config.URL}, nil
+	case "homebrew":
+		return NewHomebrewSource(name, []byte(config.Data))
+	case "embedded":
+		return builtinSRD, nil
+	}
+	return nil, fmt.Errorf("unknown content source type %q", config.Type)
+}
+
+// contentSources are where SRD lookups come from, in priority order. Every
+// source is merged, so the built-in SRD comes last and only adds records
+// whose names the others don't already have.
+var contentSources = ContentSources{
+	&HTTPSource{Label: "5e-bits SRD", BaseURL: apiBaseURL},
+	builtinSRD,
+}
+
+// setContentSources replaces the content sources and forgets everything
+// loaded from the old ones.
+func setContentSources(configs []ContentSourceConfig) error {
+	sources := ContentSources{}
+	for _, config := range configs {
+		source, err := NewContentSource(config)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+	contentSources = sources
+	srdMonsters = nil
+	spellIndex = nil
+	equipmentIndex = nil
+	return nil
+}
+
+func orDefault(client *FetchClient) *FetchClient {
+	if client == nil {
+		return fetchClient
+	}
+	return client
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/shiftregister-vg/logseq-odyssey/go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordNames(t *testing.T, records []json.RawMessage) []string {
+	names := []string{}
+	for _, record := range records {
+		var r struct {
+			Name   string `json:"name"`
+			Index  string `json:"index"`
+			Source string `json:"source"`
+		}
+		assert.NoError(t, json.Unmarshal(record, &r))
+		if r.Name == "" {
+			r.Name = r.Index
+		}
+		if r.Source != "" {
+			names = append(names, r.Name+" ("+r.Source+")")
+		} else {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+func TestHTTPSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/spells.json":
+			fmt.Fprint(w, `[{"index": "fireball", "name": "Fireball"}, {"index": "shield", "name": "Shield"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	source := &HTTPSource{Label: "test", BaseURL: server.URL + "/data/", Client: &FetchClient{Client: server.Client()}}
+
+	spells, err := source.Fetch(context.Background(), "spells")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Fireball", "Shield"}, recordNames(t, spells))
+
+	_, err = source.Fetch(context.Background(), "monsters")
+	assert.ErrorContains(t, err, "404 Not Found")
+}
+
+func TestEmbeddedAndHomebrewSources(t *testing.T) {
+	embedded := &EmbeddedSource{Label: "built in", Files: fstest.MapFS{
+		"monsters.json": {Data: []byte(`[{"name": "Goblin"}]`)},
+	}}
+	monsters, err := embedded.Fetch(context.Background(), "monsters")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Goblin"}, recordNames(t, monsters))
+	spells, err := embedded.Fetch(context.Background(), "spells")
+	assert.NoError(t, err)
+	assert.Empty(t, spells)
+
+	homebrew, err := NewHomebrewSource("homebrew", []byte(`{"monsters": [{"name": "Bog Hag"}], "spells": []}`))
+	assert.NoError(t, err)
+	monsters, err = homebrew.Fetch(context.Background(), "monsters")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bog Hag"}, recordNames(t, monsters))
+	equipment, err := homebrew.Fetch(context.Background(), "equipment")
+	assert.NoError(t, err)
+	assert.Empty(t, equipment)
+
+	_, err = NewHomebrewSource("broken", []byte(`[{"name": "Bog Hag"}]`))
+	assert.Error(t, err)
+}
+
+func TestContentSourcesPriority(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/monsters.json":
+			fmt.Fprint(w, `[{"name": "Goblin", "source": "srd"}, {"name": "Orc", "source": "srd"}, {"index": "troll", "source": "srd"}]`)
+		default:
+			http.Error(w, "down", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	homebrew, err := NewHomebrewSource("homebrew", []byte(`{"monsters": [{"name": "goblin", "source": "homebrew"}, {"name": "Bog Hag", "source": "homebrew"}]}`))
+	assert.NoError(t, err)
+	srd := &HTTPSource{Label: "srd", BaseURL: server.URL, Client: &FetchClient{Client: server.Client()}}
+	embedded := &EmbeddedSource{Label: "built in", Files: fstest.MapFS{
+		"monsters.json": {Data: []byte(`[{"name": "Orc", "source": "embedded"}, {"name": "Troll", "source": "embedded"}]`)},
+	}}
+
+	tests := []struct {
+		name       string
+		sources    ContentSources
+		collection string
+		expected   []string
+		err        bool
+	}{
+		{
+			name:       "earlier sources win",
+			sources:    ContentSources{homebrew, srd, embedded},
+			collection: "monsters",
+			expected:   []string{"goblin (homebrew)", "Bog Hag (homebrew)", "Orc (srd)", "troll (srd)"},
+		},
+		{
+			name:       "priority follows order",
+			sources:    ContentSources{embedded, srd},
+			collection: "monsters",
+			expected:   []string{"Orc (embedded)", "Troll (embedded)", "Goblin (srd)"},
+		},
+		{
+			name:       "failed sources are skipped",
+			sources:    ContentSources{srd, homebrew},
+			collection: "spells",
+			expected:   []string{},
+		},
+		{
+			name:       "every source failed",
+			sources:    ContentSources{srd},
+			collection: "spells",
+			err:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := tt.sources.Fetch(context.Background(), tt.collection)
+			if tt.err {
+				assert.ErrorContains(t, err, "srd: GET")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, recordNames(t, records))
+		})
+	}
+}
+
+func TestNewContentSource(t *testing.T) {
+	source, err := NewContentSource(ContentSourceConfig{Type: "open5e", URL: "https://api.open5e.com/v1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.open5e.com/v1", source.Name())
+	assert.IsType(t, &Open5eSource{}, source)
+
+	source, err = NewContentSource(ContentSourceConfig{Type: "homebrew", Name: "My Homebrew", Data: `{"spells": []}`})
+	assert.NoError(t, err)
+	assert.Equal(t, "My Homebrew", source.Name())
+
+	source, err = NewContentSource(ContentSourceConfig{Type: "embedded"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Built-in SRD", source.Name())
+
+	_, err = NewContentSource(ContentSourceConfig{Type: "ftp"})
+	assert.Error(t, err)
+}
+
+func TestBuiltinSRD(t *testing.T) {
+	decode := func(collection string, into interface{}) {
+		records, err := builtinSRD.Fetch(context.Background(), collection)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, records, collection)
+		data, err := json.Marshal(records)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, into), collection)
+	}
+
+	var monsters []SRDMonster
+	decode("monsters", &monsters)
+	var spells []SRDSpell
+	decode("spells", &spells)
+	var equipment []SRDEquipment
+	decode("equipment", &equipment)
+	var items []SRDMagicItem
+	decode("magic-items", &items)
+	assert.Equal(t, "Goblin", monsters[1].Name)
+
+	var magicItems []model.Equipment
+	for _, item := range items {
+		magicItems = append(magicItems, item.ToEquipment())
+	}
+	wondrous := model.NewEquipmentIndex(magicItems).Search("", "Wondrous Items")
+	assert.Len(t, wondrous, 3)
+	for _, item := range wondrous {
+		assert.True(t, strings.HasPrefix(item.TypeLine(), "Wondrous item, "), item.TypeLine())
+	}
+
+	records, err := builtinSRD.Fetch(context.Background(), "conditions")
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
@@ -1,6 +1,7 @@
 import React, { useState } from 'react';
 import { Creature, Action } from '../../types';
 import ActionEditor from './ActionEditor';
+import { addWeaponAttack } from '../../utils';
 
 interface CreatureStatBlockProps {
   initialCreature: Creature;
@@ -94,6 +95,18 @@ const CreatureStatBlock: React.FC<CreatureStatBlockProps> = ({ initialCreature,
     }));
   };
 
+  const [weapon, setWeapon] = useState('');
+
+  const handleAddWeaponAttack = async () => {
+    const updated = await addWeaponAttack(creature, weapon);
+    if (!updated) {
+      logseq.UI.showMsg(`No SRD weapon named ${weapon}`, 'warning');
+      return;
+    }
+    setCreature(prev => ({ ...prev, actions: updated.actions }));
+    setWeapon('');
+  };
+
   const handleKeyDown = (e: React.KeyboardEvent<HTMLTextAreaElement>) => {
     e.stopPropagation();
   };
@@ -265,12 +278,23 @@ const CreatureStatBlock: React.FC<CreatureStatBlockProps> = ({ initialCreature,
               <label htmlFor="languages" className="font-semibold">Languages</label>
               <textarea id="languages" name="languages" value={creature.languages || ''} onChange={handleChange} placeholder="e.g. Common, Draconic" className="w-full p-3 bg-secondary-bg text-primary-text border border-ls-border rounded-md text-base min-h-25 resize-y"></textarea>
             </div>
+            <div className="flex flex-col gap-2">
+              <label htmlFor="traits" className="font-semibold">Traits</label>
+              <ActionEditor
+                actions={creature.traits || []}
+                onChange={(actions) => handleActionChange('traits', actions)}
+              />
+            </div>
             <div className="flex flex-col gap-2">
               <label htmlFor="actions" className="font-semibold">Actions</label>
               <ActionEditor
                 actions={creature.actions || []}
                 onChange={(actions) => handleActionChange('actions', actions)}
               />
+              <div className="flex gap-2">
+                <input id="weapon" value={weapon} onChange={(e) => setWeapon(e.target.value)} placeholder="Weapon, e.g. Longsword" className="flex-grow p-3 bg-transparent text-primary-text border border-ls-border rounded-md text-base" />
+                <button onClick={handleAddWeaponAttack} disabled={!weapon} className="p-2 bg-transparent text-primary-text border border-ls-border rounded-md cursor-pointer">Add Weapon Attack</button>
+              </div>
             </div>
             <div className="flex flex-col gap-2">
               <label htmlFor="bonusActions" className="font-semibold">Bonus Actions</label>
@@ -293,6 +317,31 @@ const CreatureStatBlock: React.FC<CreatureStatBlockProps> = ({ initialCreature,
                 onChange={(actions) => handleActionChange('legendaryActions', actions)}
               />
             </div>
+            <div className="flex flex-col gap-2">
+              <label htmlFor="legendaryActionCount" className="font-semibold">Legendary Actions per Round</label>
+              <input id="legendaryActionCount" type="number" name="legendaryActionCount" value={creature.legendaryActionCount || ''} onChange={(e) => setCreature(prev => ({ ...prev, legendaryActionCount: e.target.value !== '' ? parseInt(e.target.value, 10) : undefined }))} placeholder="e.g. 3" className="w-full p-3 bg-transparent text-primary-text border border-ls-border rounded-md text-base" />
+            </div>
+            <div className="flex flex-col gap-2">
+              <label htmlFor="mythicActions" className="font-semibold">Mythic Actions</label>
+              <ActionEditor
+                actions={creature.mythicActions || []}
+                onChange={(actions) => handleActionChange('mythicActions', actions)}
+              />
+            </div>
+            <div className="flex flex-col gap-2">
+              <label htmlFor="lairActions" className="font-semibold">Lair Actions</label>
+              <ActionEditor
+                actions={creature.lairActions || []}
+                onChange={(actions) => handleActionChange('lairActions', actions)}
+              />
+            </div>
+            <div className="flex flex-col gap-2">
+              <label htmlFor="regionalEffects" className="font-semibold">Regional Effects</label>
+              <ActionEditor
+                actions={creature.regionalEffects || []}
+                onChange={(actions) => handleActionChange('regionalEffects', actions)}
+              />
+            </div>
             <div className="flex flex-col gap-2">
               <label htmlFor="options" className="font-semibold">Options</label>
               <ActionEditor
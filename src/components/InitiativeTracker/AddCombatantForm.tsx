@@ -26,7 +26,7 @@ const AddCombatantForm: React.FC<AddCombatantFormProps> = ({
   <form className="flex flex-col gap-3" onSubmit={(e) => e.preventDefault()}>
     <input
       type="text"
-      placeholder="Combatant Name"
+      placeholder="Combatant Name, or a stat block page like Goblin x4"
       value={name}
       onChange={(e) => onNameChange(e.target.value)}
       onKeyPress={onKeyPress}
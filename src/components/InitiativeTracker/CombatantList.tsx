@@ -1,17 +1,41 @@
 import React from 'react';
-import { Combatant } from '../../types';
+import { Combatant, Rule } from '../../types';
 
 interface CombatantListProps {
   combatants: Combatant[];
+  turn: number;
   onEdit: (index: number) => void;
   onRemove: (index: number) => void;
+  onUseResource: (index: number, resource: string) => void;
+  conditions: Rule[];
+  onApplyCondition: (index: number, condition: string) => void;
+  onRemoveCondition: (index: number, condition: string) => void;
 }
 
-const CombatantList: React.FC<CombatantListProps> = ({ combatants, onEdit, onRemove }) => (
+const CombatantList: React.FC<CombatantListProps> = ({ combatants, turn, onEdit, onRemove, onUseResource, conditions, onApplyCondition, onRemoveCondition }) => (
   <ul className="list-none p-0 m-0 max-h-60 overflow-y-auto border border-ls-border rounded-md">
     {combatants.map((combatant, index) => (
       <li key={index} onClick={() => onEdit(index)} className="flex justify-between items-center p-3 border-b border-ls-border last:border-b-0">
-        <span>{combatant.name} - Init: {combatant.initiative} - Dmg: {combatant.damage}</span>
+        <span className={index === turn ? 'font-bold' : ''}>
+          {combatant.name} - Init: {combatant.initiative} - Dmg: {combatant.damage}
+          {combatant.legendaryActionsMax ? ` - Legendary: ${combatant.legendaryActions || 0}/${combatant.legendaryActionsMax}` : ''}
+        </span>
+        {combatant.resources?.map((resource) => (
+          <button key={resource.name} disabled={resource.remaining <= 0} onClick={(e) => { e.stopPropagation(); onUseResource(index, resource.name); }} className="py-1 px-2 border border-ls-border rounded text-sm">
+            {resource.name} {resource.remaining}/{resource.max}
+          </button>
+        ))}
+        {combatant.conditions?.map((condition) => (
+          <button key={condition} title="Remove condition" onClick={(e) => { e.stopPropagation(); onRemoveCondition(index, condition); }} className="py-1 px-2 border border-ls-border rounded text-sm italic">
+            {condition}
+          </button>
+        ))}
+        <select value="" onClick={(e) => e.stopPropagation()} onChange={(e) => onApplyCondition(index, e.target.value)} className="py-1 px-2 bg-transparent border border-ls-border rounded text-sm">
+          <option value="">Condition…</option>
+          {conditions.map((condition) => (
+            <option key={condition.name} value={condition.name}>{condition.name}</option>
+          ))}
+        </select>
         <button onClick={(e) => { e.stopPropagation(); onRemove(index); }} className="bg-red-500 hover:bg-red-700 text-white font-bold py-1 px-2 rounded">Remove</button>
       </li>
     ))}
@@ -1,9 +1,10 @@
 import React, { useState, useEffect } from 'react';
-import { InitiativeTracker as InitiativeTrackerType, Combatant } from '../../types';
+import { InitiativeTracker as InitiativeTrackerType, Combatant, RechargeRoll } from '../../types';
 import CombatantList from './CombatantList';
 import AddCombatantForm from './AddCombatantForm';
 import RoundTracker from './RoundTracker';
 import Controls from './Controls';
+import { addCharacterToInitiative, addFromStatBlock, addPartyToInitiative, applyCondition, fetchReferenceContent, isCharacterSheet, isParty, listRules, nextInitiativeRound, nextInitiativeTurn, parseCombatantSpec, removeCombatant, removeCondition, resolveStatBlock, sortInitiativeTracker, spendCombatantResource, stringifyCreatureToMarkdown } from '../../utils';
 
 interface InitiativeTrackerProps {
   initialInitiativeTracker?: InitiativeTrackerType;
@@ -17,6 +18,7 @@ const InitiativeTracker: React.FC<InitiativeTrackerProps> = ({ initialInitiative
   const [initiative, setInitiative] = useState('');
   const [damage, setDamage] = useState('');
   const [editingIndex, setEditingIndex] = useState<number | null>(null);
+  const [conditions] = useState(() => listRules('condition'));
 
   useEffect(() => {
     if (initialInitiativeTracker) {
@@ -24,9 +26,42 @@ const InitiativeTracker: React.FC<InitiativeTrackerProps> = ({ initialInitiative
     }
   }, [initialInitiativeTracker]);
 
+  const handleAddFromStatBlock = async () => {
+    const spec = parseCombatantSpec(name);
+    const statBlock = await fetchReferenceContent(spec.reference);
+    if (!statBlock) {
+      logseq.UI.showMsg(`No stat block found for ${spec.reference}`, 'warning');
+      return;
+    }
+    if (isParty(statBlock)) {
+      setInitiativeTracker(addPartyToInitiative(initiativeTracker, statBlock));
+      setName('');
+      return;
+    }
+    if (isCharacterSheet(statBlock)) {
+      setInitiativeTracker(addCharacterToInitiative(initiativeTracker, statBlock, 0));
+      setName('');
+      return;
+    }
+    const creature = await resolveStatBlock(statBlock, true);
+    if (!creature) {
+      logseq.UI.showMsg(`Could not resolve the base creature of ${spec.reference}`, 'warning');
+      return;
+    }
+    setInitiativeTracker(addFromStatBlock(initiativeTracker, stringifyCreatureToMarkdown(creature), spec, false));
+    setName('');
+    setInitiative('');
+    setDamage('');
+  };
+
   const handleAddOrUpdateCombatant = () => {
+    if (name && !initiative && editingIndex === null) {
+      handleAddFromStatBlock();
+      return;
+    }
     if (name && initiative) {
       const newCombatant: Combatant = {
+        ...(editingIndex !== null ? initiativeTracker.combatants[editingIndex] : {}),
         name,
         initiative: parseInt(initiative, 10),
         damage: damage ? parseInt(damage, 10) : 0,
@@ -35,10 +70,10 @@ const InitiativeTracker: React.FC<InitiativeTrackerProps> = ({ initialInitiative
         const updatedCombatants = initiativeTracker.combatants.map((c, index) =>
           index === editingIndex ? newCombatant : c
         );
-        setInitiativeTracker({ ...initiativeTracker, combatants: updatedCombatants });
+        setInitiativeTracker(sortInitiativeTracker({ ...initiativeTracker, combatants: updatedCombatants }));
         setEditingIndex(null);
       } else {
-        setInitiativeTracker({ ...initiativeTracker, combatants: [...initiativeTracker.combatants, newCombatant] });
+        setInitiativeTracker(sortInitiativeTracker({ ...initiativeTracker, combatants: [...initiativeTracker.combatants, newCombatant] }));
       }
       setName('');
       setInitiative('');
@@ -47,8 +82,10 @@ const InitiativeTracker: React.FC<InitiativeTrackerProps> = ({ initialInitiative
   };
 
   const handleRemoveCombatant = (indexToRemove: number) => {
-    setInitiativeTracker({ ...initiativeTracker, combatants: initiativeTracker.combatants.filter((_, index) => index !== indexToRemove) });
-    if (editingIndex === indexToRemove) {
+    setInitiativeTracker(removeCombatant(initiativeTracker, indexToRemove));
+    if (editingIndex !== null && editingIndex > indexToRemove) {
+      setEditingIndex(editingIndex - 1);
+    } else if (editingIndex === indexToRemove) {
       setEditingIndex(null);
       setName('');
       setInitiative('');
@@ -71,8 +108,30 @@ const InitiativeTracker: React.FC<InitiativeTrackerProps> = ({ initialInitiative
     }
   };
 
+  const showRecharges = (recharges: RechargeRoll[] | null) => {
+    recharges?.forEach((r) => {
+      logseq.UI.showMsg(`${r.combatant}: ${r.resource} recharge roll ${r.roll}${r.recharged ? ' - recharged!' : ''}`);
+    });
+  };
+
   const handleNextRound = () => {
-    setInitiativeTracker({ ...initiativeTracker, round: initiativeTracker.round + 1 });
+    const { tracker, recharges } = nextInitiativeRound(initiativeTracker);
+    showRecharges(recharges);
+    setInitiativeTracker(tracker);
+  };
+
+  const handleNextTurn = () => {
+    const { tracker, recharges } = nextInitiativeTurn(initiativeTracker);
+    showRecharges(recharges);
+    setInitiativeTracker(tracker);
+  };
+
+  const handleApplyCondition = (index: number, condition: string) => {
+    const { tracker, rule } = applyCondition(initiativeTracker, index, condition);
+    if (rule) {
+      logseq.UI.showMsg(`${tracker.combatants[index].name} is ${rule.name.toLowerCase()}: ${rule.description}`);
+    }
+    setInitiativeTracker(tracker);
   };
 
   const handleConfirm = () => {
@@ -82,7 +141,7 @@ const InitiativeTracker: React.FC<InitiativeTrackerProps> = ({ initialInitiative
   return (
     <div className="p-4 flex flex-col">
       <div className="flex-grow">
-        <RoundTracker round={initiativeTracker.round} onNextRound={handleNextRound} />
+        <RoundTracker round={initiativeTracker.round} onNextRound={handleNextRound} onNextTurn={handleNextTurn} />
         <hr />
         <AddCombatantForm
           name={name}
@@ -98,8 +157,13 @@ const InitiativeTracker: React.FC<InitiativeTrackerProps> = ({ initialInitiative
         <hr />
         <CombatantList
           combatants={initiativeTracker.combatants}
+          turn={initiativeTracker.turn || 0}
           onEdit={handleEditCombatant}
           onRemove={handleRemoveCombatant}
+          onUseResource={(index, resource) => setInitiativeTracker(spendCombatantResource(initiativeTracker, index, resource))}
+          conditions={conditions}
+          onApplyCondition={handleApplyCondition}
+          onRemoveCondition={(index, condition) => setInitiativeTracker(removeCondition(initiativeTracker, index, condition))}
         />
       </div>
       <hr />
@@ -3,11 +3,13 @@ import React from 'react';
 interface RoundTrackerProps {
   round: number;
   onNextRound: () => void;
+  onNextTurn: () => void;
 }
 
-const RoundTracker: React.FC<RoundTrackerProps> = ({ round, onNextRound }) => (
+const RoundTracker: React.FC<RoundTrackerProps> = ({ round, onNextRound, onNextTurn }) => (
   <div className="flex justify-end gap-3">
     <span>Current Round: {round}</span>
+    <button onClick={onNextTurn} className="p-3 border border-ls-border rounded-md cursor-pointer text-base font-medium">Next Turn</button>
     <button onClick={onNextRound} className="p-3 border border-ls-border rounded-md cursor-pointer text-base font-medium">Start Next Round</button>
   </div>
 );
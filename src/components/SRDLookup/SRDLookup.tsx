@@ -1,13 +1,28 @@
 import React, { useState } from 'react';
+import { SearchResult } from '../../types';
+import { searchAll } from '../../utils';
 
 declare const odysseyWasm: any;
 
 const SRDLookup: React.FC = () => {
   const [searchTerm, setSearchTerm] = useState('');
+  const [results, setResults] = useState<SearchResult[]>([]);
   const [result, setResult] = useState<any>(null);
 
-  const handleSearch = async () => {
-    const monster = await odysseyWasm.findMonster(searchTerm);
+  const handleSearch = () => {
+    setResults(searchAll(searchTerm));
+    setResult(null);
+  };
+
+  const handleSelect = async (selected: SearchResult) => {
+    if (selected.reference) {
+      logseq.Editor.openInRightSidebar(selected.reference);
+      return;
+    }
+    if (selected.kind !== 'monster') {
+      return;
+    }
+    const monster = await odysseyWasm.findMonster(selected.name);
     if (monster) {
       setResult(JSON.parse(monster));
     } else {
@@ -26,6 +41,15 @@ const SRDLookup: React.FC = () => {
         />
         <button className="bg-ls-secondary-background text-ls-primary-text rounded-md px-4 py-1 ml-2" onClick={handleSearch}>Search</button>
       </div>
+      {results.length > 0 && !result && (
+        <ul className="mt-2 list-none p-0 max-h-60 overflow-y-auto">
+          {results.map((r) => (
+            <li key={`${r.kind}:${r.reference ?? r.name}`} onClick={() => handleSelect(r)} className="py-1 cursor-pointer">
+              {r.name} <span className="text-sm italic">{r.reference ? 'homebrew' : r.kind}</span>
+            </li>
+          ))}
+        </ul>
+      )}
       {result && (
         <div className="mt-2">
           <h3 className="text-lg font-bold">{result.name}</h3>
@@ -38,4 +62,4 @@ const SRDLookup: React.FC = () => {
   );
 };
 
-export default SRDLookup;
\ No newline at end of file
+export default SRDLookup;
@@ -0,0 +1,34 @@
+// fetchCache keeps the SRD responses the WASM module downloads in IndexedDB,
+// so they are revalidated rather than downloaded again after a restart.
+const dbName = 'odyssey';
+const storeName = 'fetchCache';
+
+let db: Promise<IDBDatabase> | null = null;
+
+const openDB = (): Promise<IDBDatabase> => {
+  if (!db) {
+    db = new Promise((resolve, reject) => {
+      const request = indexedDB.open(dbName, 1);
+      request.onupgradeneeded = () => request.result.createObjectStore(storeName);
+      request.onsuccess = () => resolve(request.result);
+      request.onerror = () => reject(request.error);
+    });
+  }
+  return db;
+};
+
+const run = async <T>(mode: IDBTransactionMode, action: (store: IDBObjectStore) => IDBRequest<T>): Promise<T> => {
+  const database = await openDB();
+  return new Promise((resolve, reject) => {
+    const request = action(database.transaction(storeName, mode).objectStore(storeName));
+    request.onsuccess = () => resolve(request.result);
+    request.onerror = () => reject(request.error);
+  });
+};
+
+export const fetchCache = {
+  get: async (key: string): Promise<string | null> => (await run<string | undefined>('readonly', (store) => store.get(key))) ?? null,
+  set: async (key: string, value: string): Promise<void> => {
+    await run('readwrite', (store) => store.put(value, key));
+  },
+};
@@ -1,10 +1,13 @@
 import '@logseq/libs';
 import { pluginLoad } from './plugin/plugin';
 import { loadWasm } from './lib/wasm-loader';
+import { fetchCache } from './lib/fetchCache';
+import { setFetchCache } from './utils';
 
 const main = async () => {
   console.log('Odyssey: plugin loaded')
-  loadWasm();
+  await loadWasm();
+  setFetchCache(fetchCache);
   pluginLoad()
 }
 
@@ -0,0 +1,31 @@
+import '@logseq/libs';
+import { CompendiumBlock } from '../types';
+import { ingestCompendium, isStatBlock } from '../utils';
+
+const statBlockQuery = `
+[:find (pull ?b [:block/uuid :block/content {:block/page [:block/original-name]}])
+ :where
+ [?b :block/content ?content]
+ [(clojure.string/includes? ?content "**Armor Class**")]]`;
+
+// refreshCompendium reads every stat block in the graph into the homebrew
+// compendium, so they can be searched alongside the SRD, and warns about
+// creatures with the same name on different blocks.
+export const refreshCompendium = async () => {
+  const results: any[][] = (await logseq.DB.datascriptQuery(statBlockQuery)) ?? [];
+  const blocks: CompendiumBlock[] = results
+    .map(([block]) => ({
+      uuid: typeof block.uuid === 'string' ? block.uuid : block.uuid?.$uuid$ ?? String(block.uuid),
+      page: block.page?.['original-name'] ?? '',
+      content: block.content ?? '',
+    }))
+    .filter((block) => isStatBlock(block.content));
+
+  const { errors, duplicates } = ingestCompendium(blocks);
+  errors.forEach((error) => console.warn(`Odyssey: ${error.page}: ${error.error}`));
+  if (duplicates.length > 0) {
+    const names = duplicates.map((duplicate) => `${duplicate.name} (${duplicate.entries.map((entry) => entry.page).join(', ')})`);
+    logseq.UI.showMsg(`Creatures defined more than once: ${names.join('; ')}`, 'warning');
+  }
+  return blocks.length - errors.length;
+};
@@ -0,0 +1,51 @@
+import '@logseq/libs';
+import { SettingSchemaDesc } from '@logseq/libs/dist/LSPlugin';
+import { ContentSourceConfig } from '../types';
+import { setContentSources } from '../utils';
+
+const srdURL = 'https://5e-bits.github.io/data';
+
+export const contentSourceSettings: SettingSchemaDesc[] = [
+  {
+    key: 'homebrewJSON',
+    type: 'string',
+    inputAs: 'textarea',
+    default: '',
+    title: 'Homebrew content',
+    description: 'JSON object of homebrew records by collection, e.g. {"monsters": [...], "spells": [...]}. Homebrew takes priority over the SRD.',
+  },
+  {
+    key: 'srdURL',
+    type: 'string',
+    default: srdURL,
+    title: 'SRD data URL',
+    description: 'Base URL serving <collection>.json files in the 5e-bits format. Leave blank to skip.',
+  },
+  {
+    key: 'open5eURL',
+    type: 'string',
+    default: '',
+    title: 'Open5e API URL',
+    description: 'Base URL of an Open5e-style paginated API, e.g. https://api.open5e.com/v1, used after the SRD data.',
+  },
+];
+
+// configureContentSources sets the content sources from the plugin settings.
+export const configureContentSources = () => {
+  const settings = logseq.settings ?? {};
+  const sources: ContentSourceConfig[] = [];
+  if (settings.homebrewJSON?.trim()) {
+    sources.push({ type: 'homebrew', name: 'Homebrew', data: settings.homebrewJSON });
+  }
+  const srd = settings.srdURL ?? srdURL;
+  if (srd.trim()) {
+    sources.push({ type: 'http', name: '5e-bits SRD', url: srd.trim() });
+  }
+  if (settings.open5eURL?.trim()) {
+    sources.push({ type: 'open5e', name: 'Open5e', url: settings.open5eURL.trim() });
+  }
+  sources.push({ type: 'embedded', name: 'Built-in SRD' });
+  if (!setContentSources(sources)) {
+    logseq.UI.showMsg('Odyssey: the content source settings are invalid; see the console.', 'error');
+  }
+};
@@ -3,7 +3,7 @@ import { createRoot } from "react-dom/client";
 import CreatureStatBlock from "../components/CreatureStatBlock/CreatureStatBlock";
 import { doc } from "../globals/globals";
 import { Creature } from "../types";
-import { parseCreatureStatBlock, stringifyCreatureToMarkdown } from "../utils";
+import { diffCreatures, mergeCreatures, parseCreatureStatBlock, stringifyCreatureToMarkdown } from "../utils";
 
 export const creatureStatBlock: BlockCommandCallback = async (e) => {
   const key = `odyssey-creature-stat-block-${e.uuid}`;
@@ -45,7 +45,22 @@ export const creatureStatBlock: BlockCommandCallback = async (e) => {
       reactRoot.render(
         <CreatureStatBlock
           initialCreature={creatureData}
-          onConfirm={(creature) => {
+          onConfirm={async (creature) => {
+            // Someone may have saved the block while the editor was open, so
+            // merge their changes instead of overwriting them.
+            const current = await logseq.Editor.getBlock(e.uuid);
+            if (current && current.content && block && current.content !== block.content) {
+              const theirs = parseCreatureStatBlock(current.content);
+              const merged = mergeCreatures(creatureData, creature, theirs);
+              creature = merged.creature;
+              if (merged.conflicts.length > 0) {
+                logseq.UI.showMsg(`Kept your changes to ${merged.conflicts.map((c) => c.field).join(', ')}, which were also edited elsewhere`, 'warning');
+              } else {
+                const diff = diffCreatures(creatureData, theirs);
+                const changes = [...diff.fields.map((c) => c.field), ...diff.actions.map((c) => `${c.name} (${c.kind})`)];
+                logseq.UI.showMsg(`Merged with changes saved elsewhere: ${changes.join(', ')}`, 'success');
+              }
+            }
             const markdown = stringifyCreatureToMarkdown(creature);
             logseq.Editor.updateBlock(e.uuid, markdown);
             logseq.provideUI({ key, template: `` });
@@ -0,0 +1,59 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { createRoot } from "react-dom/client";
+import { doc } from "../globals/globals";
+import { applyCreatureTemplate as applyTemplate, listCreatureTemplates, parseCreatureStatBlock, stringifyCreatureToMarkdown } from "../utils";
+
+// applyCreatureTemplate lets the user pick a template and adds the resulting
+// variant as a new block after the stat block.
+export const applyCreatureTemplate: BlockCommandCallback = async (e) => {
+  const key = `odyssey-creature-template-${e.uuid}`;
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const creature = parseCreatureStatBlock(block.content);
+  const templates = listCreatureTemplates();
+
+  const close = () => logseq.provideUI({ key, template: `` });
+  const apply = async (name: string) => {
+    const variant = applyTemplate(creature, name);
+    close();
+    if (!variant) {
+      logseq.UI.showMsg(`Could not apply the ${name} template`, 'error');
+      return;
+    }
+    await logseq.Editor.insertBlock(e.uuid, stringifyCreatureToMarkdown(variant), { sibling: true });
+  };
+
+  logseq.provideUI({
+    key,
+    close: 'outside',
+    template: `<div id="${key}"></div>`,
+    attrs: { title: `Apply a template to ${creature.name}` },
+    style: {
+      width: '400px',
+      position: 'absolute',
+      top: '50%',
+      left: '50%',
+      transform: 'translate(-50%, -50%)',
+      backgroundColor: 'var(--ls-primary-background-color)',
+      color: 'var(--ls-primary-text-color)',
+    },
+  });
+
+  setTimeout(() => {
+    const rootEl = doc.getElementById(key);
+    if (rootEl) {
+      createRoot(rootEl).render(
+        <ul style={{ padding: '1rem' }}>
+          {templates.map((template) => (
+            <li key={template.name} style={{ marginBottom: '0.5rem' }}>
+              <a href="#" onClick={(event) => { event.preventDefault(); apply(template.name); }}>{template.name}</a>
+              {template.description && <div style={{ fontSize: '0.85em', opacity: 0.8 }}>{template.description}</div>}
+            </li>
+          ))}
+        </ul>
+      );
+    }
+  }, 0);
+}
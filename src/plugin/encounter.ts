@@ -0,0 +1,57 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { Encounter } from "../types";
+import { fetchReferenceContent, isEncounter, launchEncounter, parseEncounter, rateEncounter, stringifyEncounterToMarkdown, stringifyInitiativeTable } from "../utils";
+
+// encounterBlock reads an encounter block with its monsters' stat blocks and
+// its party's block.
+const encounterBlock = async (uuid: string) => {
+  const block = await logseq.Editor.getBlock(uuid);
+  if (!block || !block.content || !isEncounter(block.content)) {
+    logseq.UI.showMsg('Not an encounter block', 'warning');
+    return null;
+  }
+  const encounter: Encounter = parseEncounter(block.content);
+  const statBlocks: Record<string, string> = {};
+  for (const monster of encounter.monsters) {
+    const statBlock = await fetchReferenceContent(monster.reference);
+    if (statBlock) {
+      statBlocks[monster.reference] = statBlock;
+    }
+  }
+  const party = encounter.party ? (await fetchReferenceContent(encounter.party)) ?? '' : '';
+  return { content: block.content, statBlocks, party };
+};
+
+// rateEncounterBlock fills in the monsters' challenge ratings and the
+// encounter's difficulty for its party.
+export const rateEncounterBlock: BlockCommandCallback = async (e) => {
+  const found = await encounterBlock(e.uuid);
+  if (!found) {
+    return;
+  }
+  if (!found.party) {
+    logseq.UI.showMsg('Set the encounter\'s party:: to a party page to rate it', 'warning');
+    return;
+  }
+  const rated = rateEncounter(found.content, found.party, found.statBlocks);
+  if (!rated) {
+    return;
+  }
+  await logseq.Editor.updateBlock(e.uuid, stringifyEncounterToMarkdown(rated.encounter));
+  logseq.UI.showMsg(`${rated.encounter.name}: ${rated.rating.difficulty} (${rated.rating.adjustedXp} adjusted XP)`);
+};
+
+// launchEncounterBlock rolls initiative for the encounter's monsters and
+// party, adding the initiative table below the encounter.
+export const launchEncounterBlock: BlockCommandCallback = async (e) => {
+  const found = await encounterBlock(e.uuid);
+  if (!found) {
+    return;
+  }
+  const tracker = launchEncounter(found.content, found.statBlocks, found.party, false, Date.now());
+  if (!tracker) {
+    logseq.UI.showMsg('Could not launch the encounter', 'error');
+    return;
+  }
+  await logseq.Editor.insertBlock(e.uuid, stringifyInitiativeTable(tracker), { sibling: false });
+};
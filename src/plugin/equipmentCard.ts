@@ -0,0 +1,18 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { findEquipment, stringifyEquipmentToMarkdown } from "../utils";
+
+// equipmentCard replaces a block holding an item's name with the SRD card for
+// that piece of equipment or magic item.
+export const equipmentCard: BlockCommandCallback = async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const name = block.content.trim();
+  const equipment = await findEquipment(name);
+  if (!equipment) {
+    logseq.UI.showMsg(`No SRD equipment named ${name}`, 'warning');
+    return;
+  }
+  await logseq.Editor.updateBlock(e.uuid, stringifyEquipmentToMarkdown(equipment));
+}
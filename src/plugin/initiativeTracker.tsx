@@ -3,7 +3,7 @@ import { createRoot } from "react-dom/client";
 import InitiativeTracker from "../components/InitiativeTracker/InitiativeTracker";
 import { doc } from "../globals/globals";
 import { InitiativeTracker as InitiativeTrackerType } from "../types";
-import { parseInitiativeTable } from "../utils";
+import { parseImprovedInitiative, parseInitiativeTable, stringifyInitiativeTable } from "../utils";
 
 export const initiativeTracker: BlockCommandCallback = async (e) => {
   const key = `odyssey-initiative-tracker-${e.uuid}`;
@@ -11,7 +11,11 @@ export const initiativeTracker: BlockCommandCallback = async (e) => {
   let initialInitiativeTracker: InitiativeTrackerType = { combatants: [], round: 1 };
 
   if (block && block.content) {
-    initialInitiativeTracker = parseInitiativeTable(block.content);
+    // Blocks holding an Improved Initiative encounter export are converted
+    // into a tracker; saving replaces the JSON with the markdown table.
+    const content = block.content.replace(/^```\w*\n?/, '').replace(/\n?```$/, '').trim();
+    const imported = content.startsWith('{') ? parseImprovedInitiative(content) : null;
+    initialInitiativeTracker = imported ?? parseInitiativeTable(block.content);
   }
 
   logseq.provideUI({
@@ -39,10 +43,7 @@ export const initiativeTracker: BlockCommandCallback = async (e) => {
         <InitiativeTracker
           initialInitiativeTracker={initialInitiativeTracker}
           onConfirm={(initiativeTracker) => {
-            const sortedCombatants = [...initiativeTracker.combatants].sort((a, b) => b.initiative - a.initiative);
-            const table = `Round: ${initiativeTracker.round}\n| Name | Initiative | Damage |\n|---|---|---|\n${sortedCombatants
-              .map((c) => `| ${c.name} | ${c.initiative} | ${c.damage} |`)
-              .join('\n')}`;
+            const table = stringifyInitiativeTable(initiativeTracker);
             logseq.Editor.updateBlock(e.uuid, table);
             logseq.provideUI({ key, template: `` }); // Close the UI
           }}
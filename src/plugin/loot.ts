@@ -0,0 +1,35 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { fetchReferenceContent, parseInitiativeTable, rollLoot } from "../utils";
+
+// loot rolls the treasure for the defeated combatants in an initiative table
+// and adds the loot block below it.
+const loot = (hoard: boolean): BlockCommandCallback => async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const parsed = parseInitiativeTable(block.content);
+  if (parsed.combatants.length === 0) {
+    logseq.UI.showMsg('Loot is rolled from an initiative table', 'warning');
+    return;
+  }
+  const statBlocks: Record<string, string> = {};
+  for (const combatant of parsed.combatants) {
+    if (combatant.reference && !(combatant.reference in statBlocks)) {
+      const statBlock = await fetchReferenceContent(combatant.reference);
+      if (statBlock) {
+        statBlocks[combatant.reference] = statBlock;
+      }
+    }
+  }
+  const result = rollLoot(block.content, statBlocks, hoard, Date.now());
+  if (!result) {
+    logseq.UI.showMsg('No defeated creature with a challenge rating', 'warning');
+    return;
+  }
+  await logseq.Editor.insertBlock(e.uuid, result.markdown, { sibling: false });
+}
+
+export const rollIndividualLoot = loot(false);
+
+export const rollHoardLoot = loot(true);
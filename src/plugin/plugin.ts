@@ -4,6 +4,18 @@ import { body, globals } from '../globals/globals';
 import { toolbar } from './toolbar';
 import { initiativeTracker } from './initiativeTracker';
 import { creatureStatBlock } from './creatureStatBlock';
+import { printStatBlock } from './printStatBlock';
+import { applyCreatureTemplate } from './creatureTemplate';
+import { longRest, shortRest } from './rest';
+import { spellCard } from './spellCard';
+import { equipmentCard } from './equipmentCard';
+import { configureContentSources, contentSourceSettings } from './contentSources';
+import { refreshCompendium } from './compendium';
+import { splitStatBlocks } from './splitStatBlocks';
+import { refreshRules } from '../utils';
+import { launchEncounterBlock, rateEncounterBlock } from './encounter';
+import { rollHoardLoot, rollIndividualLoot } from './loot';
+import { exportStatBlockToFantasyStatblock, exportStatBlockToFiveETools, exportStatBlockToFoundry, flattenStatBlock, importStatBlock } from './vttExport';
 
 export const pluginLoad = () => {
   body.classList.add(globals.isPluginEnabled);
@@ -22,10 +34,60 @@ const pluginUnload = () => {
 }
 
 const registerPlugin = async () => {
+  logseq.useSettingsSchema(contentSourceSettings);
+  configureContentSources();
+  logseq.onSettingsChanged(configureContentSources);
+  refreshCompendium();
+
+  logseq.App.registerCommandPalette({ key: 'odyssey-refresh-compendium', label: 'Odyssey: Refresh homebrew compendium' }, async () => {
+    const count = await refreshCompendium();
+    logseq.UI.showMsg(`Compendium has ${count} homebrew creatures`);
+  });
+
+  logseq.App.registerCommandPalette({ key: 'odyssey-refresh-rules', label: 'Odyssey: Refresh SRD rules reference' }, async () => {
+    if (await refreshRules()) {
+      logseq.UI.showMsg('Rules reference updated from the SRD');
+    } else {
+      logseq.UI.showMsg('Odyssey: could not refresh the rules reference; see the console.', 'error');
+    }
+  });
+
   logseq.App.registerUIItem('toolbar', toolbar());
 
   logseq.Editor.registerBlockContextMenuItem('Track Initiative', initiativeTracker);
 
   logseq.Editor.registerBlockContextMenuItem('Creature Stat Block', creatureStatBlock);
+
+  logseq.Editor.registerBlockContextMenuItem('Apply Creature Template', applyCreatureTemplate);
+
+  logseq.Editor.registerBlockContextMenuItem('Print Stat Block', printStatBlock);
+
+  logseq.Editor.registerBlockContextMenuItem('Export Stat Block to 5etools', exportStatBlockToFiveETools);
+
+  logseq.Editor.registerBlockContextMenuItem('Export Stat Block to Foundry', exportStatBlockToFoundry);
+
+  logseq.Editor.registerBlockContextMenuItem('Export Stat Block to Fantasy Statblocks', exportStatBlockToFantasyStatblock);
+
+  logseq.Editor.registerBlockContextMenuItem('Import Stat Block', importStatBlock);
+
+  logseq.Editor.registerBlockContextMenuItem('Flatten Stat Block', flattenStatBlock);
+
+  logseq.Editor.registerBlockContextMenuItem('Split Stat Blocks', splitStatBlocks);
+
+  logseq.Editor.registerBlockContextMenuItem('Spell Card', spellCard);
+
+  logseq.Editor.registerBlockContextMenuItem('Equipment Card', equipmentCard);
+
+  logseq.Editor.registerBlockContextMenuItem('Short Rest', shortRest);
+
+  logseq.Editor.registerBlockContextMenuItem('Long Rest', longRest);
+
+  logseq.Editor.registerBlockContextMenuItem('Rate Encounter', rateEncounterBlock);
+
+  logseq.Editor.registerBlockContextMenuItem('Launch Encounter', launchEncounterBlock);
+
+  logseq.Editor.registerBlockContextMenuItem('Roll Loot', rollIndividualLoot);
+
+  logseq.Editor.registerBlockContextMenuItem('Roll Hoard', rollHoardLoot);
 }
 
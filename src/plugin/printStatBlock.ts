@@ -0,0 +1,25 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { parseCreatureStatBlock, renderCreatureHTML } from "../utils";
+
+// printStatBlock opens the block's stat block in a new window laid out for
+// printing and brings up the print dialog.
+export const printStatBlock: BlockCommandCallback = async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const html = renderCreatureHTML(parseCreatureStatBlock(block.content), 'print');
+  if (!html) {
+    logseq.UI.showMsg('Could not render the stat block', 'error');
+    return;
+  }
+  const preview = window.open('', '_blank');
+  if (!preview) {
+    logseq.UI.showMsg('The print preview window was blocked', 'error');
+    return;
+  }
+  preview.document.write(html);
+  preview.document.close();
+  preview.focus();
+  preview.print();
+}
@@ -0,0 +1,39 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { RestKind } from "../types";
+import { isCharacterSheet, parseInitiativeTable, restCharacter, restInitiative, stringifyInitiativeTable } from "../utils";
+
+// rest gives the character on a sheet, or everyone in an initiative table, a
+// rest and adds what happened as a child block.
+const rest = (kind: RestKind): BlockCommandCallback => async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  let content: string;
+  let log: string[];
+  if (isCharacterSheet(block.content)) {
+    // A short rest spends as many hit dice as it takes to get back to full.
+    const result = restCharacter(block.content, kind, Number.MAX_SAFE_INTEGER);
+    content = result.sheet;
+    log = result.log;
+  } else {
+    const parsed = parseInitiativeTable(block.content);
+    if (parsed.combatants.length === 0) {
+      logseq.UI.showMsg('Rest works on a character sheet or an initiative table', 'warning');
+      return;
+    }
+    const result = restInitiative(parsed, kind);
+    content = stringifyInitiativeTable(result.tracker);
+    log = result.log;
+  }
+  await logseq.Editor.updateBlock(e.uuid, content);
+  if (log.length === 0) {
+    logseq.UI.showMsg('Nothing to recover', 'success');
+    return;
+  }
+  await logseq.Editor.insertBlock(e.uuid, log.join('\n'), { sibling: false });
+}
+
+export const shortRest = rest('short');
+
+export const longRest = rest('long');
@@ -0,0 +1,17 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { findSpell, stringifySpellToMarkdown } from "../utils";
+
+// spellCard replaces a block holding a spell's name with the SRD spell card.
+export const spellCard: BlockCommandCallback = async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const name = block.content.trim();
+  const spell = await findSpell(name);
+  if (!spell) {
+    logseq.UI.showMsg(`No SRD spell named ${name}`, 'warning');
+    return;
+  }
+  await logseq.Editor.updateBlock(e.uuid, stringifySpellToMarkdown(spell));
+}
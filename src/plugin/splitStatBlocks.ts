@@ -0,0 +1,29 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { convertCreaturePage } from "../utils";
+
+// splitStatBlocks turns a block holding many stat blocks, such as a pasted
+// bestiary, into a child block for each creature, and reports the stat
+// blocks that are missing something.
+export const splitStatBlocks: BlockCommandCallback = async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const creatures = convertCreaturePage(block.content, 'markdown');
+  if (creatures.length === 0) {
+    logseq.UI.showMsg('No stat blocks found', 'warning');
+    return;
+  }
+
+  const converted = creatures.filter((creature) => creature.output);
+  await logseq.Editor.insertBatchBlock(e.uuid, converted.map((creature) => ({ content: creature.output!.trim() })), { sibling: false });
+
+  const problems = creatures.filter((creature) => creature.errors?.length);
+  problems.forEach((creature) => console.warn(`Odyssey: line ${creature.line} ${creature.name}: ${creature.errors!.join(', ')}`));
+  if (problems.length > 0) {
+    const names = problems.map((creature) => `${creature.name || `line ${creature.line}`} (${creature.errors!.join(', ')})`);
+    logseq.UI.showMsg(`Split ${converted.length} stat blocks. Check: ${names.join('; ')}`, 'warning');
+  } else {
+    logseq.UI.showMsg(`Split ${converted.length} stat blocks`);
+  }
+}
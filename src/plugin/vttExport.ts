@@ -0,0 +1,79 @@
+import { BlockCommandCallback } from "@logseq/libs/dist/LSPlugin";
+import { Creature } from "../types";
+import {
+  parseCreatureStatBlock,
+  parseFantasyStatblock,
+  parseFiveETools,
+  parseFoundry,
+  resolveStatBlock,
+  stringifyCreatureToFantasyStatblock,
+  stringifyCreatureToFiveETools,
+  stringifyCreatureToFoundry,
+  stringifyCreatureToMarkdown,
+} from "../utils";
+
+const exportStatBlock = (stringify: (creature: Creature) => string, format: string, fence: string): BlockCommandCallback => async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const exported = stringify(parseCreatureStatBlock(block.content));
+  if (!exported) {
+    logseq.UI.showMsg(`Could not export the stat block to ${format}`, 'error');
+    return;
+  }
+  await logseq.Editor.insertBlock(e.uuid, "```" + fence + "\n" + exported.trim() + "\n```", { sibling: false });
+}
+
+export const exportStatBlockToFiveETools = exportStatBlock(stringifyCreatureToFiveETools, '5etools', 'json');
+
+export const exportStatBlockToFoundry = exportStatBlock(stringifyCreatureToFoundry, 'Foundry', 'json');
+
+export const exportStatBlockToFantasyStatblock = exportStatBlock(stringifyCreatureToFantasyStatblock, 'Fantasy Statblocks', 'statblock');
+
+// importStatBlock replaces a block holding 5etools or Foundry JSON, or Fantasy
+// Statblocks YAML, with the equivalent stat block. Foundry actors are
+// recognised by their system data.
+export const importStatBlock: BlockCommandCallback = async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const content = block.content.replace(/^```\w*\n?/, '').replace(/\n?```$/, '').trim();
+  let creature: Creature | null = null;
+  try {
+    let json: unknown = null;
+    try {
+      json = JSON.parse(content);
+    } catch {
+      json = null;
+    }
+    if (json && typeof json === 'object') {
+      creature = 'system' in json ? parseFoundry(content) : parseFiveETools(content);
+    } else {
+      creature = parseFantasyStatblock(content);
+    }
+  } catch {
+    creature = null;
+  }
+  if (!creature) {
+    logseq.UI.showMsg('The block does not contain 5etools, Foundry or Fantasy Statblocks data', 'error');
+    return;
+  }
+  await logseq.Editor.updateBlock(e.uuid, stringifyCreatureToMarkdown(creature));
+}
+
+// flattenStatBlock replaces a stat block that lists only its changes to a base
+// creature with the full stat block.
+export const flattenStatBlock: BlockCommandCallback = async (e) => {
+  const block = await logseq.Editor.getBlock(e.uuid);
+  if (!block || !block.content) {
+    return;
+  }
+  const creature = await resolveStatBlock(block.content, true);
+  if (!creature) {
+    logseq.UI.showMsg('Could not resolve the base creature', 'error');
+    return;
+  }
+  await logseq.Editor.updateBlock(e.uuid, stringifyCreatureToMarkdown(creature));
+}
@@ -1,12 +1,46 @@
 export interface InitiativeTracker {
   combatants: Combatant[];
   round: number;
+  turn?: number;
+  extraColumns?: string[];
 }
 
 export interface Combatant {
   name: string;
   initiative: number;
   damage: number;
+  reference?: string;
+  armorClass?: number;
+  hitPoints?: number;
+  initiativeBonus?: number;
+  actions?: string[];
+  lair?: boolean;
+  legendaryActions?: number;
+  legendaryActionsMax?: number;
+  resources?: Resource[];
+  conditions?: string[];
+  extra?: Record<string, string>;
+}
+
+export interface CombatantSpec {
+  reference: string;
+  count: number;
+}
+
+export interface Resource {
+  name: string;
+  kind: 'recharge' | 'day' | 'legendary' | 'slot' | 'short' | 'long';
+  remaining: number;
+  max: number;
+  rechargeOn?: number;
+  level?: number;
+}
+
+export interface RechargeRoll {
+  combatant: string;
+  resource: string;
+  roll: number;
+  recharged: boolean;
 }
 
 export interface Action {
@@ -21,6 +55,7 @@ export interface Creature {
   size: 'Tiny' | 'Small' | 'Medium' | 'Large' | 'Huge' | 'Gargantuan';
   alignment: string;
   armorClass: number;
+  armorType?: string;
   hitPoints: string;
   speed?: { // Made optional
     base: number;
@@ -47,12 +82,337 @@ export interface Creature {
   senses?: string;
   languages?: string;
   challengeRating: string;
+  base?: string;
   proficiencyBonus?: number;
   notes?: string;
+  traits?: Action[];
   actions?: Action[];
   bonusActions?: Action[];
   reactions?: Action[];
   legendaryActions?: Action[];
+  legendaryActionCount?: number;
+  legendaryActionsIntro?: string;
+  mythicActions?: Action[];
+  lairActions?: Action[];
+  regionalEffects?: Action[];
   options?: Action[];
   description?: string;
+  extraProperties?: Record<string, Extension>;
+  extraSections?: Record<string, Extension>;
+}
+
+export interface Extension {
+  value: string;
+  after?: string;
+}
+
+export interface FieldChange {
+  field: string;
+  from: unknown;
+  to: unknown;
+}
+
+export interface ActionChange {
+  section: string;
+  kind: 'added' | 'removed' | 'edited';
+  name: string;
+  from?: Action;
+  to?: Action;
+}
+
+export interface CreatureDiff {
+  fields: FieldChange[];
+  actions: ActionChange[];
+}
+
+export interface MergeConflict {
+  field: string;
+  base: unknown;
+  ours: unknown;
+  theirs: unknown;
+}
+
+export interface CreatureTemplate {
+  name: string;
+  title?: string;
+  description?: string;
+  properties?: Record<string, string>;
+  abilityScores?: Record<string, number>;
+  abilityAdjustments?: Record<string, number>;
+  damageVulnerabilities?: string[];
+  damageResistances?: string[];
+  damageImmunities?: string[];
+  conditionImmunities?: string[];
+  senses?: string[];
+  languages?: string[];
+  addTraits?: Action[];
+  addActions?: Action[];
+  addBonusActions?: Action[];
+  addReactions?: Action[];
+  remove?: string[];
+}
+
+export interface ClassLevel {
+  class: string;
+  subclass?: string;
+  level: number;
+}
+
+export interface HitDice {
+  die: number;
+  remaining: number;
+  max: number;
+}
+
+export interface InventoryItem {
+  name: string;
+  quantity: number;
+  equipped?: boolean;
+}
+
+export interface Character {
+  name: string;
+  player?: string;
+  species?: string;
+  background?: string;
+  alignment?: string;
+  classes: ClassLevel[];
+  armorClass: number;
+  speed: number;
+  initiativeBonus: number;
+  hitPoints: number;
+  maxHitPoints: number;
+  temporaryHitPoints?: number;
+  hitDice: HitDice[];
+  exhaustion?: number;
+  abilityScores: Creature['abilityScores'];
+  savingThrows?: string[];
+  skills?: string[];
+  expertise?: string[];
+  armor?: string;
+  weapons?: string;
+  tools?: string;
+  languages?: string;
+  resources?: Resource[];
+  features?: Action[];
+  inventory?: InventoryItem[];
+  notes?: string;
+}
+
+export type RestKind = 'short' | 'long';
+
+export interface PartyMember {
+  name: string;
+  reference?: string;
+  player?: string;
+  level: number;
+  armorClass?: number;
+  hitPoints?: number;
+  initiativeBonus: number;
+  passivePerception?: number;
+  passiveInsight?: number;
+  passiveInvestigation?: number;
+  languages?: string[];
+  xp?: number;
+}
+
+export interface Party {
+  name: string;
+  members: PartyMember[];
+  notes?: string;
+}
+
+export type Difficulty = 'trivial' | 'easy' | 'medium' | 'hard' | 'deadly';
+
+export interface DifficultyRating {
+  thresholds: { easy: number; medium: number; hard: number; deadly: number };
+  xp: number;
+  multiplier: number;
+  adjustedXp: number;
+  difficulty: Difficulty;
+}
+
+export interface Spell {
+  name: string;
+  level: number;
+  school: string;
+  castingTime: string;
+  range: string;
+  components: string[];
+  material?: string;
+  duration: string;
+  concentration?: boolean;
+  ritual?: boolean;
+  classes?: string[];
+  description: string;
+  higherLevels?: string;
+}
+
+export interface SpellFilter {
+  query?: string;
+  class?: string;
+  level?: number;
+  school?: string;
+}
+
+export interface Weapon {
+  category: string;
+  range: string;
+  damage: string;
+  damageType: string;
+  versatile?: string;
+  properties?: string[];
+  normalRange?: number;
+  longRange?: number;
+}
+
+export interface Armor {
+  category: string;
+  baseAc: number;
+  dexBonus?: boolean;
+  maxDexBonus?: number;
+  strengthMinimum?: number;
+  stealthDisadvantage?: boolean;
+}
+
+export interface Equipment {
+  name: string;
+  category: string;
+  itemType?: string;
+  cost?: string;
+  weight?: number;
+  weapon?: Weapon;
+  armor?: Armor;
+  rarity?: string;
+  attunement?: string;
+  description?: string;
+}
+
+export type RuleKind = 'condition' | 'damageType' | 'skill' | 'ability' | 'rule';
+
+export interface Rule {
+  kind: RuleKind;
+  name: string;
+  description: string;
+  ability?: string;
+}
+
+// SearchResult is a hit from the search across the SRD. Kind is "monster",
+// "spell", "item" or a rule kind.
+export interface SearchResult {
+  kind: string;
+  name: string;
+  // reference is the block UUID of a homebrew creature.
+  reference?: string;
+  score: number;
+}
+
+// ContentSourceConfig is a source of SRD-shaped content. Sources earlier in
+// the list take priority over later ones.
+export interface ContentSourceConfig {
+  type: 'http' | 'open5e' | 'homebrew' | 'embedded';
+  name: string;
+  url?: string;
+  data?: string;
+}
+
+export interface CompendiumBlock {
+  uuid: string;
+  page: string;
+  content: string;
+}
+
+export interface CompendiumEntry {
+  uuid: string;
+  page: string;
+  creature: Creature;
+}
+
+export interface CompendiumError {
+  uuid: string;
+  page: string;
+  error: string;
+}
+
+// CompendiumDuplicate is a creature name used by more than one block.
+export interface CompendiumDuplicate {
+  name: string;
+  entries: CompendiumEntry[];
+}
+
+export type CreatureFormat = 'markdown' | '5etools' | 'foundry' | 'fantasyStatblock' | 'homebrewery';
+
+// ParsedCreature is one stat block from a page of them; line is where its
+// heading is on the page.
+export interface ParsedCreature {
+  line: number;
+  creature: Creature;
+  errors?: string[];
+}
+
+export interface ConvertedCreature {
+  line: number;
+  name: string;
+  output?: string;
+  errors?: string[];
+}
+
+export interface EncounterMonster {
+  name: string;
+  // reference is the monster's stat block, e.g. "[[Goblin]]".
+  reference: string;
+  count: number;
+  challenge?: string;
+}
+
+export interface Encounter {
+  name: string;
+  location?: string;
+  party?: string;
+  difficulty?: Difficulty;
+  monsters: EncounterMonster[];
+  treasure?: string;
+  notes?: string;
+  properties?: Record<string, string>;
+}
+
+// TreasureType is a treasure table: "coins" is the individual treasure a
+// creature carries.
+export type TreasureType = 'coins' | 'hoard' | 'gems' | 'artObjects' | 'magicItems';
+
+export interface TreasureRequest {
+  type: TreasureType;
+  // challenge picks the challenge rating band of the coin and hoard tables.
+  challenge?: string;
+  // value is what each gem or art object is worth in gold pieces.
+  value?: number;
+  // table is the magic item table, A to I.
+  table?: string;
+  count?: number;
+}
+
+export interface Coins {
+  cp?: number;
+  sp?: number;
+  ep?: number;
+  gp?: number;
+  pp?: number;
+}
+
+export interface Valuable {
+  name: string;
+  value: number;
+}
+
+export interface MagicItem {
+  name: string;
+  table: string;
+}
+
+export interface Treasure {
+  name?: string;
+  coins: Coins;
+  gems?: Valuable[];
+  artObjects?: Valuable[];
+  magicItems?: MagicItem[];
 }
@@ -1,4 +1,4 @@
-import { Combatant, Creature, Action } from "./types";
+import { Combatant, Creature, Action, InitiativeTracker, RechargeRoll, CombatantSpec, CreatureDiff, MergeConflict, CreatureTemplate, Character, RestKind, Party, DifficultyRating, Spell, SpellFilter, Equipment, Rule, RuleKind, SearchResult, ContentSourceConfig, CompendiumBlock, CompendiumEntry, CompendiumError, CompendiumDuplicate, CreatureFormat, ParsedCreature, ConvertedCreature, Encounter, TreasureRequest, Treasure } from "./types";
 
 declare const odysseyWasm: any;
 
@@ -7,6 +7,167 @@ export function parseInitiativeTable(content: string): { combatants: Combatant[]
     return JSON.parse(result);
 }
 
+export function stringifyInitiativeTable(initiativeTracker: InitiativeTracker): string {
+    return odysseyWasm.stringifyInitiativeTable(JSON.stringify(initiativeTracker));
+}
+
+export function nextInitiativeTurn(initiativeTracker: InitiativeTracker): { tracker: InitiativeTracker; recharges: RechargeRoll[] } {
+    const result = odysseyWasm.nextInitiativeTurn(JSON.stringify(initiativeTracker));
+    return JSON.parse(result);
+}
+
+// nextInitiativeRound skips to the top of the next round, beginning every
+// turn passed on the way.
+export function nextInitiativeRound(initiativeTracker: InitiativeTracker): { tracker: InitiativeTracker; recharges: RechargeRoll[] } {
+    const result = odysseyWasm.nextInitiativeRound(JSON.stringify(initiativeTracker));
+    return JSON.parse(result);
+}
+
+// sortInitiativeTracker puts the combatants in initiative order, keeping the
+// current turn with the same combatant once combat is under way.
+export function sortInitiativeTracker(initiativeTracker: InitiativeTracker): InitiativeTracker {
+    const result = odysseyWasm.sortInitiativeTracker(JSON.stringify(initiativeTracker));
+    return result ? JSON.parse(result) : initiativeTracker;
+}
+
+export function removeCombatant(initiativeTracker: InitiativeTracker, index: number): InitiativeTracker {
+    const result = odysseyWasm.removeCombatant(JSON.stringify(initiativeTracker), index);
+    return result ? JSON.parse(result) : initiativeTracker;
+}
+
+export function spendCombatantResource(initiativeTracker: InitiativeTracker, index: number, resource: string): InitiativeTracker {
+    const result = odysseyWasm.spendCombatantResource(JSON.stringify(initiativeTracker), index, resource);
+    return result ? JSON.parse(result) : initiativeTracker;
+}
+
+// applyCondition gives a combatant a condition, returning the rules text of
+// the condition when it is a standard one.
+export function applyCondition(initiativeTracker: InitiativeTracker, index: number, condition: string): { tracker: InitiativeTracker; rule?: Rule } {
+    const result = odysseyWasm.applyCondition(JSON.stringify(initiativeTracker), index, condition);
+    return result ? JSON.parse(result) : { tracker: initiativeTracker };
+}
+
+export function removeCondition(initiativeTracker: InitiativeTracker, index: number, condition: string): InitiativeTracker {
+    const result = odysseyWasm.removeCondition(JSON.stringify(initiativeTracker), index, condition);
+    return result ? JSON.parse(result) : initiativeTracker;
+}
+
+export function addCreatureToInitiative(initiativeTracker: InitiativeTracker, statBlock: string, initiative: number): InitiativeTracker {
+    const result = odysseyWasm.addCreatureToInitiative(JSON.stringify(initiativeTracker), statBlock, initiative);
+    return JSON.parse(result);
+}
+
+export function addCharacterToInitiative(initiativeTracker: InitiativeTracker, characterSheet: string, initiative: number): InitiativeTracker {
+    const result = odysseyWasm.addCharacterToInitiative(JSON.stringify(initiativeTracker), characterSheet, initiative);
+    return JSON.parse(result);
+}
+
+export function parseCharacterSheet(content: string): Character {
+    const result = odysseyWasm.parseCharacterSheet(content);
+    return JSON.parse(result);
+}
+
+export function stringifyCharacterToMarkdown(character: Character): string {
+    return odysseyWasm.stringifyCharacterToMarkdown(JSON.stringify(character));
+}
+
+// restCharacter gives the character on a sheet a rest, spending up to
+// maxHitDice hit dice on a short rest.
+export function restCharacter(characterSheet: string, kind: RestKind, maxHitDice: number): { sheet: string; log: string[] } {
+    const result = odysseyWasm.restCharacter(characterSheet, kind, maxHitDice);
+    return JSON.parse(result);
+}
+
+export function restInitiative(initiativeTracker: InitiativeTracker, kind: RestKind): { tracker: InitiativeTracker; log: string[] } {
+    const result = odysseyWasm.restInitiative(JSON.stringify(initiativeTracker), kind);
+    return JSON.parse(result);
+}
+
+export function parseParty(content: string): Party {
+    const result = odysseyWasm.parseParty(content);
+    return JSON.parse(result);
+}
+
+export function stringifyPartyToMarkdown(party: Party): string {
+    return odysseyWasm.stringifyPartyToMarkdown(JSON.stringify(party));
+}
+
+export function addPartyToInitiative(initiativeTracker: InitiativeTracker, party: string): InitiativeTracker {
+    const result = odysseyWasm.addPartyToInitiative(JSON.stringify(initiativeTracker), party);
+    return JSON.parse(result);
+}
+
+// encounterDifficulty rates monsters of the given challenge ratings against
+// the party on a party block.
+export function encounterDifficulty(party: string, challenges: string[]): DifficultyRating {
+    const result = odysseyWasm.encounterDifficulty(party, JSON.stringify(challenges));
+    return JSON.parse(result);
+}
+
+// isParty tells a party block by its Member column.
+export function isParty(content: string): boolean {
+    return /^\|\s*Member\s*\|/m.test(content);
+}
+
+// isCharacterSheet tells a player character sheet from a creature stat block
+// by its Class row.
+export function isCharacterSheet(content: string): boolean {
+    return /\|\s*\*\*Class\*\*\s*\|/.test(content);
+}
+
+export function parseCombatantSpec(input: string): CombatantSpec {
+    return JSON.parse(odysseyWasm.parseCombatantSpec(input));
+}
+
+export function addFromStatBlock(initiativeTracker: InitiativeTracker, statBlock: string, spec: CombatantSpec, rollHP: boolean): InitiativeTracker {
+    const result = odysseyWasm.addFromStatBlock(JSON.stringify(initiativeTracker), statBlock, JSON.stringify(spec), rollHP);
+    return result ? JSON.parse(result) : initiativeTracker;
+}
+
+export async function fetchReferenceContent(reference: string): Promise<string | null> {
+    const blockMatch = reference.match(/^\(\((.+)\)\)$/);
+    if (blockMatch) {
+        const block = await logseq.Editor.getBlock(blockMatch[1]);
+        return block?.content ?? null;
+    }
+    const pageMatch = reference.match(/^\[\[(.+)\]\]$/);
+    if (pageMatch) {
+        const blocks = await logseq.Editor.getPageBlocksTree(pageMatch[1]);
+        const statBlock = blocks?.find((b) => hasStatBlock(b.content ?? '') || isParty(b.content ?? ''));
+        return statBlock?.content ?? null;
+    }
+    return null;
+}
+
+// hasStatBlock asks the stat block parser whether the content names a
+// creature or character and has a property table, however it is aligned.
+function hasStatBlock(content: string): boolean {
+    const creature = parseCreatureStatBlock(content);
+    return !!creature?.name && (!!creature.armorClass || !!creature.hitPoints || !!creature.base || !!creature.extraProperties);
+}
+
+// resolveStatBlock fills in what a stat block inherits from its base
+// creatures, fetching each base in turn. With flatten the result no longer
+// refers to its base.
+export async function resolveStatBlock(content: string, flatten: boolean): Promise<Creature | null> {
+    const bases: Record<string, string> = {};
+    let base = parseCreatureStatBlock(content).base;
+    while (base) {
+        const reference = parseCombatantSpec(base).reference;
+        if (reference in bases) {
+            break;
+        }
+        const baseContent = await fetchReferenceContent(reference);
+        if (!baseContent) {
+            break;
+        }
+        bases[reference] = baseContent;
+        base = parseCreatureStatBlock(baseContent).base;
+    }
+    const result = odysseyWasm.resolveCreatureStatBlock(content, JSON.stringify(bases), flatten);
+    return result ? JSON.parse(result) : null;
+}
+
 export function parseCreatureStatBlock(content: string): Creature {
     const result = odysseyWasm.parseCreatureStatBlock(content);
     return JSON.parse(result);
@@ -15,3 +176,239 @@ export function parseCreatureStatBlock(content: string): Creature {
 export function stringifyCreatureToMarkdown(creature: Creature): string {
     return odysseyWasm.stringifyCreatureToMarkdown(JSON.stringify(creature));
 }
+
+export function parseHomebrewery(content: string): Creature {
+    const result = odysseyWasm.parseHomebrewery(content);
+    return JSON.parse(result);
+}
+
+export function stringifyCreatureToHomebrewery(creature: Creature): string {
+    return odysseyWasm.stringifyCreatureToHomebrewery(JSON.stringify(creature));
+}
+
+export function parseFiveETools(content: string): Creature {
+    const result = odysseyWasm.parseFiveETools(content);
+    return JSON.parse(result);
+}
+
+export function stringifyCreatureToFiveETools(creature: Creature): string {
+    return odysseyWasm.stringifyCreatureToFiveETools(JSON.stringify(creature));
+}
+
+export function parseFoundry(content: string): Creature {
+    const result = odysseyWasm.parseFoundry(content);
+    return JSON.parse(result);
+}
+
+export function stringifyCreatureToFoundry(creature: Creature): string {
+    return odysseyWasm.stringifyCreatureToFoundry(JSON.stringify(creature));
+}
+
+export function parseFantasyStatblock(content: string): Creature {
+    const result = odysseyWasm.parseFantasyStatblock(content);
+    return JSON.parse(result);
+}
+
+export function stringifyCreatureToFantasyStatblock(creature: Creature): string {
+    return odysseyWasm.stringifyCreatureToFantasyStatblock(JSON.stringify(creature));
+}
+
+export function renderCreatureHTML(creature: Creature, layout: 'card' | 'print'): string {
+    return odysseyWasm.renderCreatureHTML(JSON.stringify(creature), layout);
+}
+
+export function diffCreatures(from: Creature, to: Creature): CreatureDiff {
+    const result = odysseyWasm.diffCreatures(JSON.stringify(from), JSON.stringify(to));
+    return JSON.parse(result);
+}
+
+export function mergeCreatures(base: Creature, ours: Creature, theirs: Creature): { creature: Creature; conflicts: MergeConflict[] } {
+    const result = odysseyWasm.mergeCreatures(JSON.stringify(base), JSON.stringify(ours), JSON.stringify(theirs));
+    return JSON.parse(result);
+}
+
+export function listCreatureTemplates(): CreatureTemplate[] {
+    const result = odysseyWasm.listCreatureTemplates();
+    return JSON.parse(result);
+}
+
+// applyCreatureTemplate takes the name of a built-in template or a template of
+// your own.
+export function applyCreatureTemplate(creature: Creature, template: string | CreatureTemplate): Creature {
+    const arg = typeof template === 'string' ? template : JSON.stringify(template);
+    const result = odysseyWasm.applyCreatureTemplate(JSON.stringify(creature), arg);
+    return JSON.parse(result);
+}
+
+export function parseImprovedInitiative(content: string): InitiativeTracker {
+    const result = odysseyWasm.parseImprovedInitiative(content);
+    return JSON.parse(result);
+}
+
+export async function findSpell(name: string): Promise<Spell | null> {
+    const result = await odysseyWasm.findSpell(name);
+    return result ? JSON.parse(result) : null;
+}
+
+// searchSpells looks through the SRD spells by name or description, narrowed
+// by class, level and school.
+export async function searchSpells(filter: SpellFilter): Promise<Spell[]> {
+    const result = await odysseyWasm.searchSpells(JSON.stringify(filter));
+    return result ? JSON.parse(result) : [];
+}
+
+export function parseSpellCard(content: string): Spell {
+    const result = odysseyWasm.parseSpellCard(content);
+    return JSON.parse(result);
+}
+
+export function stringifySpellToMarkdown(spell: Spell): string {
+    return odysseyWasm.stringifySpellToMarkdown(JSON.stringify(spell));
+}
+
+export async function findEquipment(name: string): Promise<Equipment | null> {
+    const result = await odysseyWasm.findEquipment(name);
+    return result ? JSON.parse(result) : null;
+}
+
+// searchEquipment looks through the SRD equipment and magic items by name,
+// optionally within a category such as "Weapon" or "Armor".
+export async function searchEquipment(query: string, category = ''): Promise<Equipment[]> {
+    const result = await odysseyWasm.searchEquipment(query, category);
+    return result ? JSON.parse(result) : [];
+}
+
+export function stringifyEquipmentToMarkdown(equipment: Equipment): string {
+    return odysseyWasm.stringifyEquipmentToMarkdown(JSON.stringify(equipment));
+}
+
+// addWeaponAttack adds the attack for an SRD weapon to the creature's
+// actions, with to-hit and damage worked out from its ability scores.
+export async function addWeaponAttack(creature: Creature, weapon: string): Promise<Creature | null> {
+    const result = await odysseyWasm.addWeaponAttack(JSON.stringify(creature), weapon);
+    return result ? JSON.parse(result) : null;
+}
+
+// searchRules searches the text of the conditions, damage types, skills,
+// abilities and rule sections.
+export function searchRules(query: string): Rule[] {
+    const result = odysseyWasm.searchRules(query);
+    return result ? JSON.parse(result) : [];
+}
+
+export function listRules(kind?: RuleKind): Rule[] {
+    const result = odysseyWasm.listRules(kind ?? '');
+    return result ? JSON.parse(result) : [];
+}
+
+export function findRule(kind: RuleKind | '', name: string): Rule | null {
+    const result = odysseyWasm.findRule(kind, name);
+    return result ? JSON.parse(result) : null;
+}
+
+// refreshRules replaces the built-in rules reference with the latest SRD
+// data, keeping the built-in one if it cannot be fetched.
+export async function refreshRules(): Promise<boolean> {
+    return await odysseyWasm.refreshRules();
+}
+
+// searchAll searches the monsters, spells, equipment and rules together.
+// Quote words to search for them as a phrase.
+export function searchAll(query: string, limit = 20): SearchResult[] {
+    const result = odysseyWasm.searchAll(query, limit);
+    return result ? JSON.parse(result) : [];
+}
+
+// ingestCompendium reads homebrew stat blocks into the compendium, replacing
+// what was read from the same blocks before.
+export function ingestCompendium(blocks: CompendiumBlock[]): { errors: CompendiumError[]; duplicates: CompendiumDuplicate[] } {
+    const result = odysseyWasm.ingestCompendium(JSON.stringify(blocks));
+    return result ? JSON.parse(result) : { errors: [], duplicates: [] };
+}
+
+export function removeFromCompendium(...uuids: string[]) {
+    odysseyWasm.removeFromCompendium(...uuids);
+}
+
+export function searchCompendium(query: string, limit = 20): SearchResult[] {
+    const result = odysseyWasm.searchCompendium(query, limit);
+    return result ? JSON.parse(result) : [];
+}
+
+export function findCompendiumCreature(name: string): CompendiumEntry | null {
+    const result = odysseyWasm.findCompendiumCreature(name);
+    return result ? JSON.parse(result) : null;
+}
+
+// parseCreaturePage splits a page of stat blocks, such as a pasted bestiary,
+// into a creature for each ### heading.
+export function parseCreaturePage(content: string): ParsedCreature[] {
+    const result = odysseyWasm.parseCreaturePage(content);
+    return result ? JSON.parse(result) : [];
+}
+
+export function convertCreaturePage(content: string, format: CreatureFormat): ConvertedCreature[] {
+    const result = odysseyWasm.convertCreaturePage(content, format);
+    return result ? JSON.parse(result) : [];
+}
+
+export function parseEncounter(content: string): Encounter {
+    const result = odysseyWasm.parseEncounter(content);
+    return JSON.parse(result);
+}
+
+export function stringifyEncounterToMarkdown(encounter: Encounter): string {
+    return odysseyWasm.stringifyEncounterToMarkdown(JSON.stringify(encounter));
+}
+
+// rateEncounter fills in the monsters' challenge ratings from their stat
+// blocks, keyed by reference, and the encounter's expected difficulty for the
+// party.
+export function rateEncounter(encounter: string, party: string, statBlocks: Record<string, string>): { encounter: Encounter; rating: DifficultyRating } | null {
+    const result = odysseyWasm.rateEncounter(encounter, party, JSON.stringify(statBlocks));
+    return result ? JSON.parse(result) : null;
+}
+
+// launchEncounter sets up an initiative tracker for the encounter, rolling
+// the dice from the seed.
+export function launchEncounter(encounter: string, statBlocks: Record<string, string>, party: string, rollHP: boolean, seed: number): InitiativeTracker | null {
+    const result = odysseyWasm.launchEncounter(encounter, JSON.stringify(statBlocks), party, rollHP, seed);
+    return result ? JSON.parse(result) : null;
+}
+
+// rollTreasure rolls on a treasure table, rolling the dice from the seed, and
+// returns the treasure with its loot block.
+export function rollTreasure(request: TreasureRequest, seed: number): { treasure: Treasure; markdown: string } | null {
+    const result = odysseyWasm.rollTreasure(JSON.stringify(request), seed);
+    return result ? JSON.parse(result) : null;
+}
+
+// rollLoot rolls the treasure for the defeated combatants in an initiative
+// table from their stat blocks' challenge ratings, keyed by reference.
+export function rollLoot(initiativeTable: string, statBlocks: Record<string, string>, hoard: boolean, seed: number): { challenges: string[]; treasure: Treasure; markdown: string } | null {
+    const result = odysseyWasm.rollLoot(initiativeTable, JSON.stringify(statBlocks), hoard, seed);
+    return result ? JSON.parse(result) : null;
+}
+
+// isEncounter tells an encounter block by its Monster column.
+export function isEncounter(content: string): boolean {
+    return /^\|\s*Monster\s*\|/m.test(content);
+}
+
+// isStatBlock tells a creature stat block by its name heading and Armor
+// Class row.
+export function isStatBlock(content: string): boolean {
+    return /^### /m.test(content) && /\|\s*\*\*Armor Class\*\*\s*\|/.test(content) && !isCharacterSheet(content);
+}
+
+// setFetchCache gives the WASM module a persistent store for the SRD data it
+// downloads. get and set resolve to JSON strings.
+export function setFetchCache(store: { get(key: string): Promise<string | null>; set(key: string, value: string): Promise<void> }): boolean {
+    return odysseyWasm.setFetchCache(store);
+}
+
+// setContentSources replaces where monsters, spells and equipment are looked
+// up, in priority order.
+export function setContentSources(sources: ContentSourceConfig[]): boolean {
+    return odysseyWasm.setContentSources(JSON.stringify(sources));
+}